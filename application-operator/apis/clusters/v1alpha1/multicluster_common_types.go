@@ -3,13 +3,24 @@
 
 package v1alpha1
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
 
 // This file contains common types and functions used by all MultiCluster Custom Resource Types
 
-// Placement information for multi cluster resources
+// Placement information for multi cluster resources.  The resource is placed in the clusters of the list, and in
+// the clusters that are selected by the cluster selector.
 type Placement struct {
-	Clusters []Cluster `json:"clusters"`
+	// The clusters in which the resource is placed
+	// +optional
+	Clusters []Cluster `json:"clusters,omitempty"`
+	// Selects the clusters in which the resource is placed, by the labels of the VerrazzanoManagedCluster
+	// resources of the clusters
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
 }
 
 // Cluster where multi cluster resources are placed
@@ -18,6 +29,42 @@ type Cluster struct {
 	Name string `json:"name"`
 }
 
+// ClusterOverride is a patch that is applied to the template of a multi cluster resource in the clusters that are
+// named by the override, or selected by its cluster selector, before the resource is created in the clusters
+type ClusterOverride struct {
+	// The name of the cluster in which the patch is applied
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+	// Selects the clusters in which the patch is applied, by the labels of the VerrazzanoManagedCluster
+	// resources of the clusters
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+	// The type of the patch, one of json, merge or strategic.  The default is merge.  A strategic patch cannot
+	// patch the fields of an embedded resource, such as the workload of a component.
+	// +optional
+	PatchType PatchType `json:"patchType,omitempty"`
+	// The patch that is applied to the template.  A json patch is a list of operations, and a merge or strategic
+	// patch is an object with the fields of the template to change.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Patch runtime.RawExtension `json:"patch"`
+}
+
+// PatchType identifies the type of the patch of a cluster override
+// +kubebuilder:validation:Enum=json;merge;strategic
+type PatchType string
+
+const (
+	// JSONPatchType is a JSON patch, as defined by RFC 6902
+	JSONPatchType PatchType = "json"
+
+	// MergePatchType is a JSON merge patch, as defined by RFC 7386
+	MergePatchType PatchType = "merge"
+
+	// StrategicMergePatchType is a Kubernetes strategic merge patch
+	StrategicMergePatchType PatchType = "strategic"
+)
+
 // Condition describes current state of a multi cluster resource.
 type Condition struct {
 	// Type of condition.
@@ -32,6 +79,19 @@ type Condition struct {
 	Message string `json:"message,omitempty"`
 }
 
+// ClusterLevelStatus describes the status of a multi cluster resource in one of the clusters where it is placed
+type ClusterLevelStatus struct {
+	// Name of the cluster
+	Name string `json:"name"`
+	// State of the resource in the cluster
+	State StateType `json:"state"`
+	// Message with the details of the state
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Last time the state or message of the resource in the cluster changed
+	LastUpdateTime string `json:"lastUpdateTime"`
+}
+
 // ConditionType identifies the condition of the multi-cluster resource which can be checked with kubectl wait
 type ConditionType string
 
@@ -50,6 +110,9 @@ const (
 type StateType string
 
 const (
+	// Pending is the state when deploy to specified cluster has not started
+	Pending StateType = "Pending"
+
 	// Deploying is the state when deploy to specified cluster is in progress
 	Deploying StateType = "Deploying"
 
@@ -58,4 +121,8 @@ const (
 
 	// Failed is the state when deploy to specified cluster has failed
 	Failed StateType = "Failed"
+
+	// WaitingForDependencies is the state when deploy to specified cluster is waiting for the objects that the
+	// resource depends on to be deployed to the cluster
+	WaitingForDependencies StateType = "WaitingForDependencies"
 )
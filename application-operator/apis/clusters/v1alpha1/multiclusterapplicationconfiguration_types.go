@@ -15,6 +15,10 @@ type MultiClusterApplicationConfigurationSpec struct {
 
 	// Clusters in which the secret is to be placed
 	Placement Placement `json:"placement"`
+
+	// Patches that are applied to the template in the clusters that are selected by the overrides
+	// +optional
+	Overrides []ClusterOverride `json:"overrides,omitempty"`
 }
 
 // ApplicationConfigurationTemplate has the metadata and spec of the underlying
@@ -32,6 +36,10 @@ type MultiClusterApplicationConfigurationStatus struct {
 
 	// State of the MultiClusterApplicationConfiguration custom resource
 	State StateType `json:"state,omitempty"`
+
+	// Status of the resource in each cluster where it is placed, reported by the multi-cluster agent of
+	// the cluster
+	Clusters []ClusterLevelStatus `json:"clusters,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -15,6 +15,10 @@ type MultiClusterComponentSpec struct {
 
 	// Clusters in which the secret is to be placed
 	Placement Placement `json:"placement"`
+
+	// Patches that are applied to the template in the clusters that are selected by the overrides
+	// +optional
+	Overrides []ClusterOverride `json:"overrides,omitempty"`
 }
 
 // ComponentTemplate has the metadata and spec of the underlying OAM component
@@ -31,6 +35,10 @@ type MultiClusterComponentStatus struct {
 
 	// State of the MultiClusterComponent custom resource
 	State StateType `json:"state,omitempty"`
+
+	// Status of the resource in each cluster where it is placed, reported by the multi-cluster agent of
+	// the cluster
+	Clusters []ClusterLevelStatus `json:"clusters,omitempty"`
 }
 
 // +kubebuilder:object:root=true
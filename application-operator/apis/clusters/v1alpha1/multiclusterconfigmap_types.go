@@ -14,6 +14,10 @@ type MultiClusterConfigMapSpec struct {
 
 	// Clusters in which the ConfigMap is to be placed
 	Placement Placement `json:"placement"`
+
+	// Patches that are applied to the template in the clusters that are selected by the overrides
+	// +optional
+	Overrides []ClusterOverride `json:"overrides,omitempty"`
 }
 
 // ConfigMapTemplate has the metadata and spec of the underlying ConfigMap
@@ -39,6 +43,10 @@ type MultiClusterConfigMapStatus struct {
 
 	// State of the MultiClusterConfigMap custom resource
 	State StateType `json:"state,omitempty"`
+
+	// Status of the resource in each cluster where it is placed, reported by the multi-cluster agent of
+	// the cluster
+	Clusters []ClusterLevelStatus `json:"clusters,omitempty"`
 }
 
 // +kubebuilder:object:root=true
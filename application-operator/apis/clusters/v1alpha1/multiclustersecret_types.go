@@ -15,6 +15,10 @@ type MultiClusterSecretSpec struct {
 
 	// Clusters in which the secret is to be placed
 	Placement Placement `json:"placement"`
+
+	// Patches that are applied to the template in the clusters that are selected by the overrides
+	// +optional
+	Overrides []ClusterOverride `json:"overrides,omitempty"`
 }
 
 // SecretTemplate has the metadata and spec of the underlying secret
@@ -40,6 +44,10 @@ type MultiClusterSecretStatus struct {
 
 	// State of the MultiClusterSecret custom resource
 	State StateType `json:"state,omitempty"`
+
+	// Status of the resource in each cluster where it is placed, reported by the multi-cluster agent of
+	// the cluster
+	Clusters []ClusterLevelStatus `json:"clusters,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -29,3 +29,8 @@ const ElasticsearchPortData = "elasticsearch-port"
 // ElasticsearchSecretName - the name of the secret in the Verrazzano System namespace,
 // that contains credentials and other details for for the admin cluster's Elasticsearch endpoint
 const ElasticsearchSecretName = "verrazzano-cluster-elasticsearch"
+
+// MCClusterLabelsConfigMap - the name of the config map in the Verrazzano System namespace that contains the labels
+// of this managed cluster's VerrazzanoManagedCluster resource on the admin cluster.  It is maintained by the
+// multi-cluster agent, and used to match the cluster selectors of the multi-cluster resources.
+const MCClusterLabelsConfigMap = "verrazzano-cluster-labels"
@@ -14,6 +14,8 @@ import (
 	"github.com/verrazzano/verrazzano/application-operator/constants"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -83,6 +85,105 @@ func GetConditionAndStateFromResult(err error, opResult controllerutil.Operation
 	return condition, state
 }
 
+// NewClusterLevelStatus returns the status of a multi cluster resource in the given cluster, from the state and
+// conditions set by the controller of the cluster.  The resource is failed in the cluster if syncErr is not nil,
+// meaning that the resource could not be synced to the cluster.
+func NewClusterLevelStatus(clusterName string, state clustersv1alpha1.StateType, conditions []clustersv1alpha1.Condition, syncErr error) clustersv1alpha1.ClusterLevelStatus {
+	clusterStatus := clustersv1alpha1.ClusterLevelStatus{Name: clusterName, State: state}
+	if syncErr != nil {
+		clusterStatus.State = clustersv1alpha1.Failed
+		clusterStatus.Message = syncErr.Error()
+		return clusterStatus
+	}
+	if len(clusterStatus.State) == 0 {
+		clusterStatus.State = clustersv1alpha1.Pending
+	}
+	if len(conditions) > 0 {
+		clusterStatus.Message = conditions[len(conditions)-1].Message
+	}
+	return clusterStatus
+}
+
+// SetClusterLevelStatus adds or replaces the status of a cluster in the list of cluster level statuses.  The last
+// update time is only changed when the state or the message of the cluster changes.  Returns true if the list
+// was changed.
+func SetClusterLevelStatus(clusterStatuses *[]clustersv1alpha1.ClusterLevelStatus, clusterStatus clustersv1alpha1.ClusterLevelStatus) bool {
+	for i, existing := range *clusterStatuses {
+		if existing.Name == clusterStatus.Name {
+			if existing.State == clusterStatus.State && existing.Message == clusterStatus.Message {
+				return false
+			}
+			clusterStatus.LastUpdateTime = time.Now().Format(time.RFC3339)
+			(*clusterStatuses)[i] = clusterStatus
+			return true
+		}
+	}
+	clusterStatus.LastUpdateTime = time.Now().Format(time.RFC3339)
+	*clusterStatuses = append(*clusterStatuses, clusterStatus)
+	return true
+}
+
+// ComputeEffectiveState returns the overall state of a multi cluster resource from the states of the resource in
+// the clusters it is placed in.  The resource is failed if it failed in any cluster, and ready when it is ready
+// in all the clusters.  The statuses of the clusters the resource is not placed in are ignored.
+func ComputeEffectiveState(placedClusters map[string]bool, clusterStatuses []clustersv1alpha1.ClusterLevelStatus) clustersv1alpha1.StateType {
+	reported := 0
+	ready := 0
+	for _, clusterStatus := range clusterStatuses {
+		if !placedClusters[clusterStatus.Name] {
+			continue
+		}
+		if clusterStatus.State == clustersv1alpha1.Failed {
+			return clustersv1alpha1.Failed
+		}
+		if clusterStatus.State == clustersv1alpha1.Ready {
+			ready++
+		}
+		if clusterStatus.State != clustersv1alpha1.Pending {
+			reported++
+		}
+	}
+	if ready > 0 && ready == len(placedClusters) {
+		return clustersv1alpha1.Ready
+	}
+	if reported > 0 {
+		return clustersv1alpha1.Deploying
+	}
+	return clustersv1alpha1.Pending
+}
+
+// GetPlacedClusters returns the names of the clusters a multi cluster resource is placed in, which are the
+// clusters in the list of the placement and the clusters whose labels match the cluster selector of the placement.
+// The labels of the clusters are keyed by cluster name.
+func GetPlacedClusters(placement clustersv1alpha1.Placement, clusterLabels map[string]map[string]string) map[string]bool {
+	placedClusters := map[string]bool{}
+	for _, placementCluster := range placement.Clusters {
+		placedClusters[placementCluster.Name] = true
+	}
+	for clusterName, labels := range clusterLabels {
+		if isSelectedCluster(placement.ClusterSelector, labels) {
+			placedClusters[clusterName] = true
+		}
+	}
+	return placedClusters
+}
+
+// PruneClusterLevelStatus removes the statuses of the clusters a multi cluster resource is no longer placed in
+// from the list of cluster level statuses.  Returns true if the list was changed.
+func PruneClusterLevelStatus(clusterStatuses *[]clustersv1alpha1.ClusterLevelStatus, placedClusters map[string]bool) bool {
+	var kept []clustersv1alpha1.ClusterLevelStatus
+	for _, clusterStatus := range *clusterStatuses {
+		if placedClusters[clusterStatus.Name] {
+			kept = append(kept, clusterStatus)
+		}
+	}
+	if len(kept) == len(*clusterStatuses) {
+		return false
+	}
+	*clusterStatuses = kept
+	return true
+}
+
 // NewScheme creates a new scheme that includes this package's object to use for testing
 func NewScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
@@ -91,7 +192,8 @@ func NewScheme() *runtime.Scheme {
 }
 
 // IsPlacedInThisCluster determines whether the given Placement represents placement in the current
-// cluster. Current cluster's identity is determined from the verrazzano-cluster secret
+// cluster. Current cluster's identity is determined from the verrazzano-cluster secret, and its labels from
+// the verrazzano-cluster-labels config map
 func IsPlacedInThisCluster(ctx context.Context, rdr client.Reader, placement clustersv1alpha1.Placement) bool {
 	var clusterSecret corev1.Secret
 
@@ -100,13 +202,53 @@ func IsPlacedInThisCluster(ctx context.Context, rdr client.Reader, placement clu
 		return false
 	}
 	thisCluster := string(clusterSecret.Data[constants.ClusterNameData])
+
+	// The labels are only needed to match the cluster selector
+	var clusterLabels map[string]string
+	if placement.ClusterSelector != nil {
+		var labelsConfigMap corev1.ConfigMap
+		err = rdr.Get(ctx, types.NamespacedName{Namespace: constants.VerrazzanoSystemNamespace, Name: constants.MCClusterLabelsConfigMap}, &labelsConfigMap)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return false
+		}
+		clusterLabels = labelsConfigMap.Data
+	}
+
+	return IsPlacedInCluster(placement, thisCluster, clusterLabels)
+}
+
+// IsPlacedInCluster determines whether the given Placement represents placement in the cluster with the given
+// name and labels.  The labels are the labels of the VerrazzanoManagedCluster resource of the cluster, which
+// are matched against the cluster selector of the placement.
+func IsPlacedInCluster(placement clustersv1alpha1.Placement, clusterName string, clusterLabels map[string]string) bool {
 	for _, placementCluster := range placement.Clusters {
-		if thisCluster == placementCluster.Name {
+		if clusterName == placementCluster.Name {
 			return true
 		}
 	}
+	return isSelectedCluster(placement.ClusterSelector, clusterLabels)
+}
+
+// IsOverrideForCluster returns true if the cluster override applies to the cluster with the given name and labels,
+// because the cluster is named by the override, or its labels match the cluster selector of the override
+func IsOverrideForCluster(override clustersv1alpha1.ClusterOverride, clusterName string, clusterLabels map[string]string) bool {
+	if len(override.ClusterName) > 0 && override.ClusterName == clusterName {
+		return true
+	}
+	return isSelectedCluster(override.ClusterSelector, clusterLabels)
+}
 
-	return false
+// isSelectedCluster returns true if the cluster labels match the cluster selector.  A nil selector selects no
+// clusters.
+func isSelectedCluster(clusterSelector *metav1.LabelSelector, clusterLabels map[string]string) bool {
+	if clusterSelector == nil {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(clusterSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(clusterLabels))
 }
 
 // IgnoreNotFoundWithLog returns nil if err is a "Not Found" error, and if not, logs an error
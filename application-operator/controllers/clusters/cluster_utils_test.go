@@ -0,0 +1,187 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package clusters
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	asserts "github.com/stretchr/testify/assert"
+	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	"github.com/verrazzano/verrazzano/application-operator/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestNewClusterLevelStatus tests the NewClusterLevelStatus function
+// GIVEN the status of a multi cluster resource set by the controller of a cluster
+// WHEN the cluster level status is created
+// THEN the status has the state and the message of the latest condition, or the sync error
+func TestNewClusterLevelStatus(t *testing.T) {
+	assert := asserts.New(t)
+
+	conditions := []clustersv1alpha1.Condition{{Message: "first"}, {Message: "latest"}}
+	clusterStatus := NewClusterLevelStatus("cluster1", clustersv1alpha1.Ready, conditions, nil)
+	assert.Equal(clustersv1alpha1.ClusterLevelStatus{Name: "cluster1", State: clustersv1alpha1.Ready, Message: "latest"}, clusterStatus)
+
+	clusterStatus = NewClusterLevelStatus("cluster1", "", nil, nil)
+	assert.Equal(clustersv1alpha1.Pending, clusterStatus.State)
+
+	clusterStatus = NewClusterLevelStatus("cluster1", clustersv1alpha1.Ready, conditions, errors.New("sync failed"))
+	assert.Equal(clustersv1alpha1.Failed, clusterStatus.State)
+	assert.Equal("sync failed", clusterStatus.Message)
+}
+
+// TestSetClusterLevelStatus tests the SetClusterLevelStatus function
+// GIVEN a list of cluster level statuses
+// WHEN the status of a cluster is set
+// THEN the status is added or replaced, and the list is only changed when the state or message changes
+func TestSetClusterLevelStatus(t *testing.T) {
+	assert := asserts.New(t)
+
+	var clusterStatuses []clustersv1alpha1.ClusterLevelStatus
+	assert.True(SetClusterLevelStatus(&clusterStatuses, clustersv1alpha1.ClusterLevelStatus{Name: "cluster1", State: clustersv1alpha1.Deploying}))
+	assert.True(SetClusterLevelStatus(&clusterStatuses, clustersv1alpha1.ClusterLevelStatus{Name: "cluster2", State: clustersv1alpha1.Ready}))
+	assert.Len(clusterStatuses, 2)
+	assert.NotEmpty(clusterStatuses[0].LastUpdateTime)
+
+	clusterStatuses[0].LastUpdateTime = "unchanged"
+	assert.False(SetClusterLevelStatus(&clusterStatuses, clustersv1alpha1.ClusterLevelStatus{Name: "cluster1", State: clustersv1alpha1.Deploying}))
+	assert.Equal("unchanged", clusterStatuses[0].LastUpdateTime)
+
+	assert.True(SetClusterLevelStatus(&clusterStatuses, clustersv1alpha1.ClusterLevelStatus{Name: "cluster1", State: clustersv1alpha1.Ready}))
+	assert.Len(clusterStatuses, 2)
+	assert.Equal(clustersv1alpha1.Ready, clusterStatuses[0].State)
+	assert.NotEqual("unchanged", clusterStatuses[0].LastUpdateTime)
+}
+
+// TestComputeEffectiveState tests the ComputeEffectiveState function
+// GIVEN the states of a multi cluster resource in the clusters it is placed in
+// WHEN the overall state is computed
+// THEN the resource is failed if it failed in any cluster, ready if it is ready in all the clusters, and
+//      deploying or pending otherwise
+func TestComputeEffectiveState(t *testing.T) {
+	assert := asserts.New(t)
+
+	placedClusters := map[string]bool{"cluster1": true, "cluster2": true}
+	newStatuses := func(state1 clustersv1alpha1.StateType, state2 clustersv1alpha1.StateType) []clustersv1alpha1.ClusterLevelStatus {
+		return []clustersv1alpha1.ClusterLevelStatus{{Name: "cluster1", State: state1}, {Name: "cluster2", State: state2}}
+	}
+
+	assert.Equal(clustersv1alpha1.Pending, ComputeEffectiveState(placedClusters, nil))
+	assert.Equal(clustersv1alpha1.Pending, ComputeEffectiveState(placedClusters, newStatuses(clustersv1alpha1.Pending, clustersv1alpha1.Pending)))
+	assert.Equal(clustersv1alpha1.Deploying, ComputeEffectiveState(placedClusters, newStatuses(clustersv1alpha1.Ready, clustersv1alpha1.Pending)))
+	assert.Equal(clustersv1alpha1.Ready, ComputeEffectiveState(placedClusters, newStatuses(clustersv1alpha1.Ready, clustersv1alpha1.Ready)))
+	assert.Equal(clustersv1alpha1.Failed, ComputeEffectiveState(placedClusters, newStatuses(clustersv1alpha1.Ready, clustersv1alpha1.Failed)))
+
+	// A cluster that did not report a status yet is not ready
+	assert.Equal(clustersv1alpha1.Deploying, ComputeEffectiveState(placedClusters, newStatuses(clustersv1alpha1.Ready, clustersv1alpha1.Ready)[:1]))
+
+	// The statuses of the clusters the resource is no longer placed in are ignored
+	delete(placedClusters, "cluster2")
+	assert.Equal(clustersv1alpha1.Ready, ComputeEffectiveState(placedClusters, newStatuses(clustersv1alpha1.Ready, clustersv1alpha1.Failed)))
+}
+
+// TestGetPlacedClusters tests the GetPlacedClusters function
+// GIVEN a placement with a list of clusters and a cluster selector
+// WHEN the clusters of the placement are found from the labels of the clusters
+// THEN the clusters in the list and the clusters whose labels match the selector are returned
+func TestGetPlacedClusters(t *testing.T) {
+	assert := asserts.New(t)
+
+	clusterLabels := map[string]map[string]string{
+		"cluster1": nil,
+		"cluster2": {"region": "us-east"},
+		"cluster3": {"region": "us-west"},
+	}
+	placement := clustersv1alpha1.Placement{Clusters: []clustersv1alpha1.Cluster{{Name: "cluster1"}}}
+	assert.Equal(map[string]bool{"cluster1": true}, GetPlacedClusters(placement, clusterLabels))
+
+	placement.ClusterSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"region": "us-east"}}
+	assert.Equal(map[string]bool{"cluster1": true, "cluster2": true}, GetPlacedClusters(placement, clusterLabels))
+}
+
+// TestPruneClusterLevelStatus tests the PruneClusterLevelStatus function
+// GIVEN the cluster level statuses of a multi cluster resource
+// WHEN the statuses are pruned
+// THEN the statuses of the clusters the resource is no longer placed in are removed
+func TestPruneClusterLevelStatus(t *testing.T) {
+	assert := asserts.New(t)
+
+	clusterStatuses := []clustersv1alpha1.ClusterLevelStatus{{Name: "cluster1", State: clustersv1alpha1.Ready}, {Name: "cluster2", State: clustersv1alpha1.Failed}}
+	assert.False(PruneClusterLevelStatus(&clusterStatuses, map[string]bool{"cluster1": true, "cluster2": true}))
+	assert.Len(clusterStatuses, 2)
+
+	assert.True(PruneClusterLevelStatus(&clusterStatuses, map[string]bool{"cluster1": true}))
+	assert.Equal([]clustersv1alpha1.ClusterLevelStatus{{Name: "cluster1", State: clustersv1alpha1.Ready}}, clusterStatuses)
+}
+
+// TestIsPlacedInCluster tests the IsPlacedInCluster function
+// GIVEN a placement with a list of clusters and a cluster selector
+// WHEN the placement is checked for a cluster
+// THEN the resource is placed in the cluster if the cluster is in the list, or its labels match the selector
+func TestIsPlacedInCluster(t *testing.T) {
+	assert := asserts.New(t)
+
+	placement := clustersv1alpha1.Placement{Clusters: []clustersv1alpha1.Cluster{{Name: "cluster1"}}}
+	assert.True(IsPlacedInCluster(placement, "cluster1", nil))
+	assert.False(IsPlacedInCluster(placement, "cluster2", map[string]string{"region": "us-east"}))
+
+	placement.ClusterSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"region": "us-east"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"prod", "staging"}},
+		},
+	}
+	assert.True(IsPlacedInCluster(placement, "cluster1", nil))
+	assert.True(IsPlacedInCluster(placement, "cluster2", map[string]string{"region": "us-east", "tier": "prod"}))
+	assert.False(IsPlacedInCluster(placement, "cluster2", map[string]string{"region": "us-east", "tier": "dev"}))
+	assert.False(IsPlacedInCluster(placement, "cluster2", nil))
+}
+
+// TestIsOverrideForCluster tests the IsOverrideForCluster function
+// GIVEN a cluster override with a cluster name or a cluster selector
+// WHEN the override is checked for a cluster
+// THEN the override applies to the cluster if it names the cluster, or the labels of the cluster match the selector
+func TestIsOverrideForCluster(t *testing.T) {
+	assert := asserts.New(t)
+
+	override := clustersv1alpha1.ClusterOverride{ClusterName: "cluster1"}
+	assert.True(IsOverrideForCluster(override, "cluster1", nil))
+	assert.False(IsOverrideForCluster(override, "cluster2", map[string]string{"region": "us-east"}))
+
+	override = clustersv1alpha1.ClusterOverride{ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "us-east"}}}
+	assert.True(IsOverrideForCluster(override, "cluster2", map[string]string{"region": "us-east"}))
+	assert.False(IsOverrideForCluster(override, "cluster2", map[string]string{"region": "us-west"}))
+
+	// An override without a cluster name or a cluster selector applies to no cluster
+	assert.False(IsOverrideForCluster(clustersv1alpha1.ClusterOverride{}, "", nil))
+}
+
+// TestIsPlacedInThisCluster tests the IsPlacedInThisCluster function
+// GIVEN a managed cluster with the registration secret and the config map with the labels of the cluster
+// WHEN a placement with a cluster selector is checked
+// THEN the resource is placed in this cluster if the labels of the cluster match the selector
+func TestIsPlacedInThisCluster(t *testing.T) {
+	assert := asserts.New(t)
+
+	clusterSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: constants.VerrazzanoSystemNamespace, Name: constants.MCRegistrationSecret},
+		Data:       map[string][]byte{constants.ClusterNameData: []byte("cluster1")},
+	}
+	labelsConfigMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: constants.VerrazzanoSystemNamespace, Name: constants.MCClusterLabelsConfigMap},
+		Data:       map[string]string{"region": "us-east"},
+	}
+	cli := fake.NewFakeClientWithScheme(k8scheme.Scheme, &clusterSecret, &labelsConfigMap)
+
+	placement := clustersv1alpha1.Placement{ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "us-east"}}}
+	assert.True(IsPlacedInThisCluster(context.TODO(), cli, placement))
+
+	placement.ClusterSelector.MatchLabels["region"] = "us-west"
+	assert.False(IsPlacedInThisCluster(context.TODO(), cli, placement))
+}
@@ -49,7 +49,7 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	logger.Info("MultiClusterApplicationConfiguration create or update with underlying OAM applicationconfiguration",
 		"applicationconfiguration", mcAppConfig.Spec.Template.Metadata.Name,
-		"placement", mcAppConfig.Spec.Placement.Clusters[0].Name)
+		"placement", mcAppConfig.Spec.Placement)
 	opResult, err := r.createOrUpdateAppConfig(ctx, mcAppConfig)
 
 	return r.updateStatus(ctx, &mcAppConfig, opResult, err)
@@ -44,7 +44,7 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	logger.Info("MultiClusterComponent create or update with underlying component",
 		"component", mcComp.Spec.Template.Metadata.Name,
-		"placement", mcComp.Spec.Placement.Clusters[0].Name)
+		"placement", mcComp.Spec.Placement)
 	opResult, err := r.createOrUpdateComponent(ctx, mcComp)
 
 	return r.updateStatus(ctx, &mcComp, opResult, err)
@@ -46,7 +46,7 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	logger.Info("MultiClusterConfigMap create or update with underlying ConfigMap",
 		"ConfigMap", mcConfigMap.Spec.Template.Metadata.Name,
-		"placement", mcConfigMap.Spec.Placement.Clusters[0].Name)
+		"placement", mcConfigMap.Spec.Placement)
 	// Immutable ConfigMaps are not supported - we need a webhook to validate, or add the support
 	opResult, err := r.createOrUpdateConfigMap(ctx, mcConfigMap)
 
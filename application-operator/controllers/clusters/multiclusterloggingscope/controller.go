@@ -44,7 +44,7 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	logger.Info("MultiClusterLoggingScope create or update with underlying LoggingScope",
 		"loggingscope", mcLogScope.Spec.Template.Metadata.Name,
-		"placement", mcLogScope.Spec.Placement.Clusters[0].Name)
+		"placement", mcLogScope.Spec.Placement)
 	opResult, err := r.createOrUpdateLoggingScope(ctx, mcLogScope)
 
 	return r.updateStatus(ctx, &mcLogScope, opResult, err)
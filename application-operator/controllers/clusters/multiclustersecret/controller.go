@@ -45,7 +45,7 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 	logger.Info("MultiClusterSecret create or update with underlying secret",
 		"secret", mcSecret.Spec.Template.Metadata.Name,
-		"placement", mcSecret.Spec.Placement.Clusters[0].Name)
+		"placement", mcSecret.Spec.Placement)
 	opResult, err := r.createOrUpdateSecret(ctx, mcSecret)
 
 	return r.updateStatus(ctx, &mcSecret, opResult, err)
@@ -6,6 +6,7 @@ require (
 	github.com/Jeffail/gabs/v2 v2.2.0
 	github.com/crossplane/crossplane-runtime v0.10.0
 	github.com/crossplane/oam-kubernetes-runtime v0.3.2
+	github.com/evanphx/json-patch v4.5.0+incompatible
 	github.com/gertd/go-pluralize v0.1.7
 	github.com/go-logr/logr v0.1.0
 	github.com/golang/mock v1.4.4
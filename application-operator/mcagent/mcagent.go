@@ -16,19 +16,20 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // StartAgent - start the agent thread for syncing multi-cluster objects
-func StartAgent(client client.Client, log logr.Logger) {
+func StartAgent(localClient client.Client, log logr.Logger) {
 	// Wait for the existence of the verrazzano-cluster secret.  It contains the credentials
 	// for connecting to a managed cluster.
 	log.Info("Starting multi-cluster agent")
 	secret := corev1.Secret{}
 
 	for {
-		err := client.Get(context.TODO(), types.NamespacedName{Name: constants.MCRegistrationSecret, Namespace: constants.VerrazzanoSystemNamespace}, &secret)
+		err := localClient.Get(context.TODO(), types.NamespacedName{Name: constants.MCRegistrationSecret, Namespace: constants.VerrazzanoSystemNamespace}, &secret)
 		if err != nil {
 			time.Sleep(60 * time.Second)
 		} else {
@@ -46,7 +47,12 @@ func StartAgent(client client.Client, log logr.Logger) {
 	log.Info(fmt.Sprintf("Found secret named %s in namespace %s for cluster named %q", secret.Name, secret.Namespace, managedClusterName))
 
 	// Create the client for accessing the admin cluster
-	adminClient, err := getAdminClient(&secret)
+	adminConfig, err := getAdminConfig(&secret)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("Failed to get the client config for cluster %q", managedClusterName))
+		return
+	}
+	adminClient, err := client.New(adminConfig, client.Options{Scheme: newAdminScheme()})
 	if err != nil {
 		log.Error(err, fmt.Sprintf("Failed to get the client for cluster %q", managedClusterName))
 		return
@@ -55,7 +61,8 @@ func StartAgent(client client.Client, log logr.Logger) {
 	// Create the synchronization context structure
 	s := &Syncer{
 		AdminClient:        adminClient,
-		LocalClient:        client,
+		AdminConfig:        adminConfig,
+		LocalClient:        localClient,
 		Log:                log,
 		ManagedClusterName: managedClusterName,
 		Context:            context.TODO(),
@@ -66,42 +73,22 @@ func StartAgent(client client.Client, log logr.Logger) {
 	s.StartSync()
 }
 
-// StartSync - start syncing multi-cluster objects
+// StartSync - start syncing multi-cluster objects.  The multi-cluster objects in the project namespaces are
+// watched on the admin cluster and synced as soon as they change.  The watches are restarted when the project
+// namespaces change, and with a backoff when the admin cluster is unreachable.
 func (s *Syncer) StartSync() {
 	s.Log.Info("Starting sync of multi-cluster objects")
 
-	// Periodically loop looking for multi-cluster objects
+	backoff := minReconnectBackoff
 	for {
-		err := s.syncVerrazzanoProjects()
-		if err != nil {
-			s.Log.Error(err, "Error syncing VerrazzanoProject objects")
+		err := s.watchAndSync()
+		if err == nil {
+			backoff = minReconnectBackoff
+			continue
 		}
-
-		// Synchronize objects one namespace at a time
-		for _, namespace := range s.ProjectNamespaces {
-			err = s.syncMCSecretObjects(namespace)
-			if err != nil {
-				s.Log.Error(err, "Error syncing MultiClusterSecret objects")
-			}
-			err = s.syncMCConfigMapObjects(namespace)
-			if err != nil {
-				s.Log.Error(err, "Error syncing MultiClusterConfigMap objects")
-			}
-			err = s.syncMCLoggingScopeObjects(namespace)
-			if err != nil {
-				s.Log.Error(err, "Error syncing MultiClusterLoggingScope objects")
-			}
-			err = s.syncMCComponentObjects(namespace)
-			if err != nil {
-				s.Log.Error(err, "Error syncing MultiClusterComponent objects")
-			}
-			err = s.syncMCApplicationConfigurationObjects(namespace)
-			if err != nil {
-				s.Log.Error(err, "Error syncing MultiClusterApplicationConfiguration objects")
-			}
-		}
-
-		time.Sleep(1 * time.Minute)
+		s.Log.Error(err, fmt.Sprintf("Failed to watch the multi-cluster objects on the admin cluster, reconnecting in %v", backoff))
+		time.Sleep(backoff)
+		backoff = nextReconnectBackoff(backoff)
 	}
 }
 
@@ -122,8 +109,8 @@ func validateClusterSecret(secret *corev1.Secret) error {
 	return nil
 }
 
-// Get the clientset for accessing the admin cluster
-func getAdminClient(secret *corev1.Secret) (client.Client, error) {
+// Get the client config for accessing the admin cluster
+func getAdminConfig(secret *corev1.Secret) (*rest.Config, error) {
 	// Create a temp file that contains the kubeconfig
 	tmpFile, err := ioutil.TempFile("", "kubeconfig")
 	if err != nil {
@@ -136,17 +123,12 @@ func getAdminClient(secret *corev1.Secret) (client.Client, error) {
 		return nil, err
 	}
 
-	config, err := clientcmd.BuildConfigFromFlags("", tmpFile.Name())
-	if err != nil {
-		return nil, err
-	}
+	return clientcmd.BuildConfigFromFlags("", tmpFile.Name())
+}
+
+// Create the scheme of the objects that are read from the admin cluster
+func newAdminScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	_ = clustersv1alpha1.AddToScheme(scheme)
-
-	clientset, err := client.New(config, client.Options{Scheme: scheme})
-	if err != nil {
-		return nil, err
-	}
-
-	return clientset, nil
+	return scheme
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	"github.com/verrazzano/verrazzano/application-operator/controllers/clusters"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -23,12 +24,22 @@ func (s *Syncer) syncMCApplicationConfigurationObjects(namespace string) error {
 	}
 	for _, mcAppConfig := range allAdminMCAppConfigs.Items {
 		if s.isThisCluster(mcAppConfig.Spec.Placement) {
-			_, err := s.createOrUpdateMCAppConfig(mcAppConfig)
+			// Hold back the application configuration until the objects that it depends on exist locally
+			missing, err := s.getMissingDependencies(mcAppConfig)
+			if err == nil && len(missing) > 0 {
+				s.Log.Info(fmt.Sprintf("MultiClusterApplicationConfiguration %s/%s is waiting for its dependencies %v", mcAppConfig.Namespace, mcAppConfig.Name, missing))
+				s.updateMCAppConfigWaitingStatus(mcAppConfig, missing)
+				continue
+			}
+			if err == nil {
+				_, err = s.createOrUpdateMCAppConfig(mcAppConfig)
+			}
 			if err != nil {
 				s.Log.Error(err, "Error syncing object",
 					"MultiClusterApplicationConfiguration",
 					types.NamespacedName{Namespace: mcAppConfig.Namespace, Name: mcAppConfig.Name})
 			}
+			s.updateMCAppConfigStatus(mcAppConfig, err)
 		}
 	}
 
@@ -43,8 +54,8 @@ func (s *Syncer) syncMCApplicationConfigurationObjects(namespace string) error {
 		return nil
 	}
 	for _, mcAppConfig := range allLocalMCAppConfigs.Items {
-		// Delete each MultiClusterApplicationConfiguration object that is not on the admin cluster
-		if !appConfigListContains(&allAdminMCAppConfigs, mcAppConfig.Name, mcAppConfig.Namespace) {
+		// Delete each MultiClusterApplicationConfiguration object that is not on the admin cluster or is no longer placed in this cluster
+		if !s.appConfigPlacedInThisCluster(&allAdminMCAppConfigs, mcAppConfig.Name, mcAppConfig.Namespace) {
 			err := s.LocalClient.Delete(s.Context, &mcAppConfig)
 			if err != nil {
 				s.Log.Error(err, fmt.Sprintf("failed to delete MultiClusterApplicationConfiguration with name %q and namespace %q", mcAppConfig.Name, mcAppConfig.Namespace))
@@ -60,6 +71,12 @@ func (s *Syncer) createOrUpdateMCAppConfig(mcAppConfig clustersv1alpha1.MultiClu
 	mcAppConfigNew.Namespace = mcAppConfig.Namespace
 	mcAppConfigNew.Name = mcAppConfig.Name
 
+	// Apply the overrides for this cluster to the template
+	err := s.applyClusterOverrides(mcAppConfig.Spec.Overrides, &mcAppConfig.Spec.Template)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
 	// Create or update on the local cluster
 	return controllerutil.CreateOrUpdate(s.Context, s.LocalClient, &mcAppConfigNew, func() error {
 		mutateMCAppConfig(mcAppConfig, &mcAppConfigNew)
@@ -67,17 +84,52 @@ func (s *Syncer) createOrUpdateMCAppConfig(mcAppConfig clustersv1alpha1.MultiClu
 	})
 }
 
+// Update the status of this cluster in the MultiClusterApplicationConfiguration on the admin cluster, from the status
+// set by the controller of this cluster
+func (s *Syncer) updateMCAppConfigStatus(mcAppConfig clustersv1alpha1.MultiClusterApplicationConfiguration, syncErr error) {
+	name := types.NamespacedName{Namespace: mcAppConfig.Namespace, Name: mcAppConfig.Name}
+	localMCAppConfig := clustersv1alpha1.MultiClusterApplicationConfiguration{}
+	if syncErr == nil {
+		err := s.LocalClient.Get(s.Context, name, &localMCAppConfig)
+		if err != nil {
+			s.Log.Error(err, "Error getting object", "MultiClusterApplicationConfiguration", name)
+			return
+		}
+	}
+	clusterStatus := clusters.NewClusterLevelStatus(s.ManagedClusterName, localMCAppConfig.Status.State, localMCAppConfig.Status.Conditions, syncErr)
+	err := s.updateAdminClusterStatus(&mcAppConfig, mcAppConfig.Spec.Placement, &mcAppConfig.Status.Clusters, &mcAppConfig.Status.State, clusterStatus)
+	if err != nil {
+		s.Log.Error(err, "Error updating status on the admin cluster", "MultiClusterApplicationConfiguration", name)
+	}
+}
+
+// Update the status of this cluster in the MultiClusterApplicationConfiguration on the admin cluster, with the
+// dependencies that do not exist on this cluster yet
+func (s *Syncer) updateMCAppConfigWaitingStatus(mcAppConfig clustersv1alpha1.MultiClusterApplicationConfiguration, missing []dependency) {
+	clusterStatus := clustersv1alpha1.ClusterLevelStatus{
+		Name:    s.ManagedClusterName,
+		State:   clustersv1alpha1.WaitingForDependencies,
+		Message: waitingForDependenciesMessage(missing),
+	}
+	err := s.updateAdminClusterStatus(&mcAppConfig, mcAppConfig.Spec.Placement, &mcAppConfig.Status.Clusters, &mcAppConfig.Status.State, clusterStatus)
+	if err != nil {
+		s.Log.Error(err, "Error updating status on the admin cluster", "MultiClusterApplicationConfiguration",
+			types.NamespacedName{Namespace: mcAppConfig.Namespace, Name: mcAppConfig.Name})
+	}
+}
+
 func mutateMCAppConfig(mcAppConfig clustersv1alpha1.MultiClusterApplicationConfiguration, mcAppConfigNew *clustersv1alpha1.MultiClusterApplicationConfiguration) {
 	mcAppConfigNew.Spec.Placement = mcAppConfig.Spec.Placement
 	mcAppConfigNew.Spec.Template = mcAppConfig.Spec.Template
 	mcAppConfigNew.Labels = mcAppConfig.Labels
 }
 
-// appConfigListContains returns boolean indicating if the list contains the object with the specified name and namespace
-func appConfigListContains(mcAdminList *clustersv1alpha1.MultiClusterApplicationConfigurationList, name string, namespace string) bool {
+// appConfigPlacedInThisCluster returns boolean indicating if the list contains the object with the specified name and
+// namespace, and the object is placed in this cluster
+func (s *Syncer) appConfigPlacedInThisCluster(mcAdminList *clustersv1alpha1.MultiClusterApplicationConfigurationList, name string, namespace string) bool {
 	for _, item := range mcAdminList.Items {
 		if item.Name == name && item.Namespace == namespace {
-			return true
+			return s.isThisCluster(item.Spec.Placement)
 		}
 	}
 	return false
@@ -9,12 +9,15 @@ import (
 	"path/filepath"
 	"testing"
 
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	"github.com/golang/mock/gomock"
 	asserts "github.com/stretchr/testify/assert"
 	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
 	clusterstest "github.com/verrazzano/verrazzano/application-operator/controllers/clusters/test"
 	"github.com/verrazzano/verrazzano/application-operator/mocks"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -42,6 +45,7 @@ func TestCreateMCAppConfig(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCAppConfig, err := getSampleMCAppConfig("testdata/multicluster-appconfig.yaml")
@@ -58,6 +62,11 @@ func TestCreateMCAppConfig(t *testing.T) {
 			return nil
 		})
 
+	// Managed Cluster - expect calls to get the components of the application configuration - return the components exist
+	mcMock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: testMCAppConfigNamespace, Name: "hello-component"}, gomock.Not(gomock.Nil())).
+		Return(nil)
+
 	// Managed Cluster - expect call to get a MultiClusterApplicationConfiguration from the list returned by the admin cluster
 	//                   Return the resource does not exist
 	mcMock.EXPECT().
@@ -75,6 +84,9 @@ func TestCreateMCAppConfig(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCAppConfig)
+
 	// Managed Cluster - expect call to list MultiClusterApplicationConfiguration objects - return same list as admin
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterApplicationConfigurationList{}, gomock.Not(gomock.Nil())).
@@ -115,6 +127,7 @@ func TestUpdateMCAppConfig(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCAppConfig, err := getSampleMCAppConfig("testdata/multicluster-appconfig.yaml")
@@ -132,6 +145,14 @@ func TestUpdateMCAppConfig(t *testing.T) {
 			return nil
 		})
 
+	// Managed Cluster - expect calls to get the components of the application configuration - return the components exist
+	mcMock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: testMCAppConfigNamespace, Name: "hello-component-updated"}, gomock.Not(gomock.Nil())).
+		Return(nil)
+	mcMock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: testMCAppConfigNamespace, Name: "hello-component-extra"}, gomock.Not(gomock.Nil())).
+		Return(nil)
+
 	// Managed Cluster - expect call to get a MultiClusterApplicationConfiguration from the list returned by the admin cluster
 	//                   Return the resource with some values different than what the admin cluster returned
 	mcMock.EXPECT().
@@ -162,6 +183,9 @@ func TestUpdateMCAppConfig(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCAppConfig)
+
 	// Managed Cluster - expect call to list MultiClusterApplicationConfiguration objects - return same list as admin
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterApplicationConfigurationList{}, gomock.Not(gomock.Nil())).
@@ -202,6 +226,7 @@ func TestDeleteMCAppConfig(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCAppConfig, err := getSampleMCAppConfig("testdata/multicluster-appconfig.yaml")
@@ -223,6 +248,11 @@ func TestDeleteMCAppConfig(t *testing.T) {
 			return nil
 		})
 
+	// Managed Cluster - expect calls to get the components of the application configuration - return the components exist
+	mcMock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: testMCAppConfigNamespace, Name: "hello-component"}, gomock.Not(gomock.Nil())).
+		Return(nil)
+
 	// Managed Cluster - expect call to get a MultiClusterApplicationConfiguration from the list returned by the admin cluster
 	//                   Return the resource
 	mcMock.EXPECT().
@@ -232,6 +262,9 @@ func TestDeleteMCAppConfig(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCAppConfig)
+
 	// Managed Cluster - expect call to list MultiClusterApplicationConfiguration objects - return list including an orphaned object
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterApplicationConfigurationList{}, gomock.Not(gomock.Nil())).
@@ -263,10 +296,99 @@ func TestDeleteMCAppConfig(t *testing.T) {
 	assert.NoError(err)
 }
 
+// TestMCAppConfigWaitingForDependencies tests the synchronization method for the following use case.
+// GIVEN a request to sync MultiClusterApplicationConfiguration objects
+// WHEN the component, the secret and the config map of the application configuration do not exist on the local cluster
+// THEN ensure that the MultiClusterApplicationConfiguration is not created, and the status of the cluster on the
+//      admin cluster is WaitingForDependencies with the names of the missing objects
+func TestMCAppConfigWaitingForDependencies(t *testing.T) {
+	assert := asserts.New(t)
+	log := ctrl.Log.WithName("test")
+
+	// Managed cluster mocks
+	mcMocker := gomock.NewController(t)
+	mcMock := mocks.NewMockClient(mcMocker)
+
+	// Admin cluster mocks
+	adminMocker := gomock.NewController(t)
+	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
+
+	// Test data
+	testMCAppConfig, err := getSampleMCAppConfig("testdata/multicluster-appconfig.yaml")
+	if err != nil {
+		assert.NoError(err, "failed to read sample data for MultiClusterApplicationConfiguration")
+	}
+	testMCAppConfig.Spec.Template.Spec.Components[0].Traits = []oamv1alpha2.ComponentTrait{
+		{Trait: runtime.RawExtension{Raw: []byte(`{"kind":"IngressTrait","spec":{"rules":[{"hosts":["hello.example.com"]}],"tls":{"secretName":"hello-tls"}}}`)}},
+	}
+
+	// Admin Cluster - expect call to list MultiClusterApplicationConfiguration objects - return list with one object
+	adminMock.EXPECT().
+		List(gomock.Any(), &clustersv1alpha1.MultiClusterApplicationConfigurationList{}, gomock.Not(gomock.Nil())).
+		DoAndReturn(func(ctx context.Context, mcAppConfigList *clustersv1alpha1.MultiClusterApplicationConfigurationList, listOptions *client.ListOptions) error {
+			mcAppConfigList.Items = append(mcAppConfigList.Items, testMCAppConfig)
+			return nil
+		})
+
+	// Managed Cluster - expect call to get the component of the application configuration - return the component does not exist
+	mcMock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: testMCAppConfigNamespace, Name: "hello-component"}, gomock.AssignableToTypeOf(&oamv1alpha2.Component{})).
+		Return(errors.NewNotFound(schema.GroupResource{Group: "core.oam.dev", Resource: "Component"}, "hello-component"))
+
+	// Admin Cluster - expect call to get the MultiClusterComponent - return a workload that references a config map
+	adminMock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: testMCAppConfigNamespace, Name: "hello-component"}, gomock.Not(gomock.Nil())).
+		DoAndReturn(func(ctx context.Context, name types.NamespacedName, mcComponent *clustersv1alpha1.MultiClusterComponent) error {
+			mcComponent.Spec.Template.Spec.Workload = runtime.RawExtension{Raw: []byte(`{"kind":"ContainerizedWorkload","spec":{"containers":[{"envFrom":[{"configMapRef":{"name":"hello-config"}}]}]}}`)}
+			return nil
+		})
+
+	// Managed Cluster - expect calls to get the secret and the config map - return they do not exist
+	mcMock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: testMCAppConfigNamespace, Name: "hello-tls"}, gomock.AssignableToTypeOf(&corev1.Secret{})).
+		Return(errors.NewNotFound(schema.GroupResource{Group: "", Resource: "Secret"}, "hello-tls"))
+	mcMock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: testMCAppConfigNamespace, Name: "hello-config"}, gomock.AssignableToTypeOf(&corev1.ConfigMap{})).
+		Return(errors.NewNotFound(schema.GroupResource{Group: "", Resource: "ConfigMap"}, "hello-config"))
+
+	// Admin Cluster - expect call to patch the status of the MultiClusterApplicationConfiguration with the missing dependencies
+	adminMock.EXPECT().Status().Return(adminStatusMock)
+	adminStatusMock.EXPECT().
+		Patch(gomock.Any(), gomock.AssignableToTypeOf(&clustersv1alpha1.MultiClusterApplicationConfiguration{}), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, mcAppConfig *clustersv1alpha1.MultiClusterApplicationConfiguration, patch client.Patch, opts ...client.PatchOption) error {
+			assert.Equal(clustersv1alpha1.Deploying, mcAppConfig.Status.State, "mcAppConfig state did not match")
+			assert.Len(mcAppConfig.Status.Clusters, 1, "mcAppConfig does not contain the status of the cluster")
+			assert.Equal(clustersv1alpha1.WaitingForDependencies, mcAppConfig.Status.Clusters[0].State, "mcAppConfig cluster state did not match")
+			assert.Equal("Waiting for the dependencies Component/hello-component, ConfigMap/hello-config, Secret/hello-tls", mcAppConfig.Status.Clusters[0].Message, "mcAppConfig cluster message did not match")
+			return nil
+		})
+
+	// Managed Cluster - expect call to list MultiClusterApplicationConfiguration objects - return an empty list
+	mcMock.EXPECT().
+		List(gomock.Any(), &clustersv1alpha1.MultiClusterApplicationConfigurationList{}, gomock.Not(gomock.Nil())).
+		Return(nil)
+
+	// Make the request
+	s := &Syncer{
+		AdminClient:        adminMock,
+		LocalClient:        mcMock,
+		Log:                log,
+		ManagedClusterName: testClusterName,
+		Context:            context.TODO(),
+	}
+	err = s.syncMCApplicationConfigurationObjects(testMCAppConfigNamespace)
+
+	// Validate the results
+	adminMocker.Finish()
+	mcMocker.Finish()
+	assert.NoError(err)
+}
+
 // TestMCAppConfigPlacement tests the synchronization method for the following use case.
 // GIVEN a request to sync MultiClusterApplicationConfiguration objects
 // WHEN an object exists that is not targeted for the cluster
-// THEN ensure that the MultiClusterApplicationConfiguration is not created or updated
+// THEN ensure that the MultiClusterApplicationConfiguration is not created or updated and the local copy is deleted
 func TestMCAppConfigPlacement(t *testing.T) {
 	assert := asserts.New(t)
 	log := ctrl.Log.WithName("test")
@@ -302,6 +424,11 @@ func TestMCAppConfigPlacement(t *testing.T) {
 			return nil
 		})
 
+	// Managed Cluster - expect a call to delete the MultiClusterApplicationConfiguration object that is no longer placed in this cluster
+	mcMock.EXPECT().
+		Delete(gomock.Any(), gomock.Eq(&testMCAppConfig), gomock.Any()).
+		Return(nil)
+
 	// Make the request
 	s := &Syncer{
 		AdminClient:        adminMock,
@@ -8,28 +8,90 @@ import (
 
 	"github.com/go-logr/logr"
 	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	"github.com/verrazzano/verrazzano/application-operator/constants"
+	"github.com/verrazzano/verrazzano/application-operator/controllers/clusters"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // Syncer contains context for synchronize operations
 type Syncer struct {
 	AdminClient        client.Client
+	AdminConfig        *rest.Config
 	LocalClient        client.Client
 	Log                logr.Logger
 	ManagedClusterName string
-	Context            context.Context
+	// Labels of the VerrazzanoManagedCluster resource of this cluster, to match the cluster selectors
+	ManagedClusterLabels map[string]string
+	Context              context.Context
 
 	// List of namespaces to watch for multi-cluster objects.
 	ProjectNamespaces []string
+
+	// Labels of the VerrazzanoManagedCluster resources on the admin cluster by cluster name, to find the clusters
+	// selected by the cluster selectors.  The labels are read once per sync pass, see syncKind.
+	clusterLabels map[string]map[string]string
 }
 
 // Check if the placement is for this cluster
 func (s *Syncer) isThisCluster(placement clustersv1alpha1.Placement) bool {
-	// Loop through the cluster list looking for the cluster name
-	for _, cluster := range placement.Clusters {
-		if cluster.Name == s.ManagedClusterName {
-			return true
-		}
+	return clusters.IsPlacedInCluster(placement, s.ManagedClusterName, s.ManagedClusterLabels)
+}
+
+// Update the status of this cluster in the status of a multi-cluster object on the admin cluster, and the overall
+// state of the object from the states of all the clusters it is placed in.  The statuses of the clusters the object
+// is no longer placed in are removed.  The status is patched with an optimistic lock because the agents of the other
+// clusters update the same status.
+func (s *Syncer) updateAdminClusterStatus(adminObj runtime.Object, placement clustersv1alpha1.Placement,
+	clusterStatuses *[]clustersv1alpha1.ClusterLevelStatus, state *clustersv1alpha1.StateType,
+	clusterStatus clustersv1alpha1.ClusterLevelStatus) error {
+	placedClusters, err := s.getPlacedClusters(placement)
+	if err != nil {
+		return err
+	}
+	orig := adminObj.DeepCopyObject()
+	changed := clusters.SetClusterLevelStatus(clusterStatuses, clusterStatus)
+	pruned := clusters.PruneClusterLevelStatus(clusterStatuses, placedClusters)
+	effectiveState := clusters.ComputeEffectiveState(placedClusters, *clusterStatuses)
+	if !changed && !pruned && *state == effectiveState {
+		return nil
+	}
+	*state = effectiveState
+	return s.AdminClient.Status().Patch(s.Context, adminObj, client.MergeFromWithOptions(orig, client.MergeFromWithOptimisticLock{}))
+}
+
+// Get the names of the clusters a multi-cluster object is placed in.  The clusters that are selected by the cluster
+// selector of the placement are found from the labels of the VerrazzanoManagedCluster resources on the admin cluster.
+func (s *Syncer) getPlacedClusters(placement clustersv1alpha1.Placement) (map[string]bool, error) {
+	if placement.ClusterSelector == nil {
+		return clusters.GetPlacedClusters(placement, nil), nil
+	}
+	clusterLabels, err := s.getClusterLabels()
+	if err != nil {
+		return nil, err
+	}
+	return clusters.GetPlacedClusters(placement, clusterLabels), nil
+}
+
+// Get the labels of the VerrazzanoManagedCluster resources on the admin cluster by cluster name.  The resources
+// are listed the first time the labels are needed in a sync pass, and the labels are reused for the other objects
+// that are synced in the same pass.
+func (s *Syncer) getClusterLabels() (map[string]map[string]string, error) {
+	if s.clusterLabels != nil {
+		return s.clusterLabels, nil
+	}
+	vmcList := unstructured.UnstructuredList{}
+	vmcList.SetGroupVersionKind(vmcListGroupVersionKind)
+	err := s.AdminClient.List(s.Context, &vmcList, client.InNamespace(constants.VerrazzanoMultiClusterNamespace))
+	if err != nil {
+		return nil, err
+	}
+	clusterLabels := map[string]map[string]string{}
+	for _, vmc := range vmcList.Items {
+		clusterLabels[vmc.GetName()] = vmc.GetLabels()
 	}
-	return false
+	s.clusterLabels = clusterLabels
+	return clusterLabels, nil
 }
@@ -0,0 +1,317 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package mcagent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	asserts "github.com/stretchr/testify/assert"
+	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	"github.com/verrazzano/verrazzano/application-operator/constants"
+	"github.com/verrazzano/verrazzano/application-operator/controllers/clusters"
+	"github.com/verrazzano/verrazzano/application-operator/mocks"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// multiClusterStatus is the part of the status that all the multi-cluster objects have in common
+type multiClusterStatus struct {
+	State    clustersv1alpha1.StateType            `json:"state,omitempty"`
+	Clusters []clustersv1alpha1.ClusterLevelStatus `json:"clusters,omitempty"`
+}
+
+// TestUpdateAdminClusterStatusSyncError tests the updateAdminClusterStatus method for the following use case.
+// GIVEN a multi-cluster object that failed to synchronize to this cluster
+// WHEN the status is updated on the admin cluster
+// THEN ensure that the object and this cluster are failed, with the error as the message
+func TestUpdateAdminClusterStatusSyncError(t *testing.T) {
+	assert := asserts.New(t)
+
+	adminMocker := gomock.NewController(t)
+	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
+
+	testMCSecret, err := getSampleMCSecret("testdata/multicluster-secret.yaml")
+	assert.NoError(err, "failed to get sample secret data")
+
+	// Admin Cluster - expect call to patch the status of the MultiClusterSecret with the failure of this cluster
+	adminMock.EXPECT().Status().Return(adminStatusMock)
+	adminStatusMock.EXPECT().
+		Patch(gomock.Any(), gomock.AssignableToTypeOf(&clustersv1alpha1.MultiClusterSecret{}), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, mcSecret *clustersv1alpha1.MultiClusterSecret, patch client.Patch, opts ...client.PatchOption) error {
+			assert.Equal(clustersv1alpha1.Failed, mcSecret.Status.State, "mcSecret state did not match")
+			assert.Len(mcSecret.Status.Clusters, 1, "mcSecret does not contain the status of the cluster")
+			assert.Equal(clustersv1alpha1.Failed, mcSecret.Status.Clusters[0].State, "mcSecret cluster state did not match")
+			assert.Equal("failed to create secret", mcSecret.Status.Clusters[0].Message, "mcSecret cluster message did not match")
+			return nil
+		})
+
+	// Make the request, the local copy is not read when the synchronization failed
+	s := &Syncer{
+		AdminClient:        adminMock,
+		Log:                ctrl.Log.WithName("test"),
+		ManagedClusterName: testClusterName,
+		Context:            context.TODO(),
+	}
+	s.updateMCSecretStatus(testMCSecret, fmt.Errorf("failed to create secret"))
+
+	// Validate the results
+	adminMocker.Finish()
+}
+
+// TestUpdateAdminClusterStatusConflict tests the updateAdminClusterStatus method for the following use case.
+// GIVEN a multi-cluster object whose status was changed on the admin cluster since it was read
+// WHEN the status is updated on the admin cluster
+// THEN ensure that the patch is rejected with a conflict, so the status of the other clusters is not overwritten
+func TestUpdateAdminClusterStatusConflict(t *testing.T) {
+	assert := asserts.New(t)
+
+	adminMocker := gomock.NewController(t)
+	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
+
+	testMCSecret, err := getSampleMCSecret("testdata/multicluster-secret.yaml")
+	assert.NoError(err, "failed to get sample secret data")
+	testMCSecret.ResourceVersion = "5"
+
+	// Admin Cluster - expect call to patch the status of the MultiClusterSecret with the resource version that
+	// was read - return a conflict
+	adminMock.EXPECT().Status().Return(adminStatusMock)
+	adminStatusMock.EXPECT().
+		Patch(gomock.Any(), gomock.AssignableToTypeOf(&clustersv1alpha1.MultiClusterSecret{}), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, mcSecret *clustersv1alpha1.MultiClusterSecret, patch client.Patch, opts ...client.PatchOption) error {
+			data, err := patch.Data(mcSecret)
+			assert.NoError(err)
+			assert.Contains(string(data), `"resourceVersion":"5"`, "the patch does not lock the resource version")
+			return errors.NewConflict(clustersv1alpha1.GroupVersion.WithResource("multiclustersecrets").GroupResource(), mcSecret.Name, fmt.Errorf("the object has been modified"))
+		})
+
+	// Make the request
+	s := &Syncer{
+		AdminClient:        adminMock,
+		Log:                ctrl.Log.WithName("test"),
+		ManagedClusterName: testClusterName,
+		Context:            context.TODO(),
+	}
+	clusterStatus := clusters.NewClusterLevelStatus(testClusterName, clustersv1alpha1.Ready, nil, nil)
+	err = s.updateAdminClusterStatus(&testMCSecret, testMCSecret.Spec.Placement, &testMCSecret.Status.Clusters, &testMCSecret.Status.State, clusterStatus)
+
+	// Validate the results
+	adminMocker.Finish()
+	assert.True(errors.IsConflict(err), "expected a conflict error")
+}
+
+// TestUpdateAdminClusterStatusTwoClusters tests the updateAdminClusterStatus method for the following use case.
+// GIVEN a multi-cluster object that is placed in two clusters
+// WHEN the agents of both clusters update the status of the object they read at the same time
+// THEN ensure that the update of the second agent conflicts, and the status of both clusters is kept once the
+//      second agent updates the status of the object it reads again
+func TestUpdateAdminClusterStatusTwoClusters(t *testing.T) {
+	assert := asserts.New(t)
+
+	testMCSecret, err := getSampleMCSecret("testdata/multicluster-secret.yaml")
+	assert.NoError(err, "failed to get sample secret data")
+	testMCSecret.Spec.Placement.Clusters = []clustersv1alpha1.Cluster{{Name: "managed1"}, {Name: "managed2"}}
+	testMCSecret.ResourceVersion = "1"
+	adminClient := fake.NewFakeClientWithScheme(clusters.NewScheme(), &testMCSecret)
+	name := types.NamespacedName{Namespace: testMCSecretNamespace, Name: testMCSecretName}
+
+	// Both agents read the object before either of them updates the status
+	agent1 := &Syncer{AdminClient: adminClient, Log: ctrl.Log.WithName("test"), ManagedClusterName: "managed1", Context: context.TODO()}
+	agent2 := &Syncer{AdminClient: adminClient, Log: ctrl.Log.WithName("test"), ManagedClusterName: "managed2", Context: context.TODO()}
+	mcSecret1 := clustersv1alpha1.MultiClusterSecret{}
+	assert.NoError(adminClient.Get(context.TODO(), name, &mcSecret1))
+	mcSecret2 := clustersv1alpha1.MultiClusterSecret{}
+	assert.NoError(adminClient.Get(context.TODO(), name, &mcSecret2))
+
+	assert.NoError(updateStatusReady(agent1, &mcSecret1))
+	err = updateStatusReady(agent2, &mcSecret2)
+	assert.True(errors.IsConflict(err), "expected a conflict error")
+
+	// The next synchronization of the second agent reads the status of the first agent
+	mcSecret2 = clustersv1alpha1.MultiClusterSecret{}
+	assert.NoError(adminClient.Get(context.TODO(), name, &mcSecret2))
+	assert.Equal(clustersv1alpha1.Deploying, mcSecret2.Status.State, "mcSecret should not be ready in all the clusters")
+	assert.NoError(updateStatusReady(agent2, &mcSecret2))
+
+	mcSecret := clustersv1alpha1.MultiClusterSecret{}
+	assert.NoError(adminClient.Get(context.TODO(), name, &mcSecret))
+	assert.Equal(clustersv1alpha1.Ready, mcSecret.Status.State, "mcSecret state did not match")
+	assert.Len(mcSecret.Status.Clusters, 2, "mcSecret does not contain the status of both clusters")
+}
+
+// TestUpdateAdminClusterStatusClusterSelector tests the updateAdminClusterStatus method for the following use case.
+// GIVEN a multi-cluster object that is placed in the clusters selected by a cluster selector
+// WHEN the agents of the selected clusters update the status of the object
+// THEN ensure that the object is only ready once all of the selected clusters are ready, and the status of a
+//      cluster that is no longer selected is removed
+func TestUpdateAdminClusterStatusClusterSelector(t *testing.T) {
+	assert := asserts.New(t)
+
+	testMCSecret, err := getSampleMCSecret("testdata/multicluster-secret.yaml")
+	assert.NoError(err, "failed to get sample secret data")
+	testMCSecret.Spec.Placement.Clusters = nil
+	testMCSecret.Spec.Placement.ClusterSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"region": "us-east"}}
+	testMCSecret.Status.Clusters = []clustersv1alpha1.ClusterLevelStatus{{Name: "managed3", State: clustersv1alpha1.Failed}}
+	testMCSecret.ResourceVersion = "1"
+	scheme := newAdminScheme()
+	scheme.AddKnownTypeWithName(vmcGroupVersionKind, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(vmcListGroupVersionKind, &unstructured.UnstructuredList{})
+	adminClient := fake.NewFakeClientWithScheme(scheme, &testMCSecret,
+		newVMC("managed1", "us-east"), newVMC("managed2", "us-east"), newVMC("managed3", "us-west"))
+	name := types.NamespacedName{Namespace: testMCSecretNamespace, Name: testMCSecretName}
+
+	// The object is not ready until all the selected clusters are ready, and the status of the cluster that
+	// is no longer selected is removed
+	agent1 := &Syncer{AdminClient: adminClient, Log: ctrl.Log.WithName("test"), ManagedClusterName: "managed1", Context: context.TODO()}
+	mcSecret := clustersv1alpha1.MultiClusterSecret{}
+	assert.NoError(adminClient.Get(context.TODO(), name, &mcSecret))
+	assert.NoError(updateStatusReady(agent1, &mcSecret))
+	mcSecret = clustersv1alpha1.MultiClusterSecret{}
+	assert.NoError(adminClient.Get(context.TODO(), name, &mcSecret))
+	assert.Equal(clustersv1alpha1.Deploying, mcSecret.Status.State, "mcSecret should not be ready in all the clusters")
+	assert.Len(mcSecret.Status.Clusters, 1, "the status of the cluster that is no longer selected was not removed")
+
+	agent2 := &Syncer{AdminClient: adminClient, Log: ctrl.Log.WithName("test"), ManagedClusterName: "managed2", Context: context.TODO()}
+	assert.NoError(updateStatusReady(agent2, &mcSecret))
+	mcSecret = clustersv1alpha1.MultiClusterSecret{}
+	assert.NoError(adminClient.Get(context.TODO(), name, &mcSecret))
+	assert.Equal(clustersv1alpha1.Ready, mcSecret.Status.State, "mcSecret state did not match")
+	assert.Len(mcSecret.Status.Clusters, 2, "mcSecret does not contain the status of both clusters")
+}
+
+// TestGetPlacedClustersOncePerSync tests the getPlacedClusters method for the following use case.
+// GIVEN multi-cluster objects that are placed in the clusters selected by a cluster selector
+// WHEN the placed clusters of the objects are found in the same sync pass
+// THEN ensure that the labels of the clusters are read once, and read again for the next sync pass
+func TestGetPlacedClustersOncePerSync(t *testing.T) {
+	assert := asserts.New(t)
+
+	scheme := newAdminScheme()
+	scheme.AddKnownTypeWithName(vmcGroupVersionKind, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(vmcListGroupVersionKind, &unstructured.UnstructuredList{})
+	vmc := newVMC("managed1", "us-east")
+	adminClient := fake.NewFakeClientWithScheme(scheme, vmc, newVMC("managed2", "us-west"))
+	localClient := fake.NewFakeClientWithScheme(newAdminScheme())
+	s := &Syncer{AdminClient: adminClient, LocalClient: localClient, Log: ctrl.Log.WithName("test"), ManagedClusterName: "managed1", Context: context.TODO()}
+	placement := clustersv1alpha1.Placement{ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "us-east"}}}
+
+	placed, err := s.getPlacedClusters(placement)
+	assert.NoError(err)
+	assert.Equal(map[string]bool{"managed1": true}, placed)
+
+	// The labels that changed after they were read are not used until the next sync pass
+	vmc.SetLabels(map[string]string{"region": "us-west"})
+	assert.NoError(adminClient.Update(context.TODO(), vmc))
+	placed, err = s.getPlacedClusters(placement)
+	assert.NoError(err)
+	assert.Equal(map[string]bool{"managed1": true}, placed, "the cluster labels should be read once per sync pass")
+
+	assert.NoError(syncKind(s, syncKey{kind: "MultiClusterSecret", namespace: testMCSecretNamespace}))
+	placed, err = s.getPlacedClusters(placement)
+	assert.NoError(err)
+	assert.Empty(placed, "the cluster labels should be read again for the next sync pass")
+}
+
+// TestDeleteNotPlacedLocalCopy tests the synchronization method for the following use case.
+// GIVEN a multi-cluster object that was synchronized to this cluster
+// WHEN the placement of the object on the admin cluster no longer includes this cluster
+// THEN ensure that the local copy of the object is deleted
+func TestDeleteNotPlacedLocalCopy(t *testing.T) {
+	assert := asserts.New(t)
+
+	testMCSecret, err := getSampleMCSecret("testdata/multicluster-secret.yaml")
+	assert.NoError(err, "failed to get sample secret data")
+	localMCSecret := testMCSecret.DeepCopy()
+	testMCSecret.Spec.Placement.Clusters = []clustersv1alpha1.Cluster{{Name: "managed2"}}
+	adminClient := fake.NewFakeClientWithScheme(newAdminScheme(), &testMCSecret)
+	localClient := fake.NewFakeClientWithScheme(newAdminScheme(), localMCSecret)
+
+	s := &Syncer{
+		AdminClient:        adminClient,
+		LocalClient:        localClient,
+		Log:                ctrl.Log.WithName("test"),
+		ManagedClusterName: testClusterName,
+		Context:            context.TODO(),
+	}
+	assert.NoError(s.syncMCSecretObjects(testMCSecretNamespace))
+
+	err = localClient.Get(context.TODO(), types.NamespacedName{Namespace: testMCSecretNamespace, Name: testMCSecretName}, &clustersv1alpha1.MultiClusterSecret{})
+	assert.True(errors.IsNotFound(err), "the MultiClusterSecret that is no longer placed in this cluster was not deleted")
+}
+
+// newVMC creates the VerrazzanoManagedCluster of a cluster, with the region label of the cluster
+func newVMC(name string, region string) *unstructured.Unstructured {
+	vmc := &unstructured.Unstructured{}
+	vmc.SetGroupVersionKind(vmcGroupVersionKind)
+	vmc.SetNamespace(constants.VerrazzanoMultiClusterNamespace)
+	vmc.SetName(name)
+	vmc.SetLabels(map[string]string{"region": region})
+	return vmc
+}
+
+// updateStatusReady updates the status of a MultiClusterSecret on the admin cluster with the Ready state of the
+// cluster of the agent
+func updateStatusReady(s *Syncer, mcSecret *clustersv1alpha1.MultiClusterSecret) error {
+	clusterStatus := clusters.NewClusterLevelStatus(s.ManagedClusterName, clustersv1alpha1.Ready, nil, nil)
+	return s.updateAdminClusterStatus(mcSecret, mcSecret.Spec.Placement, &mcSecret.Status.Clusters, &mcSecret.Status.State, clusterStatus)
+}
+
+// expectStatusUpdateReady expects the agent to read the local copy of a multi-cluster object for the status set
+// by the controller of this cluster, which is returned with the Ready state, and to patch the status of the object
+// on the admin cluster with the Ready state of this cluster.  The object must be a pointer to the multi-cluster
+// object that is synchronized from the admin cluster.
+func expectStatusUpdateReady(assert *asserts.Assertions, mcMock *mocks.MockClient, adminMock *mocks.MockClient, adminStatusMock *mocks.MockStatusWriter, mcObj runtime.Object) {
+	name, err := client.ObjectKeyFromObject(mcObj)
+	assert.NoError(err)
+
+	// Managed Cluster - expect call to get the multi-cluster object to read the status set by the local controller
+	mcMock.EXPECT().
+		Get(gomock.Any(), name, gomock.AssignableToTypeOf(mcObj)).
+		DoAndReturn(func(ctx context.Context, name types.NamespacedName, obj runtime.Object) error {
+			data, err := json.Marshal(mcObj)
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(data, obj); err != nil {
+				return err
+			}
+			return json.Unmarshal([]byte(`{"status":{"state":"Ready"}}`), obj)
+		})
+
+	// Admin Cluster - expect call to patch the status of the multi-cluster object with the status of this cluster
+	adminMock.EXPECT().Status().Return(adminStatusMock)
+	adminStatusMock.EXPECT().
+		Patch(gomock.Any(), gomock.AssignableToTypeOf(mcObj), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+			status := getMultiClusterStatus(assert, obj)
+			assert.Equal(clustersv1alpha1.Ready, status.State, "state did not match")
+			if assert.Len(status.Clusters, 1, "status does not contain the status of the cluster") {
+				assert.Equal(testClusterName, status.Clusters[0].Name, "cluster name did not match")
+				assert.Equal(clustersv1alpha1.Ready, status.Clusters[0].State, "cluster state did not match")
+			}
+			return nil
+		})
+}
+
+// getMultiClusterStatus returns the status of a multi-cluster object
+func getMultiClusterStatus(assert *asserts.Assertions, obj runtime.Object) multiClusterStatus {
+	var mcObj struct {
+		Status multiClusterStatus `json:"status"`
+	}
+	data, err := json.Marshal(obj)
+	assert.NoError(err)
+	assert.NoError(json.Unmarshal(data, &mcObj))
+	return mcObj.Status
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	"github.com/verrazzano/verrazzano/application-operator/controllers/clusters"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -31,6 +32,7 @@ func (s *Syncer) syncMCComponentObjects(namespace string) error {
 					"MultiClusterComponent",
 					types.NamespacedName{Namespace: mcComponent.Namespace, Name: mcComponent.Name})
 			}
+			s.updateMCComponentStatus(mcComponent, err)
 		}
 	}
 
@@ -45,8 +47,8 @@ func (s *Syncer) syncMCComponentObjects(namespace string) error {
 		return nil
 	}
 	for _, mcComponent := range allLocalMCComponents.Items {
-		// Delete each MultiClusterComponent object that is not on the admin cluster
-		if !componentListContains(&allAdminMCComponents, mcComponent.Name, mcComponent.Namespace) {
+		// Delete each MultiClusterComponent object that is not on the admin cluster or is no longer placed in this cluster
+		if !s.componentPlacedInThisCluster(&allAdminMCComponents, mcComponent.Name, mcComponent.Namespace) {
 			err := s.LocalClient.Delete(s.Context, &mcComponent)
 			if err != nil {
 				s.Log.Error(err, fmt.Sprintf("failed to delete MultiClusterComponent with name %q and namespace %q", mcComponent.Name, mcComponent.Namespace))
@@ -63,6 +65,12 @@ func (s *Syncer) createOrUpdateMCComponent(mcComponent clustersv1alpha1.MultiClu
 	mcComponentNew.Namespace = mcComponent.Namespace
 	mcComponentNew.Name = mcComponent.Name
 
+	// Apply the overrides for this cluster to the template
+	err := s.applyClusterOverrides(mcComponent.Spec.Overrides, &mcComponent.Spec.Template)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
 	// Create or update on the local cluster
 	return controllerutil.CreateOrUpdate(s.Context, s.LocalClient, &mcComponentNew, func() error {
 		mutateMCComponent(mcComponent, &mcComponentNew)
@@ -70,6 +78,25 @@ func (s *Syncer) createOrUpdateMCComponent(mcComponent clustersv1alpha1.MultiClu
 	})
 }
 
+// Update the status of this cluster in the MultiClusterComponent on the admin cluster, from the status
+// set by the controller of this cluster
+func (s *Syncer) updateMCComponentStatus(mcComponent clustersv1alpha1.MultiClusterComponent, syncErr error) {
+	name := types.NamespacedName{Namespace: mcComponent.Namespace, Name: mcComponent.Name}
+	localMCComponent := clustersv1alpha1.MultiClusterComponent{}
+	if syncErr == nil {
+		err := s.LocalClient.Get(s.Context, name, &localMCComponent)
+		if err != nil {
+			s.Log.Error(err, "Error getting object", "MultiClusterComponent", name)
+			return
+		}
+	}
+	clusterStatus := clusters.NewClusterLevelStatus(s.ManagedClusterName, localMCComponent.Status.State, localMCComponent.Status.Conditions, syncErr)
+	err := s.updateAdminClusterStatus(&mcComponent, mcComponent.Spec.Placement, &mcComponent.Status.Clusters, &mcComponent.Status.State, clusterStatus)
+	if err != nil {
+		s.Log.Error(err, "Error updating status on the admin cluster", "MultiClusterComponent", name)
+	}
+}
+
 // mutateMCComponent mutates the MultiClusterComponent to reflect the contents of the parent MultiClusterComponent
 func mutateMCComponent(mcComponent clustersv1alpha1.MultiClusterComponent, mcComponentNew *clustersv1alpha1.MultiClusterComponent) {
 	mcComponentNew.Spec.Placement = mcComponent.Spec.Placement
@@ -77,11 +104,12 @@ func mutateMCComponent(mcComponent clustersv1alpha1.MultiClusterComponent, mcCom
 	mcComponentNew.Labels = mcComponent.Labels
 }
 
-// componentListContains returns boolean indicating if the list contains the object with the specified name and namespace
-func componentListContains(mcAdminList *clustersv1alpha1.MultiClusterComponentList, name string, namespace string) bool {
+// componentPlacedInThisCluster returns boolean indicating if the list contains the object with the specified name and
+// namespace, and the object is placed in this cluster
+func (s *Syncer) componentPlacedInThisCluster(mcAdminList *clustersv1alpha1.MultiClusterComponentList, name string, namespace string) bool {
 	for _, item := range mcAdminList.Items {
 		if item.Name == name && item.Namespace == namespace {
-			return true
+			return s.isThisCluster(item.Spec.Placement)
 		}
 	}
 	return false
@@ -43,6 +43,7 @@ func TestCreateMCComponent(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCComponent, err := getSampleMCComponent("testdata/multicluster-component.yaml")
@@ -76,6 +77,9 @@ func TestCreateMCComponent(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCComponent)
+
 	// Managed Cluster - expect call to list MultiClusterComponent objects - return same list as admin
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterComponentList{}, gomock.Not(gomock.Nil())).
@@ -116,6 +120,7 @@ func TestUpdateMCComponent(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCComponent, err := getSampleMCComponent("testdata/multicluster-component.yaml")
@@ -158,6 +163,9 @@ func TestUpdateMCComponent(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCComponent)
+
 	// Managed Cluster - expect call to list MultiClusterComponent objects - return same list as admin
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterComponentList{}, gomock.Not(gomock.Nil())).
@@ -198,6 +206,7 @@ func TestDeleteMCComponent(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCComponent, err := getSampleMCComponent("testdata/multicluster-component.yaml")
@@ -228,6 +237,9 @@ func TestDeleteMCComponent(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCComponent)
+
 	// Managed Cluster - expect call to list MultiClusterComponent objects - return list including an orphaned object
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterComponentList{}, gomock.Not(gomock.Nil())).
@@ -262,7 +274,7 @@ func TestDeleteMCComponent(t *testing.T) {
 // TestMCComponentPlacement tests the synchronization method for the following use case.
 // GIVEN a request to sync MultiClusterComponent objects
 // WHEN the a object exists that is not targeted for the cluster
-// THEN ensure that the MultiClusterComponent is not created or updated
+// THEN ensure that the MultiClusterComponent is not created or updated and the local copy is deleted
 func TestMCComponentPlacement(t *testing.T) {
 	assert := asserts.New(t)
 	log := ctrl.Log.WithName("test")
@@ -298,6 +310,11 @@ func TestMCComponentPlacement(t *testing.T) {
 			return nil
 		})
 
+	// Managed Cluster - expect a call to delete the MultiClusterComponent object that is no longer placed in this cluster
+	mcMock.EXPECT().
+		Delete(gomock.Any(), gomock.Eq(&testMCComponent), gomock.Any()).
+		Return(nil)
+
 	// Make the request
 	s := &Syncer{
 		AdminClient:        adminMock,
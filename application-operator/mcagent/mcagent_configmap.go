@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	"github.com/verrazzano/verrazzano/application-operator/controllers/clusters"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -31,6 +32,7 @@ func (s *Syncer) syncMCConfigMapObjects(namespace string) error {
 					"MultiClusterConfigMap",
 					types.NamespacedName{Namespace: mcConfigMap.Namespace, Name: mcConfigMap.Name})
 			}
+			s.updateMCConfigMapStatus(mcConfigMap, err)
 		}
 	}
 
@@ -45,8 +47,8 @@ func (s *Syncer) syncMCConfigMapObjects(namespace string) error {
 		return nil
 	}
 	for _, mcConfigMap := range allLocalMCConfigMaps.Items {
-		// Delete each MultiClusterConfigMap object that is not on the admin cluster
-		if !configMapListContains(&allAdminMCConfigMaps, mcConfigMap.Name, mcConfigMap.Namespace) {
+		// Delete each MultiClusterConfigMap object that is not on the admin cluster or is no longer placed in this cluster
+		if !s.configMapPlacedInThisCluster(&allAdminMCConfigMaps, mcConfigMap.Name, mcConfigMap.Namespace) {
 			err := s.LocalClient.Delete(s.Context, &mcConfigMap)
 			if err != nil {
 				s.Log.Error(err, fmt.Sprintf("failed to delete MultiClusterConfigMap with name %q and namespace %q", mcConfigMap.Name, mcConfigMap.Namespace))
@@ -63,6 +65,12 @@ func (s *Syncer) createOrUpdateMCConfigMap(mcConfigMap clustersv1alpha1.MultiClu
 	mcConfigMapNew.Namespace = mcConfigMap.Namespace
 	mcConfigMapNew.Name = mcConfigMap.Name
 
+	// Apply the overrides for this cluster to the template
+	err := s.applyClusterOverrides(mcConfigMap.Spec.Overrides, &mcConfigMap.Spec.Template)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
 	// Create or update on the local cluster
 	return controllerutil.CreateOrUpdate(s.Context, s.LocalClient, &mcConfigMapNew, func() error {
 		mutateMCConfigMap(mcConfigMap, &mcConfigMapNew)
@@ -70,6 +78,25 @@ func (s *Syncer) createOrUpdateMCConfigMap(mcConfigMap clustersv1alpha1.MultiClu
 	})
 }
 
+// Update the status of this cluster in the MultiClusterConfigMap on the admin cluster, from the status
+// set by the controller of this cluster
+func (s *Syncer) updateMCConfigMapStatus(mcConfigMap clustersv1alpha1.MultiClusterConfigMap, syncErr error) {
+	name := types.NamespacedName{Namespace: mcConfigMap.Namespace, Name: mcConfigMap.Name}
+	localMCConfigMap := clustersv1alpha1.MultiClusterConfigMap{}
+	if syncErr == nil {
+		err := s.LocalClient.Get(s.Context, name, &localMCConfigMap)
+		if err != nil {
+			s.Log.Error(err, "Error getting object", "MultiClusterConfigMap", name)
+			return
+		}
+	}
+	clusterStatus := clusters.NewClusterLevelStatus(s.ManagedClusterName, localMCConfigMap.Status.State, localMCConfigMap.Status.Conditions, syncErr)
+	err := s.updateAdminClusterStatus(&mcConfigMap, mcConfigMap.Spec.Placement, &mcConfigMap.Status.Clusters, &mcConfigMap.Status.State, clusterStatus)
+	if err != nil {
+		s.Log.Error(err, "Error updating status on the admin cluster", "MultiClusterConfigMap", name)
+	}
+}
+
 // mutateMCConfigMap mutates the MultiClusterConfigMap to reflect the contents of the parent MultiClusterConfigMap
 func mutateMCConfigMap(mcConfigMap clustersv1alpha1.MultiClusterConfigMap, mcConfigMapNew *clustersv1alpha1.MultiClusterConfigMap) {
 	mcConfigMapNew.Spec.Placement = mcConfigMap.Spec.Placement
@@ -77,11 +104,12 @@ func mutateMCConfigMap(mcConfigMap clustersv1alpha1.MultiClusterConfigMap, mcCon
 	mcConfigMapNew.Labels = mcConfigMap.Labels
 }
 
-// configMapListContains returns boolean indicating if the list contains the object with the specified name and namespace
-func configMapListContains(mcAdminList *clustersv1alpha1.MultiClusterConfigMapList, name string, namespace string) bool {
+// configMapPlacedInThisCluster returns boolean indicating if the list contains the object with the specified name and
+// namespace, and the object is placed in this cluster
+func (s *Syncer) configMapPlacedInThisCluster(mcAdminList *clustersv1alpha1.MultiClusterConfigMapList, name string, namespace string) bool {
 	for _, item := range mcAdminList.Items {
 		if item.Name == name && item.Namespace == namespace {
-			return true
+			return s.isThisCluster(item.Spec.Placement)
 		}
 	}
 	return false
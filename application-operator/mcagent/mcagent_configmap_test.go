@@ -15,6 +15,7 @@ import (
 	clusterstest "github.com/verrazzano/verrazzano/application-operator/controllers/clusters/test"
 	"github.com/verrazzano/verrazzano/application-operator/mocks"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -42,6 +43,7 @@ func TestCreateMCConfigMap(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCConfigMap, err := getSampleMCConfigMap("testdata/multicluster-configmap.yaml")
@@ -76,6 +78,9 @@ func TestCreateMCConfigMap(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCConfigMap)
+
 	// Managed Cluster - expect call to list MultiClusterConfigMap objects - return same list as admin cluster
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterConfigMapList{}, gomock.Not(gomock.Nil())).
@@ -101,6 +106,108 @@ func TestCreateMCConfigMap(t *testing.T) {
 	assert.NoError(err)
 }
 
+// TestCreateMCConfigMapWithOverrides tests the synchronization method for the following use case.
+// GIVEN a request to sync MultiClusterConfigMap objects
+// WHEN a new object exists with overrides for this cluster and for another cluster
+// THEN ensure that the MultiClusterConfigMap is created with the overrides for this cluster applied to the template
+func TestCreateMCConfigMapWithOverrides(t *testing.T) {
+	assert := asserts.New(t)
+	log := ctrl.Log.WithName("test")
+
+	// Managed cluster mocks
+	mcMocker := gomock.NewController(t)
+	mcMock := mocks.NewMockClient(mcMocker)
+
+	// Admin cluster mocks
+	adminMocker := gomock.NewController(t)
+	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
+
+	// Test data
+	testMCConfigMap, err := getSampleMCConfigMap("testdata/multicluster-configmap.yaml")
+	if err != nil {
+		assert.NoError(err, "failed to read sample data for MultiClusterConfigMap")
+	}
+	testMCConfigMap.Spec.Overrides = []clustersv1alpha1.ClusterOverride{
+		{
+			ClusterName: testClusterName,
+			PatchType:   clustersv1alpha1.MergePatchType,
+			Patch:       runtime.RawExtension{Raw: []byte(`{"data":{"simple.key":"managed1value"}}`)},
+		},
+		{
+			ClusterName: "managed2",
+			PatchType:   clustersv1alpha1.MergePatchType,
+			Patch:       runtime.RawExtension{Raw: []byte(`{"data":{"simple.key":"managed2value"}}`)},
+		},
+	}
+
+	// Admin Cluster - expect call to list MultiClusterConfigMap objects - return list with one object
+	adminMock.EXPECT().
+		List(gomock.Any(), &clustersv1alpha1.MultiClusterConfigMapList{}, gomock.Not(gomock.Nil())).
+		DoAndReturn(func(ctx context.Context, mcConfigMapList *clustersv1alpha1.MultiClusterConfigMapList, listOptions *client.ListOptions) error {
+			mcConfigMapList.Items = append(mcConfigMapList.Items, testMCConfigMap)
+			return nil
+		})
+
+	// Managed Cluster - expect call to get a MultiClusterConfigMap from the list returned by the admin cluster
+	//                   Return the resource does not exist
+	mcMock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: testMCConfigMapNamespace, Name: testMCConfigMapName}, gomock.Not(gomock.Nil())).
+		Return(errors.NewNotFound(schema.GroupResource{Group: "clusters.verrazzano.io", Resource: "MultiClusterConfigMap"}, testMCConfigMapName))
+
+	// Managed Cluster - expect call to create a MultiClusterConfigMap with the override for this cluster applied
+	mcMock.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, mcConfigMap *clustersv1alpha1.MultiClusterConfigMap, opts ...client.CreateOption) error {
+			assert.Equal("managed1value", mcConfigMap.Spec.Template.Data["simple.key"], "mcConfigMap override was not applied")
+			assert.Equal(testMCConfigMap.Spec.Template.Data["json.key"], mcConfigMap.Spec.Template.Data["json.key"], "mcConfigMap data was not kept")
+			assert.Equal("myconfigmap", mcConfigMap.Spec.Template.Metadata.Name, "mcConfigMap metadata was not kept")
+			assert.Empty(mcConfigMap.Spec.Overrides, "mcConfigMap overrides were copied to the local cluster")
+			return nil
+		})
+
+	// Managed Cluster - expect call to get the MultiClusterConfigMap to read the status set by the local controller
+	mcMock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: testMCConfigMapNamespace, Name: testMCConfigMapName}, gomock.Not(gomock.Nil())).
+		DoAndReturn(func(ctx context.Context, name types.NamespacedName, mcConfigMap *clustersv1alpha1.MultiClusterConfigMap) error {
+			mcConfigMap.Status.State = clustersv1alpha1.Ready
+			return nil
+		})
+
+	// Admin Cluster - expect call to patch the status of the MultiClusterConfigMap - the template is not changed
+	adminMock.EXPECT().Status().Return(adminStatusMock)
+	adminStatusMock.EXPECT().
+		Patch(gomock.Any(), gomock.AssignableToTypeOf(&clustersv1alpha1.MultiClusterConfigMap{}), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, mcConfigMap *clustersv1alpha1.MultiClusterConfigMap, patch client.Patch, opts ...client.PatchOption) error {
+			assert.Equal("simplevalue", mcConfigMap.Spec.Template.Data["simple.key"], "admin mcConfigMap template was changed")
+			assert.Equal(clustersv1alpha1.Ready, mcConfigMap.Status.Clusters[0].State, "mcConfigMap cluster state did not match")
+			return nil
+		})
+
+	// Managed Cluster - expect call to list MultiClusterConfigMap objects - return same list as admin cluster
+	mcMock.EXPECT().
+		List(gomock.Any(), &clustersv1alpha1.MultiClusterConfigMapList{}, gomock.Not(gomock.Nil())).
+		DoAndReturn(func(ctx context.Context, mcConfigMapList *clustersv1alpha1.MultiClusterConfigMapList, listOptions *client.ListOptions) error {
+			mcConfigMapList.Items = append(mcConfigMapList.Items, testMCConfigMap)
+			return nil
+		})
+
+	// Make the request
+	s := &Syncer{
+		AdminClient:        adminMock,
+		LocalClient:        mcMock,
+		Log:                log,
+		ManagedClusterName: testClusterName,
+		Context:            context.TODO(),
+	}
+	err = s.syncMCConfigMapObjects(testMCConfigMapNamespace)
+
+	// Validate the results
+	adminMocker.Finish()
+	mcMocker.Finish()
+	assert.NoError(err)
+}
+
 // TestUpdateMCConfigMap tests the synchronization method for the following use case.
 // GIVEN a request to sync MultiClusterConfigMap objects
 // WHEN the a object exists
@@ -117,6 +224,7 @@ func TestUpdateMCConfigMap(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCConfigMap, err := getSampleMCConfigMap("testdata/multicluster-configmap.yaml")
@@ -155,6 +263,9 @@ func TestUpdateMCConfigMap(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCConfigMap)
+
 	// Managed Cluster - expect call to list MultiClusterConfigMap objects - return same list as admin cluster
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterConfigMapList{}, gomock.Not(gomock.Nil())).
@@ -195,6 +306,7 @@ func TestDeleteMCConfigMap(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCConfigMap, err := getSampleMCConfigMap("testdata/multicluster-configmap.yaml")
@@ -225,6 +337,9 @@ func TestDeleteMCConfigMap(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCConfigMap)
+
 	// Managed Cluster - expect call to list MultiClusterConfigMap objects - return list including an orphaned object
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterConfigMapList{}, gomock.Not(gomock.Nil())).
@@ -259,7 +374,7 @@ func TestDeleteMCConfigMap(t *testing.T) {
 // TestMCConfigMapPlacement tests the synchronization method for the following use case.
 // GIVEN a request to sync MultiClusterConfigMap objects
 // WHEN the a object exists that is not targeted for the cluster
-// THEN ensure that the MultiClusterConfigMap is not created or updated
+// THEN ensure that the MultiClusterConfigMap is not created or updated and the local copy is deleted
 func TestMCConfigMapPlacement(t *testing.T) {
 	assert := asserts.New(t)
 	log := ctrl.Log.WithName("test")
@@ -295,6 +410,11 @@ func TestMCConfigMapPlacement(t *testing.T) {
 			return nil
 		})
 
+	// Managed Cluster - expect a call to delete the MultiClusterConfigMap object that is no longer placed in this cluster
+	mcMock.EXPECT().
+		Delete(gomock.Any(), gomock.Eq(&testMCConfigMap), gomock.Any()).
+		Return(nil)
+
 	// Make the request
 	s := &Syncer{
 		AdminClient:        adminMock,
@@ -0,0 +1,194 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package mcagent
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	"github.com/verrazzano/verrazzano/application-operator/controllers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// The kinds of the objects that an application configuration depends on
+const (
+	componentDependency = "Component"
+	secretDependency    = "Secret"
+	configMapDependency = "ConfigMap"
+)
+
+// The fields of the workloads and traits that reference a secret or a config map by name.  The name fields
+// contain the name, and the reference fields contain either the name or an object with the name in a name field.
+var (
+	secretNameFields    = []string{"secretName", "runtimeEncryptionSecret"}
+	secretRefFields     = []string{"secretRef", "secretKeyRef", "webLogicCredentialsSecret"}
+	secretListFields    = []string{"imagePullSecrets"}
+	configMapRefFields  = []string{"configMap", "configMapRef", "configMapKeyRef"}
+	configMapNameFields = []string{"configMapName"}
+)
+
+// dependency is an object in the namespace of an application configuration that must exist on the local
+// cluster before the application configuration is synced
+type dependency struct {
+	kind string
+	name string
+}
+
+// String returns the kind and the name of the dependency
+func (d dependency) String() string {
+	return fmt.Sprintf("%s/%s", d.kind, d.name)
+}
+
+// Get the dependencies of the application configuration that do not exist on the local cluster.  The
+// dependencies are the components of the application configuration, and the secrets and config maps that are
+// referenced by the workloads of the components and by the traits, after the overrides for this cluster are
+// applied.  The workload of a component that does not exist on the local cluster yet is read from the
+// MultiClusterComponent on the admin cluster.
+func (s *Syncer) getMissingDependencies(mcAppConfig clustersv1alpha1.MultiClusterApplicationConfiguration) ([]dependency, error) {
+	namespace := mcAppConfig.Namespace
+	template := mcAppConfig.Spec.Template
+	err := s.applyClusterOverrides(mcAppConfig.Spec.Overrides, &template)
+	if err != nil {
+		return nil, err
+	}
+	var missing []dependency
+	var refs []dependency
+	for _, appComponent := range template.Spec.Components {
+		name := types.NamespacedName{Namespace: namespace, Name: appComponent.ComponentName}
+		component := oamv1alpha2.Component{}
+		err := s.LocalClient.Get(s.Context, name, &component)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		workload := component.Spec.Workload
+		if errors.IsNotFound(err) {
+			missing = append(missing, dependency{kind: componentDependency, name: appComponent.ComponentName})
+			mcComponent := clustersv1alpha1.MultiClusterComponent{}
+			err = s.AdminClient.Get(s.Context, name, &mcComponent)
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+			err = s.applyClusterOverrides(mcComponent.Spec.Overrides, &mcComponent.Spec.Template)
+			if err != nil {
+				return nil, err
+			}
+			workload = mcComponent.Spec.Template.Spec.Workload
+		}
+		refs = append(refs, findReferences(workload)...)
+		for _, trait := range appComponent.Traits {
+			refs = append(refs, findReferences(trait.Trait)...)
+		}
+	}
+
+	// Check that the referenced secrets and config maps exist on the local cluster
+	checked := map[dependency]bool{}
+	for _, ref := range refs {
+		if checked[ref] {
+			continue
+		}
+		checked[ref] = true
+		var obj runtime.Object = &corev1.Secret{}
+		if ref.kind == configMapDependency {
+			obj = &corev1.ConfigMap{}
+		}
+		err := s.LocalClient.Get(s.Context, types.NamespacedName{Namespace: namespace, Name: ref.name}, obj)
+		if errors.IsNotFound(err) {
+			missing = append(missing, ref)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return missing, nil
+}
+
+// Return the message of the status of an application configuration that is waiting for its dependencies
+func waitingForDependenciesMessage(missing []dependency) string {
+	names := make([]string, len(missing))
+	for i, dep := range missing {
+		names[i] = dep.String()
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("Waiting for the dependencies %s", strings.Join(names, ", "))
+}
+
+// Find the secrets and config maps that are referenced by name in a workload or a trait
+func findReferences(raw runtime.RawExtension) []dependency {
+	if len(raw.Raw) == 0 {
+		return nil
+	}
+	var obj interface{}
+	if err := json.Unmarshal(raw.Raw, &obj); err != nil {
+		return nil
+	}
+	var refs []dependency
+	collectReferences(obj, &refs)
+	return refs
+}
+
+// Walk the fields of a workload or a trait and collect the references to secrets and config maps.  The optional
+// references are not collected because the workload does not need the objects they reference.
+func collectReferences(obj interface{}, refs *[]dependency) {
+	switch value := obj.(type) {
+	case map[string]interface{}:
+		for field, fieldValue := range value {
+			switch {
+			case controllers.StringSliceContainsString(secretNameFields, field):
+				if !isOptional(value) {
+					addReference(refs, secretDependency, fieldValue)
+				}
+			case controllers.StringSliceContainsString(secretRefFields, field):
+				addReference(refs, secretDependency, nameOf(fieldValue))
+			case controllers.StringSliceContainsString(secretListFields, field):
+				if list, ok := fieldValue.([]interface{}); ok {
+					for _, item := range list {
+						addReference(refs, secretDependency, nameOf(item))
+					}
+				}
+			case controllers.StringSliceContainsString(configMapRefFields, field):
+				addReference(refs, configMapDependency, nameOf(fieldValue))
+			case controllers.StringSliceContainsString(configMapNameFields, field):
+				if !isOptional(value) {
+					addReference(refs, configMapDependency, fieldValue)
+				}
+			}
+			collectReferences(fieldValue, refs)
+		}
+	case []interface{}:
+		for _, item := range value {
+			collectReferences(item, refs)
+		}
+	}
+}
+
+// Add a reference to the list if the name is a non-empty string
+func addReference(refs *[]dependency, kind string, name interface{}) {
+	if s, ok := name.(string); ok && len(s) > 0 {
+		*refs = append(*refs, dependency{kind: kind, name: s})
+	}
+}
+
+// Return the name field of an object reference, or the reference itself if it is a name.  An optional object
+// reference has no name.
+func nameOf(ref interface{}) interface{} {
+	if m, ok := ref.(map[string]interface{}); ok {
+		if isOptional(m) {
+			return nil
+		}
+		return m["name"]
+	}
+	return ref
+}
+
+// Return true if an object reference, or the volume source that contains a name field, is marked optional
+func isOptional(m map[string]interface{}) bool {
+	optional, ok := m["optional"].(bool)
+	return ok && optional
+}
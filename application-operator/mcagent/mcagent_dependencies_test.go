@@ -0,0 +1,68 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package mcagent
+
+import (
+	"testing"
+
+	asserts "github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// TestFindReferences tests the findReferences function
+// GIVEN a workload that references secrets and config maps by name in nested fields
+// WHEN the references are found
+// THEN the referenced secrets and config maps that are not optional are returned, and invalid or empty workloads have no references
+func TestFindReferences(t *testing.T) {
+	assert := asserts.New(t)
+
+	workload := runtime.RawExtension{Raw: []byte(`{
+		"kind": "VerrazzanoWebLogicWorkload",
+		"spec": {"template": {"spec": {
+			"webLogicCredentialsSecret": {"name": "weblogic-credentials"},
+			"imagePullSecrets": [{"name": "registry-secret"}],
+			"configuration": {"model": {"configMap": "model-config", "runtimeEncryptionSecret": "encryption-secret"}},
+			"serverPod": {"env": [{"name": "KEY", "valueFrom": {"configMapKeyRef": {"name": "env-config", "key": "key"}}}]}
+		}}}
+	}`)}
+	refs := findReferences(workload)
+	assert.ElementsMatch([]dependency{
+		{kind: secretDependency, name: "weblogic-credentials"},
+		{kind: secretDependency, name: "registry-secret"},
+		{kind: secretDependency, name: "encryption-secret"},
+		{kind: configMapDependency, name: "model-config"},
+		{kind: configMapDependency, name: "env-config"},
+	}, refs)
+
+	// The optional references in the env and envFrom of a container, and in volumes, are not returned
+	workload = runtime.RawExtension{Raw: []byte(`{
+		"kind": "ContainerizedWorkload",
+		"spec": {"containers": [{
+			"env": [
+				{"name": "A", "valueFrom": {"secretKeyRef": {"name": "optional-secret", "key": "a", "optional": true}}},
+				{"name": "B", "valueFrom": {"configMapKeyRef": {"name": "optional-config", "key": "b", "optional": true}}},
+				{"name": "C", "valueFrom": {"secretKeyRef": {"name": "required-secret", "key": "c", "optional": false}}}
+			],
+			"envFrom": [
+				{"configMapRef": {"name": "optional-env-config", "optional": true}},
+				{"secretRef": {"name": "optional-env-secret", "optional": true}},
+				{"configMapRef": {"name": "required-env-config"}}
+			]
+		}],
+		"volumes": [
+			{"name": "v1", "secret": {"secretName": "optional-volume-secret", "optional": true}},
+			{"name": "v2", "configMap": {"name": "optional-volume-config", "optional": true}}
+		]}
+	}`)}
+	refs = findReferences(workload)
+	assert.ElementsMatch([]dependency{
+		{kind: secretDependency, name: "required-secret"},
+		{kind: configMapDependency, name: "required-env-config"},
+	}, refs)
+
+	assert.Empty(findReferences(runtime.RawExtension{}))
+	assert.Empty(findReferences(runtime.RawExtension{Raw: []byte("not json")}))
+	assert.Equal("Waiting for the dependencies Component/b, Secret/a",
+		waitingForDependenciesMessage([]dependency{{kind: secretDependency, name: "a"}, {kind: componentDependency, name: "b"}}))
+}
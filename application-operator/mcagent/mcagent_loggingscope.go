@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	"github.com/verrazzano/verrazzano/application-operator/controllers/clusters"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -31,6 +32,7 @@ func (s *Syncer) syncMCLoggingScopeObjects(namespace string) error {
 					"MultiClusterLoggingScope",
 					types.NamespacedName{Namespace: mcLoggingScope.Namespace, Name: mcLoggingScope.Name})
 			}
+			s.updateMCLoggingScopeStatus(mcLoggingScope, err)
 		}
 	}
 
@@ -45,8 +47,8 @@ func (s *Syncer) syncMCLoggingScopeObjects(namespace string) error {
 		return nil
 	}
 	for _, mcLoggingScope := range allLocalMCLoggingScopes.Items {
-		// Delete each MultiClusterLoggingScope object that is not on the admin cluster
-		if !loggingScopeListContains(&allAdminMCLoggingScopes, mcLoggingScope.Name, mcLoggingScope.Namespace) {
+		// Delete each MultiClusterLoggingScope object that is not on the admin cluster or is no longer placed in this cluster
+		if !s.loggingScopePlacedInThisCluster(&allAdminMCLoggingScopes, mcLoggingScope.Name, mcLoggingScope.Namespace) {
 			err := s.LocalClient.Delete(s.Context, &mcLoggingScope)
 			if err != nil {
 				s.Log.Error(err, fmt.Sprintf("failed to delete MultiClusterLoggingScope with name %q and namespace %q", mcLoggingScope.Name, mcLoggingScope.Namespace))
@@ -63,6 +65,12 @@ func (s *Syncer) createOrUpdateMCLoggingScope(mcLoggingScope clustersv1alpha1.Mu
 	mcLoggingScopeNew.Namespace = mcLoggingScope.Namespace
 	mcLoggingScopeNew.Name = mcLoggingScope.Name
 
+	// Apply the overrides for this cluster to the template
+	err := s.applyClusterOverrides(mcLoggingScope.Spec.Overrides, &mcLoggingScope.Spec.Template)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
 	// Create or update on the local cluster
 	return controllerutil.CreateOrUpdate(s.Context, s.LocalClient, &mcLoggingScopeNew, func() error {
 		mutateMCLoggingScope(mcLoggingScope, &mcLoggingScopeNew)
@@ -70,6 +78,25 @@ func (s *Syncer) createOrUpdateMCLoggingScope(mcLoggingScope clustersv1alpha1.Mu
 	})
 }
 
+// Update the status of this cluster in the MultiClusterLoggingScope on the admin cluster, from the status
+// set by the controller of this cluster
+func (s *Syncer) updateMCLoggingScopeStatus(mcLoggingScope clustersv1alpha1.MultiClusterLoggingScope, syncErr error) {
+	name := types.NamespacedName{Namespace: mcLoggingScope.Namespace, Name: mcLoggingScope.Name}
+	localMCLoggingScope := clustersv1alpha1.MultiClusterLoggingScope{}
+	if syncErr == nil {
+		err := s.LocalClient.Get(s.Context, name, &localMCLoggingScope)
+		if err != nil {
+			s.Log.Error(err, "Error getting object", "MultiClusterLoggingScope", name)
+			return
+		}
+	}
+	clusterStatus := clusters.NewClusterLevelStatus(s.ManagedClusterName, localMCLoggingScope.Status.State, localMCLoggingScope.Status.Conditions, syncErr)
+	err := s.updateAdminClusterStatus(&mcLoggingScope, mcLoggingScope.Spec.Placement, &mcLoggingScope.Status.Clusters, &mcLoggingScope.Status.State, clusterStatus)
+	if err != nil {
+		s.Log.Error(err, "Error updating status on the admin cluster", "MultiClusterLoggingScope", name)
+	}
+}
+
 // mutateMCLoggingScope mutates the MultiClusterLoggingScope to reflect the contents of the parent MultiClusterLoggingScope
 func mutateMCLoggingScope(mcLoggingScope clustersv1alpha1.MultiClusterLoggingScope, mcLoggingScopeNew *clustersv1alpha1.MultiClusterLoggingScope) {
 	mcLoggingScopeNew.Spec.Placement = mcLoggingScope.Spec.Placement
@@ -77,11 +104,12 @@ func mutateMCLoggingScope(mcLoggingScope clustersv1alpha1.MultiClusterLoggingSco
 	mcLoggingScopeNew.Labels = mcLoggingScope.Labels
 }
 
-// loggingScopeListContains returns boolean indicating if the list contains the object with the specified name and namespace
-func loggingScopeListContains(mcAdminList *clustersv1alpha1.MultiClusterLoggingScopeList, name string, namespace string) bool {
+// loggingScopePlacedInThisCluster returns boolean indicating if the list contains the object with the specified name and
+// namespace, and the object is placed in this cluster
+func (s *Syncer) loggingScopePlacedInThisCluster(mcAdminList *clustersv1alpha1.MultiClusterLoggingScopeList, name string, namespace string) bool {
 	for _, item := range mcAdminList.Items {
 		if item.Name == name && item.Namespace == namespace {
-			return true
+			return s.isThisCluster(item.Spec.Placement)
 		}
 	}
 	return false
@@ -42,6 +42,7 @@ func TestCreateMCLoggingScope(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCLoggingScope, err := getSampleMCLoggingScope("testdata/multicluster-loggingscope.yaml")
@@ -77,6 +78,9 @@ func TestCreateMCLoggingScope(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCLoggingScope)
+
 	// Managed Cluster - expect call to list MultiClusterLoggingScope objects - return same list as admin cluster
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterLoggingScopeList{}, gomock.Not(gomock.Nil())).
@@ -117,6 +121,7 @@ func TestUpdateMCLoggingScope(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCLoggingScope, err := getSampleMCLoggingScope("testdata/multicluster-loggingscope.yaml")
@@ -156,6 +161,9 @@ func TestUpdateMCLoggingScope(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCLoggingScope)
+
 	// Managed Cluster - expect call to list MultiClusterLoggingScope objects - return same list as admin cluster
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterLoggingScopeList{}, gomock.Not(gomock.Nil())).
@@ -196,6 +204,7 @@ func TestDeleteMCLoggingScope(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCLoggingScope, err := getSampleMCLoggingScope("testdata/multicluster-loggingscope.yaml")
@@ -226,6 +235,9 @@ func TestDeleteMCLoggingScope(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCLoggingScope)
+
 	// Managed Cluster - expect call to list MultiClusterLoggingScope objects - return list including an orphaned object
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterLoggingScopeList{}, gomock.Not(gomock.Nil())).
@@ -260,7 +272,7 @@ func TestDeleteMCLoggingScope(t *testing.T) {
 // TestMCLoggingScopePlacement tests the synchronization method for the following use case.
 // GIVEN a request to sync MultiClusterLoggingScope objects
 // WHEN the a object exists that is not targeted for the cluster
-// THEN ensure that the MultiClusterLoggingScope is not created or updated
+// THEN ensure that the MultiClusterLoggingScope is not created or updated and the local copy is deleted
 func TestMCLoggingScopePlacement(t *testing.T) {
 	assert := asserts.New(t)
 	log := ctrl.Log.WithName("test")
@@ -296,6 +308,11 @@ func TestMCLoggingScopePlacement(t *testing.T) {
 			return nil
 		})
 
+	// Managed Cluster - expect a call to delete the MultiClusterLoggingScope object that is no longer placed in this cluster
+	mcMock.EXPECT().
+		Delete(gomock.Any(), gomock.Eq(&testMCLoggingScope), gomock.Any()).
+		Return(nil)
+
 	// Make the request
 	s := &Syncer{
 		AdminClient:        adminMock,
@@ -0,0 +1,63 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package mcagent
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	"github.com/verrazzano/verrazzano/application-operator/controllers/clusters"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// Apply the patches of the overrides for this cluster to the template of a multi-cluster object, in the order of
+// the overrides.  The template must be a pointer to the template struct, which is replaced by the patched template.
+func (s *Syncer) applyClusterOverrides(overrides []clustersv1alpha1.ClusterOverride, template interface{}) error {
+	var patched []byte
+	for i, override := range overrides {
+		if !clusters.IsOverrideForCluster(override, s.ManagedClusterName, s.ManagedClusterLabels) {
+			continue
+		}
+		var err error
+		if patched == nil {
+			patched, err = json.Marshal(template)
+			if err != nil {
+				return err
+			}
+		}
+		patched, err = applyPatch(patched, override, template)
+		if err != nil {
+			return fmt.Errorf("failed to apply the override %d for cluster %s, %v", i, s.ManagedClusterName, err)
+		}
+	}
+	if patched == nil {
+		return nil
+	}
+
+	// Reset the template before decoding the patched template, so that the fields removed by the patches are not kept
+	value := reflect.ValueOf(template).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	return json.Unmarshal(patched, template)
+}
+
+// Apply the patch of an override to the JSON document of a template.  A patch without a type is a merge patch.  The
+// template struct provides the patch strategies of the fields for a strategic merge patch, which cannot patch the
+// fields of the embedded resources, such as the workload of a component.
+func applyPatch(doc []byte, override clustersv1alpha1.ClusterOverride, template interface{}) ([]byte, error) {
+	switch override.PatchType {
+	case clustersv1alpha1.JSONPatchType:
+		patch, err := jsonpatch.DecodePatch(override.Patch.Raw)
+		if err != nil {
+			return nil, err
+		}
+		return patch.Apply(doc)
+	case clustersv1alpha1.StrategicMergePatchType:
+		return strategicpatch.StrategicMergePatch(doc, override.Patch.Raw, template)
+	default:
+		return jsonpatch.MergePatch(doc, override.Patch.Raw)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package mcagent
+
+import (
+	"testing"
+
+	asserts "github.com/stretchr/testify/assert"
+	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// TestApplyClusterOverrides tests the applyClusterOverrides function
+// GIVEN the template of a multi-cluster object and overrides with each type of patch
+// WHEN the overrides are applied for this cluster
+// THEN the patches of the overrides that name or select this cluster are applied in order, and the other
+//      overrides are ignored
+func TestApplyClusterOverrides(t *testing.T) {
+	assert := asserts.New(t)
+
+	testMCConfigMap, err := getSampleMCConfigMap("testdata/multicluster-configmap.yaml")
+	assert.NoError(err, "failed to read sample data for MultiClusterConfigMap")
+	s := &Syncer{
+		ManagedClusterName:   testClusterName,
+		ManagedClusterLabels: map[string]string{"region": "us-east"},
+	}
+	overrides := []clustersv1alpha1.ClusterOverride{
+		{
+			ClusterName: testClusterName,
+			Patch:       runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"region":"us-east"}},"data":{"simple.key":"merge"}}`)},
+		},
+		{
+			ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "us-east"}},
+			PatchType:       clustersv1alpha1.MergePatchType,
+			Patch:           runtime.RawExtension{Raw: []byte(`{"data":{"yaml.key":null,"region.key":"us-east"}}`)},
+		},
+		{
+			ClusterName: testClusterName,
+			PatchType:   clustersv1alpha1.JSONPatchType,
+			Patch:       runtime.RawExtension{Raw: []byte(`[{"op":"replace","path":"/data/simple.key","value":"json"}]`)},
+		},
+		{
+			ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "us-west"}},
+			PatchType:       clustersv1alpha1.MergePatchType,
+			Patch:           runtime.RawExtension{Raw: []byte(`{"data":{"simple.key":"us-west"}}`)},
+		},
+	}
+	template := testMCConfigMap.Spec.Template
+	assert.NoError(s.applyClusterOverrides(overrides, &template))
+	assert.Equal("json", template.Data["simple.key"])
+	assert.Equal("us-east", template.Data["region.key"])
+	assert.NotContains(template.Data, "yaml.key")
+	assert.Equal(testMCConfigMap.Spec.Template.Data["json.key"], template.Data["json.key"])
+	assert.Equal(map[string]string{"region": "us-east"}, template.Metadata.Labels)
+	assert.Equal("simplevalue", testMCConfigMap.Spec.Template.Data["simple.key"], "the original template was changed")
+
+	// An override with a patch that cannot be applied is an error
+	template = testMCConfigMap.Spec.Template
+	err = s.applyClusterOverrides([]clustersv1alpha1.ClusterOverride{{
+		ClusterName: testClusterName,
+		PatchType:   clustersv1alpha1.JSONPatchType,
+		Patch:       runtime.RawExtension{Raw: []byte(`[{"op":"remove","path":"/data/missing.key"}]`)},
+	}}, &template)
+	assert.Error(err)
+}
+
+// TestApplyClusterOverridesWorkload tests the applyClusterOverrides function
+// GIVEN the template of a MultiClusterComponent with an embedded workload
+// WHEN a json patch of the workload is applied for this cluster
+// THEN the workload is patched
+func TestApplyClusterOverridesWorkload(t *testing.T) {
+	assert := asserts.New(t)
+
+	template := clustersv1alpha1.ComponentTemplate{}
+	template.Spec.Workload = runtime.RawExtension{Raw: []byte(`{"kind":"Deployment","spec":{"replicas":1}}`)}
+	s := &Syncer{ManagedClusterName: testClusterName}
+	err := s.applyClusterOverrides([]clustersv1alpha1.ClusterOverride{{
+		ClusterName: testClusterName,
+		PatchType:   clustersv1alpha1.JSONPatchType,
+		Patch:       runtime.RawExtension{Raw: []byte(`[{"op":"replace","path":"/spec/workload/spec/replicas","value":3}]`)},
+	}}, &template)
+	assert.NoError(err)
+	assert.JSONEq(`{"kind":"Deployment","spec":{"replicas":3}}`, string(template.Spec.Workload.Raw))
+}
+
+// TestApplyClusterOverridesWorkloadDefaultPatchType tests the applyClusterOverrides function
+// GIVEN the template of a MultiClusterComponent with an embedded workload
+// WHEN a patch of the workload without a patch type is applied for this cluster
+// THEN the workload is patched with a merge patch
+func TestApplyClusterOverridesWorkloadDefaultPatchType(t *testing.T) {
+	assert := asserts.New(t)
+
+	template := clustersv1alpha1.ComponentTemplate{}
+	template.Spec.Workload = runtime.RawExtension{Raw: []byte(`{"kind":"Deployment","spec":{"replicas":1,"paused":true}}`)}
+	s := &Syncer{ManagedClusterName: testClusterName}
+	err := s.applyClusterOverrides([]clustersv1alpha1.ClusterOverride{{
+		ClusterName: testClusterName,
+		Patch:       runtime.RawExtension{Raw: []byte(`{"spec":{"workload":{"spec":{"replicas":3,"paused":null}}}}`)},
+	}}, &template)
+	assert.NoError(err)
+	assert.JSONEq(`{"kind":"Deployment","spec":{"replicas":3}}`, string(template.Spec.Workload.Raw))
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	"github.com/verrazzano/verrazzano/application-operator/controllers/clusters"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -31,6 +32,7 @@ func (s *Syncer) syncMCSecretObjects(namespace string) error {
 					"MultiClusterSecret",
 					types.NamespacedName{Namespace: mcSecret.Namespace, Name: mcSecret.Name})
 			}
+			s.updateMCSecretStatus(mcSecret, err)
 		}
 	}
 
@@ -45,8 +47,8 @@ func (s *Syncer) syncMCSecretObjects(namespace string) error {
 		return nil
 	}
 	for _, mcSecret := range allLocalMCSecrets.Items {
-		// Delete each MultiClusterSecret object that is not on the admin cluster
-		if !secretListContains(&allAdminMCSecrets, mcSecret.Name, mcSecret.Namespace) {
+		// Delete each MultiClusterSecret object that is not on the admin cluster or is no longer placed in this cluster
+		if !s.secretPlacedInThisCluster(&allAdminMCSecrets, mcSecret.Name, mcSecret.Namespace) {
 			err := s.LocalClient.Delete(s.Context, &mcSecret)
 			if err != nil {
 				s.Log.Error(err, fmt.Sprintf("failed to delete MultiClusterSecret with name %q and namespace %q", mcSecret.Name, mcSecret.Namespace))
@@ -63,6 +65,12 @@ func (s *Syncer) createOrUpdateMCSecret(mcSecret clustersv1alpha1.MultiClusterSe
 	mcSecretNew.Namespace = mcSecret.Namespace
 	mcSecretNew.Name = mcSecret.Name
 
+	// Apply the overrides for this cluster to the template
+	err := s.applyClusterOverrides(mcSecret.Spec.Overrides, &mcSecret.Spec.Template)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
 	// Create or update on the local cluster
 	return controllerutil.CreateOrUpdate(s.Context, s.LocalClient, &mcSecretNew, func() error {
 		mutateMCSecret(mcSecret, &mcSecretNew)
@@ -70,6 +78,25 @@ func (s *Syncer) createOrUpdateMCSecret(mcSecret clustersv1alpha1.MultiClusterSe
 	})
 }
 
+// Update the status of this cluster in the MultiClusterSecret on the admin cluster, from the status
+// set by the controller of this cluster
+func (s *Syncer) updateMCSecretStatus(mcSecret clustersv1alpha1.MultiClusterSecret, syncErr error) {
+	name := types.NamespacedName{Namespace: mcSecret.Namespace, Name: mcSecret.Name}
+	localMCSecret := clustersv1alpha1.MultiClusterSecret{}
+	if syncErr == nil {
+		err := s.LocalClient.Get(s.Context, name, &localMCSecret)
+		if err != nil {
+			s.Log.Error(err, "Error getting object", "MultiClusterSecret", name)
+			return
+		}
+	}
+	clusterStatus := clusters.NewClusterLevelStatus(s.ManagedClusterName, localMCSecret.Status.State, localMCSecret.Status.Conditions, syncErr)
+	err := s.updateAdminClusterStatus(&mcSecret, mcSecret.Spec.Placement, &mcSecret.Status.Clusters, &mcSecret.Status.State, clusterStatus)
+	if err != nil {
+		s.Log.Error(err, "Error updating status on the admin cluster", "MultiClusterSecret", name)
+	}
+}
+
 // mutateMCSecret mutates the MultiClusterSecret to reflect the contents of the parent MultiClusterSecret
 func mutateMCSecret(mcSecret clustersv1alpha1.MultiClusterSecret, mcSecretNew *clustersv1alpha1.MultiClusterSecret) {
 	mcSecretNew.Spec.Placement = mcSecret.Spec.Placement
@@ -77,11 +104,12 @@ func mutateMCSecret(mcSecret clustersv1alpha1.MultiClusterSecret, mcSecretNew *c
 	mcSecretNew.Labels = mcSecret.Labels
 }
 
-// secretListContains returns boolean indicating if the list contains the object with the specified name and namespace
-func secretListContains(mcAdminList *clustersv1alpha1.MultiClusterSecretList, name string, namespace string) bool {
+// secretPlacedInThisCluster returns boolean indicating if the list contains the object with the specified name and
+// namespace, and the object is placed in this cluster
+func (s *Syncer) secretPlacedInThisCluster(mcAdminList *clustersv1alpha1.MultiClusterSecretList, name string, namespace string) bool {
 	for _, item := range mcAdminList.Items {
 		if item.Name == name && item.Namespace == namespace {
-			return true
+			return s.isThisCluster(item.Spec.Placement)
 		}
 	}
 	return false
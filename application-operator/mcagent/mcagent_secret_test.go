@@ -43,6 +43,7 @@ func TestCreateMCSecret(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCSecret, err := getSampleMCSecret("testdata/multicluster-secret.yaml")
@@ -76,6 +77,9 @@ func TestCreateMCSecret(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCSecret)
+
 	// Managed Cluster - expect call to list MultiClusterSecret objects - return same list as admin cluster
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterSecretList{}, gomock.Not(gomock.Nil())).
@@ -116,6 +120,7 @@ func TestUpdateMCSecret(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCSecret, err := getSampleMCSecret("testdata/multicluster-secret.yaml")
@@ -154,6 +159,9 @@ func TestUpdateMCSecret(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCSecret)
+
 	// Managed Cluster - expect call to list MultiClusterSecret objects - return same list as admin cluster
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterSecretList{}, gomock.Not(gomock.Nil())).
@@ -182,7 +190,7 @@ func TestUpdateMCSecret(t *testing.T) {
 // TestMCSecretPlacement tests the synchronization method for the following use case.
 // GIVEN a request to sync MultiClusterSecret objects
 // WHEN the a object exists that is not targeted for the cluster
-// THEN ensure that the MultiClusterSecret is not created or updated
+// THEN ensure that the MultiClusterSecret is not created or updated and the local copy is deleted
 func TestMCSecretPlacement(t *testing.T) {
 	assert := asserts.New(t)
 	log := ctrl.Log.WithName("test")
@@ -218,6 +226,11 @@ func TestMCSecretPlacement(t *testing.T) {
 			return nil
 		})
 
+	// Managed Cluster - expect a call to delete the MultiClusterSecret object that is no longer placed in this cluster
+	mcMock.EXPECT().
+		Delete(gomock.Any(), gomock.Eq(&testMCSecret), gomock.Any()).
+		Return(nil)
+
 	// Make the request
 	s := &Syncer{
 		AdminClient:        adminMock,
@@ -249,6 +262,7 @@ func TestDeleteMCSecret(t *testing.T) {
 	// Admin cluster mocks
 	adminMocker := gomock.NewController(t)
 	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
 
 	// Test data
 	testMCSecret, err := getSampleMCSecret("testdata/multicluster-secret.yaml")
@@ -279,6 +293,9 @@ func TestDeleteMCSecret(t *testing.T) {
 			return nil
 		})
 
+	// Expect the status of this cluster to be read and patched on the admin cluster
+	expectStatusUpdateReady(assert, mcMock, adminMock, adminStatusMock, &testMCSecret)
+
 	// Managed Cluster - expect call to list MultiClusterSecret objects - return list including an orphaned object
 	mcMock.EXPECT().
 		List(gomock.Any(), &clustersv1alpha1.MultiClusterSecretList{}, gomock.Not(gomock.Nil())).
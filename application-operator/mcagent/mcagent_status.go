@@ -0,0 +1,126 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package mcagent
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/verrazzano/verrazzano/application-operator/constants"
+	"github.com/verrazzano/verrazzano/application-operator/controllers/clusters"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// The VerrazzanoManagedCluster resources are defined by the platform operator, so they are accessed as
+// unstructured objects
+var vmcGroupVersionKind = schema.GroupVersionKind{Group: "clusters.verrazzano.io", Version: "v1alpha1", Kind: "VerrazzanoManagedCluster"}
+var vmcListGroupVersionKind = schema.GroupVersionKind{Group: "clusters.verrazzano.io", Version: "v1alpha1", Kind: "VerrazzanoManagedClusterList"}
+
+// The Verrazzano resources of the local cluster, which contain the installed version and endpoints
+var verrazzanoListGroupVersionKind = schema.GroupVersionKind{Group: "install.verrazzano.io", Version: "v1alpha1", Kind: "VerrazzanoList"}
+
+// The condition of the VerrazzanoManagedCluster that is set when the agent first connects, meaning that the
+// registration manifest was applied to this cluster
+const manifestPushedCondition = "ManifestPushed"
+
+// Update the status of the VerrazzanoManagedCluster resource of this cluster on the admin cluster with the
+// time the agent connected, the Verrazzano version and the endpoints that are used by this cluster.
+// The admin cluster marks this cluster as unreachable when the agent stops connecting.
+func (s *Syncer) updateVMCStatus() error {
+	vmc := unstructured.Unstructured{}
+	vmc.SetGroupVersionKind(vmcGroupVersionKind)
+	err := s.AdminClient.Get(s.Context, types.NamespacedName{Namespace: constants.VerrazzanoMultiClusterNamespace, Name: s.ManagedClusterName}, &vmc)
+	if err != nil {
+		return err
+	}
+
+	// Save the labels of this cluster, to match the cluster selectors of the multi-cluster objects
+	err = s.updateClusterLabels(vmc.GetLabels())
+	if err != nil {
+		s.Log.Error(err, "Error saving the labels of the VerrazzanoManagedCluster")
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	status := map[string]interface{}{
+		"lastAgentConnectTime": now,
+	}
+	version, prometheusHost := s.getVerrazzanoInfo()
+	if len(version) > 0 {
+		status["version"] = version
+	}
+	if len(prometheusHost) > 0 {
+		status["prometheusHost"] = prometheusHost
+	}
+	esDetails := clusters.FetchManagedClusterElasticSearchDetails(s.Context, s.LocalClient, s.Log)
+	if len(esDetails.Host) > 0 {
+		status["elasticsearchHost"] = fmt.Sprintf("%s:%d", esDetails.Host, esDetails.Port)
+	}
+
+	// A merge patch replaces the conditions, so the conditions are only patched when the agent connects for the
+	// first time, to add the condition that the manifest was applied
+	conditions, _, _ := unstructured.NestedSlice(vmc.Object, "status", "conditions")
+	if !containsCondition(conditions, manifestPushedCondition) {
+		status["conditions"] = append(conditions, map[string]interface{}{
+			"type":               manifestPushedCondition,
+			"status":             "True",
+			"lastTransitionTime": now,
+			"message":            "The registration manifest was applied to the managed cluster",
+		})
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"status": status})
+	if err != nil {
+		return err
+	}
+	return s.AdminClient.Status().Patch(s.Context, &vmc, client.RawPatch(types.MergePatchType, patch))
+}
+
+// Save the labels of the VerrazzanoManagedCluster resource of this cluster, which are matched against the cluster
+// selectors of the multi-cluster objects.  The labels are also saved in a config map of the local cluster for the
+// controllers of the multi-cluster objects.
+func (s *Syncer) updateClusterLabels(labels map[string]string) error {
+	s.ManagedClusterLabels = labels
+	configMap := corev1.ConfigMap{}
+	configMap.Namespace = constants.VerrazzanoSystemNamespace
+	configMap.Name = constants.MCClusterLabelsConfigMap
+	_, err := controllerutil.CreateOrUpdate(s.Context, s.LocalClient, &configMap, func() error {
+		configMap.Data = labels
+		return nil
+	})
+	return err
+}
+
+// Get the version and the Prometheus endpoint of the Verrazzano installation on this cluster.  Empty strings are
+// returned if Verrazzano is not installed.
+func (s *Syncer) getVerrazzanoInfo() (version string, prometheusHost string) {
+	vzList := unstructured.UnstructuredList{}
+	vzList.SetGroupVersionKind(verrazzanoListGroupVersionKind)
+	err := s.LocalClient.List(s.Context, &vzList)
+	if err != nil {
+		s.Log.Info("Failed to list the Verrazzano resources on the local cluster", "err", err)
+		return "", ""
+	}
+	for _, vz := range vzList.Items {
+		version, _, _ = unstructured.NestedString(vz.Object, "status", "version")
+		prometheusHost, _, _ = unstructured.NestedString(vz.Object, "status", "instance", "prometheusUrl")
+		return version, prometheusHost
+	}
+	return "", ""
+}
+
+// Return true if the list of conditions contains a condition of the condition type
+func containsCondition(conditions []interface{}, conditionType string) bool {
+	for _, condition := range conditions {
+		if c, ok := condition.(map[string]interface{}); ok && c["type"] == conditionType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package mcagent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	asserts "github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/application-operator/constants"
+	"github.com/verrazzano/verrazzano/application-operator/controllers/clusters"
+	"github.com/verrazzano/verrazzano/application-operator/mocks"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TestUpdateVMCStatus tests the synchronization method for the following use case.
+// GIVEN a request to update the VerrazzanoManagedCluster status of this cluster
+// WHEN the agent connects to the admin cluster for the first time
+// THEN ensure that the connect time, the version, the endpoints and the ManifestPushed condition are patched,
+//      and the labels of the cluster are saved
+func TestUpdateVMCStatus(t *testing.T) {
+	assert := asserts.New(t)
+	log := ctrl.Log.WithName("test")
+
+	// Managed cluster mocks
+	mcMocker := gomock.NewController(t)
+	mcMock := mocks.NewMockClient(mcMocker)
+
+	// Admin cluster mocks
+	adminMocker := gomock.NewController(t)
+	adminMock := mocks.NewMockClient(adminMocker)
+	adminStatusMock := mocks.NewMockStatusWriter(adminMocker)
+
+	// Admin Cluster - expect call to get the VerrazzanoManagedCluster of this cluster, with the Ready condition
+	adminMock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: constants.VerrazzanoMultiClusterNamespace, Name: testClusterName}, gomock.Not(gomock.Nil())).
+		DoAndReturn(func(ctx context.Context, name types.NamespacedName, vmc *unstructured.Unstructured) error {
+			assert.Equal("VerrazzanoManagedCluster", vmc.GetKind())
+			vmc.SetLabels(map[string]string{"region": "us-east"})
+			vmc.Object["status"] = map[string]interface{}{
+				"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "False"}},
+			}
+			return nil
+		})
+
+	// Managed Cluster - expect calls to get and create the config map with the labels of this cluster
+	mcMock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: constants.VerrazzanoSystemNamespace, Name: constants.MCClusterLabelsConfigMap}, gomock.Not(gomock.Nil())).
+		Return(errors.NewNotFound(schema.GroupResource{Resource: "ConfigMap"}, constants.MCClusterLabelsConfigMap))
+	mcMock.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, configMap *corev1.ConfigMap, opts ...client.CreateOption) error {
+			assert.Equal(map[string]string{"region": "us-east"}, configMap.Data)
+			return nil
+		})
+
+	// Managed Cluster - expect call to list the Verrazzano resources
+	mcMock.EXPECT().
+		List(gomock.Any(), gomock.Not(gomock.Nil())).
+		DoAndReturn(func(ctx context.Context, vzList *unstructured.UnstructuredList, opts ...client.ListOption) error {
+			vzList.Items = append(vzList.Items, unstructured.Unstructured{Object: map[string]interface{}{
+				"status": map[string]interface{}{
+					"version":  "0.11.0",
+					"instance": map[string]interface{}{"prometheusUrl": "https://prometheus.vmi.system.example.com"},
+				},
+			}})
+			return nil
+		})
+
+	// Managed Cluster - expect call to get the cluster registration secret
+	mcMock.EXPECT().
+		Get(gomock.Any(), clusters.MCRegistrationSecretFullName, gomock.Not(gomock.Nil())).
+		DoAndReturn(func(ctx context.Context, name types.NamespacedName, secret *corev1.Secret) error {
+			secret.Data = map[string][]byte{
+				constants.ElasticsearchHostData: []byte("es.example.com"),
+				constants.ElasticsearchPortData: []byte("443"),
+			}
+			return nil
+		})
+
+	// Admin Cluster - expect call to patch the VerrazzanoManagedCluster status
+	adminMock.EXPECT().Status().Return(adminStatusMock)
+	adminStatusMock.EXPECT().
+		Patch(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, vmc runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+			assert.Equal(types.MergePatchType, patch.Type())
+			data, err := patch.Data(vmc)
+			assert.NoError(err)
+			patched := map[string]map[string]interface{}{}
+			assert.NoError(json.Unmarshal(data, &patched))
+			status := patched["status"]
+			assert.NotEmpty(status["lastAgentConnectTime"], "The connect time was not patched")
+			assert.Equal("0.11.0", status["version"])
+			assert.Equal("https://prometheus.vmi.system.example.com", status["prometheusHost"])
+			assert.Equal("es.example.com:443", status["elasticsearchHost"])
+			conditions := status["conditions"].([]interface{})
+			assert.Len(conditions, 2, "The existing condition should be kept")
+			assert.Equal(manifestPushedCondition, conditions[1].(map[string]interface{})["type"])
+			return nil
+		})
+
+	// Make the request
+	s := &Syncer{
+		AdminClient:        adminMock,
+		LocalClient:        mcMock,
+		Log:                log,
+		ManagedClusterName: testClusterName,
+		Context:            context.TODO(),
+	}
+	err := s.updateVMCStatus()
+
+	// Validate the results
+	adminMocker.Finish()
+	mcMocker.Finish()
+	assert.NoError(err)
+	assert.Equal(map[string]string{"region": "us-east"}, s.ManagedClusterLabels)
+}
+
+// TestContainsCondition tests the containsCondition function
+// GIVEN a list of unstructured conditions
+// WHEN containsCondition is called
+// THEN true is returned only if a condition of the condition type is in the list
+func TestContainsCondition(t *testing.T) {
+	assert := asserts.New(t)
+	conditions := []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}}
+	assert.True(containsCondition(conditions, "Ready"))
+	assert.False(containsCondition(conditions, manifestPushedCondition))
+	assert.False(containsCondition(nil, manifestPushedCondition))
+}
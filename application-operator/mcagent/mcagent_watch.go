@@ -0,0 +1,282 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package mcagent
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	"github.com/verrazzano/verrazzano/application-operator/constants"
+	"github.com/verrazzano/verrazzano/application-operator/controllers"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The periods of the full resync of the multi-cluster objects and of the status updates of this cluster on the
+// admin cluster.  The full resync is a safety net for missed watch events.
+var (
+	resyncPeriod    = 10 * time.Minute
+	heartbeatPeriod = 1 * time.Minute
+)
+
+// The time to wait for the watches of the admin cluster to be established
+var cacheSyncTimeout = 2 * time.Minute
+
+// The backoff between attempts to connect to the admin cluster
+var (
+	minReconnectBackoff = 5 * time.Second
+	maxReconnectBackoff = 5 * time.Minute
+)
+
+// The number of consecutive status updates that can fail before the watches are restarted
+const maxHeartbeatFailures = 3
+
+// newAdminCache creates the cache that watches the multi-cluster objects in the given namespaces of the admin
+// cluster.  It is a variable so that it can be replaced by the unit tests.
+var newAdminCache = func(config *rest.Config, namespaces []string) (cache.Cache, error) {
+	return cache.MultiNamespacedCacheBuilder(namespaces)(config, cache.Options{Scheme: newAdminScheme(), Resync: &resyncPeriod})
+}
+
+// syncKey is the work queue item that identifies the objects to sync, all the objects of a kind in a namespace
+type syncKey struct {
+	kind      string
+	namespace string
+}
+
+// The work queue items that are not for multi-cluster objects
+var (
+	projectKey   = syncKey{kind: "VerrazzanoProject", namespace: constants.VerrazzanoMultiClusterNamespace}
+	heartbeatKey = syncKey{kind: "VerrazzanoManagedCluster", namespace: constants.VerrazzanoMultiClusterNamespace}
+)
+
+// The kind of the multi-cluster application configurations, which are synced after the objects they depend on
+const mcAppConfigKind = "MultiClusterApplicationConfiguration"
+
+// mcKinds are the multi-cluster objects that are watched in the project namespaces, with the function that syncs
+// the objects of a namespace
+var mcKinds = []struct {
+	kind string
+	obj  runtime.Object
+	sync func(s *Syncer, namespace string) error
+}{
+	{"MultiClusterSecret", &clustersv1alpha1.MultiClusterSecret{}, (*Syncer).syncMCSecretObjects},
+	{"MultiClusterConfigMap", &clustersv1alpha1.MultiClusterConfigMap{}, (*Syncer).syncMCConfigMapObjects},
+	{"MultiClusterLoggingScope", &clustersv1alpha1.MultiClusterLoggingScope{}, (*Syncer).syncMCLoggingScopeObjects},
+	{"MultiClusterComponent", &clustersv1alpha1.MultiClusterComponent{}, (*Syncer).syncMCComponentObjects},
+	{"MultiClusterApplicationConfiguration", &clustersv1alpha1.MultiClusterApplicationConfiguration{}, (*Syncer).syncMCApplicationConfigurationObjects},
+}
+
+// watchAndSync watches the VerrazzanoProject resources and the multi-cluster objects in the project namespaces
+// of the admin cluster, and syncs the objects of a kind in a namespace whenever one of them is added, updated or
+// deleted.  All the objects are also synced periodically.  The function returns nil when the project namespaces
+// change, so that the watches can be restarted for the new namespaces, and an error when the admin cluster
+// cannot be reached.
+func (s *Syncer) watchAndSync() error {
+	// Sync the projects first to find the namespaces to watch
+	err := s.syncVerrazzanoProjects()
+	if err != nil {
+		return fmt.Errorf("failed to sync the VerrazzanoProject objects, %v", err)
+	}
+	projectNamespaces := append([]string{}, s.ProjectNamespaces...)
+	namespaces := []string{constants.VerrazzanoMultiClusterNamespace}
+	for _, namespace := range projectNamespaces {
+		if !controllers.StringSliceContainsString(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	s.Log.Info(fmt.Sprintf("Watching the multi-cluster objects in the namespaces %v", namespaces))
+
+	adminCache, err := newAdminCache(s.AdminConfig, namespaces)
+	if err != nil {
+		return err
+	}
+
+	// Read the objects from the cache while it is running
+	directClient := s.AdminClient
+	s.AdminClient = client.DelegatingClient{
+		Reader:       &client.DelegatingReader{CacheReader: adminCache, ClientReader: directClient},
+		Writer:       directClient,
+		StatusClient: directClient,
+	}
+	defer func() { s.AdminClient = directClient }()
+
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+
+	// Queue the sync of the objects of a kind in a namespace when one of them changes
+	informer, err := adminCache.GetInformer(s.Context, &clustersv1alpha1.VerrazzanoProject{})
+	if err != nil {
+		return err
+	}
+	informer.AddEventHandler(newEventHandler(queue, func(namespace string) (syncKey, bool) {
+		return projectKey, namespace == constants.VerrazzanoMultiClusterNamespace
+	}))
+	for _, mcKind := range mcKinds {
+		kind := mcKind.kind
+		informer, err := adminCache.GetInformer(s.Context, mcKind.obj)
+		if err != nil {
+			return err
+		}
+		informer.AddEventHandler(newEventHandler(queue, func(namespace string) (syncKey, bool) {
+			return syncKey{kind: kind, namespace: namespace}, controllers.StringSliceContainsString(projectNamespaces, namespace)
+		}))
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		if err := adminCache.Start(stop); err != nil {
+			s.Log.Error(err, "Error watching the multi-cluster objects on the admin cluster")
+		}
+	}()
+	syncStop := make(chan struct{})
+	timer := time.AfterFunc(cacheSyncTimeout, func() { close(syncStop) })
+	synced := adminCache.WaitForCacheSync(syncStop)
+	timer.Stop()
+	if !synced {
+		return errors.New("timed out waiting for the watches of the multi-cluster objects on the admin cluster")
+	}
+
+	// Periodically update the status of this cluster and resync all the objects.  The multi-cluster objects are
+	// also synced on each heartbeat, from the cache, to report the status set by the controllers of this cluster
+	// to the admin cluster.
+	// The status of this cluster is updated first, which reads the labels of this cluster that are matched against
+	// the cluster selectors of the objects.
+	queue.Add(heartbeatKey)
+	queueObjects := func() {
+		for _, namespace := range projectNamespaces {
+			for _, mcKind := range mcKinds {
+				queue.Add(syncKey{kind: mcKind.kind, namespace: namespace})
+			}
+		}
+	}
+	go wait.Until(func() {
+		queue.Add(heartbeatKey)
+		queueObjects()
+	}, heartbeatPeriod, stop)
+	go wait.Until(func() {
+		queue.Add(projectKey)
+		queueObjects()
+	}, resyncPeriod, stop)
+
+	// Process the queue until the project namespaces change or the admin cluster is unreachable
+	heartbeatFailures := 0
+	for {
+		item, shutdown := queue.Get()
+		if shutdown {
+			return nil
+		}
+		key := item.(syncKey)
+		switch key {
+		case heartbeatKey:
+			// Update the status of this cluster on the admin cluster, so that the admin cluster knows the
+			// agent is connected.  The status update is not retried until the next heartbeat.
+			err = s.updateVMCStatus()
+			if err != nil && !k8serrors.IsNotFound(err) {
+				heartbeatFailures++
+				if heartbeatFailures >= maxHeartbeatFailures {
+					queue.Done(item)
+					return fmt.Errorf("failed to update the VerrazzanoManagedCluster status %d times, %v", heartbeatFailures, err)
+				}
+			} else {
+				heartbeatFailures = 0
+			}
+			if err != nil {
+				s.Log.Error(err, "Error updating the VerrazzanoManagedCluster status")
+			}
+			queue.Done(item)
+			continue
+		case projectKey:
+			err = s.syncVerrazzanoProjects()
+			if err == nil && !sameNamespaces(projectNamespaces, s.ProjectNamespaces) {
+				s.Log.Info(fmt.Sprintf("The project namespaces changed to %v, restarting the watches", s.ProjectNamespaces))
+				queue.Done(item)
+				return nil
+			}
+		default:
+			err = syncKind(s, key)
+			if err == nil && key.kind != mcAppConfigKind {
+				// The application configurations may be waiting for the objects that were synced
+				queue.Add(syncKey{kind: mcAppConfigKind, namespace: key.namespace})
+			}
+		}
+		if err != nil {
+			s.Log.Error(err, fmt.Sprintf("Error syncing %s objects in namespace %s", key.kind, key.namespace))
+			queue.AddRateLimited(item)
+		} else {
+			queue.Forget(item)
+		}
+		queue.Done(item)
+	}
+}
+
+// syncKind syncs the multi-cluster objects of the kind and namespace of the key.  The labels of the clusters are
+// read again for each sync, so that the cluster selectors are matched against the current labels.
+func syncKind(s *Syncer, key syncKey) error {
+	s.clusterLabels = nil
+	for _, mcKind := range mcKinds {
+		if mcKind.kind == key.kind {
+			return mcKind.sync(s, key.namespace)
+		}
+	}
+	return fmt.Errorf("unknown multi-cluster kind %s", key.kind)
+}
+
+// newEventHandler creates an informer event handler that adds the key returned by keyFunc for the namespace of
+// the changed object to the queue.  The object is ignored when keyFunc returns false.
+func newEventHandler(queue workqueue.Interface, keyFunc func(namespace string) (syncKey, bool)) toolscache.ResourceEventHandler {
+	enqueue := func(obj interface{}) {
+		if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return
+		}
+		if key, ok := keyFunc(accessor.GetNamespace()); ok {
+			queue.Add(key)
+		}
+	}
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueue,
+		UpdateFunc: func(oldObj, newObj interface{}) { enqueue(newObj) },
+		DeleteFunc: enqueue,
+	}
+}
+
+// sameNamespaces returns true if the two lists contain the same namespaces, in any order
+func sameNamespaces(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string{}, a...)
+	b = append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// nextReconnectBackoff doubles the backoff between attempts to connect to the admin cluster, up to the maximum
+func nextReconnectBackoff(backoff time.Duration) time.Duration {
+	backoff = backoff * 2
+	if backoff > maxReconnectBackoff {
+		return maxReconnectBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package mcagent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	asserts "github.com/stretchr/testify/assert"
+	clustersv1alpha1 "github.com/verrazzano/verrazzano/application-operator/apis/clusters/v1alpha1"
+	"github.com/verrazzano/verrazzano/application-operator/constants"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeAdminCache is a cache with fake informers that reads the objects from the admin cluster client
+type fakeAdminCache struct {
+	*informertest.FakeInformers
+	reader client.Reader
+}
+
+func (c *fakeAdminCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	return c.reader.Get(ctx, key, obj)
+}
+
+func (c *fakeAdminCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	return c.reader.List(ctx, list, opts...)
+}
+
+// TestWatchAndSync tests the watchAndSync function
+// GIVEN a VerrazzanoProject and a MultiClusterSecret on the admin cluster
+// WHEN the watches of the admin cluster are started
+// THEN the MultiClusterSecret is synced to the local cluster, the local MultiClusterSecret is deleted when the
+//      admin MultiClusterSecret is deleted, and the function returns when the project namespaces change
+func TestWatchAndSync(t *testing.T) {
+	assert := asserts.New(t)
+
+	testMCSecret, err := getSampleMCSecret("testdata/multicluster-secret.yaml")
+	assert.NoError(err, "failed to get sample secret data")
+	testProject := clustersv1alpha1.VerrazzanoProject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: constants.VerrazzanoMultiClusterNamespace, Name: "test-project"},
+		Spec:       clustersv1alpha1.VerrazzanoProjectSpec{Namespaces: []string{testMCSecretNamespace}},
+	}
+	scheme := newAdminScheme()
+	adminClient := fake.NewFakeClientWithScheme(scheme, &testProject, &testMCSecret)
+	localClient := fake.NewFakeClientWithScheme(scheme)
+
+	informers := &informertest.FakeInformers{Scheme: scheme}
+	defer setAdminCache(func(config *rest.Config, namespaces []string) (cache.Cache, error) {
+		assert.Equal([]string{constants.VerrazzanoMultiClusterNamespace, testMCSecretNamespace}, namespaces)
+		return &fakeAdminCache{FakeInformers: informers, reader: adminClient}, nil
+	})()
+
+	s := &Syncer{
+		AdminClient:        adminClient,
+		LocalClient:        localClient,
+		Log:                ctrl.Log.WithName("test"),
+		ManagedClusterName: testClusterName,
+		Context:            context.TODO(),
+	}
+	done := make(chan error)
+	go func() { done <- s.watchAndSync() }()
+
+	// The MultiClusterSecret is synced by the initial resync
+	mcSecretName := types.NamespacedName{Namespace: testMCSecretNamespace, Name: testMCSecretName}
+	assert.Eventually(func() bool {
+		return localClient.Get(context.TODO(), mcSecretName, &clustersv1alpha1.MultiClusterSecret{}) == nil
+	}, 5*time.Second, 10*time.Millisecond, "the MultiClusterSecret was not synced")
+
+	// The local MultiClusterSecret is deleted when the watch reports the deletion
+	assert.NoError(adminClient.Delete(context.TODO(), &testMCSecret))
+	secretInformer, err := informers.FakeInformerFor(&clustersv1alpha1.MultiClusterSecret{})
+	assert.NoError(err)
+	secretInformer.Delete(&testMCSecret)
+	assert.Eventually(func() bool {
+		return localClient.Get(context.TODO(), mcSecretName, &clustersv1alpha1.MultiClusterSecret{}) != nil
+	}, 5*time.Second, 10*time.Millisecond, "the MultiClusterSecret was not deleted")
+
+	// The watches are restarted when the project namespaces change
+	testProject.Spec.Namespaces = append(testProject.Spec.Namespaces, "new-namespace")
+	assert.NoError(adminClient.Update(context.TODO(), &testProject))
+	projectInformer, err := informers.FakeInformerFor(&clustersv1alpha1.VerrazzanoProject{})
+	assert.NoError(err)
+	projectInformer.Update(&testProject, &testProject)
+	select {
+	case err = <-done:
+		assert.NoError(err)
+		assert.Equal(testProject.Spec.Namespaces, s.ProjectNamespaces)
+		assert.Equal(adminClient, s.AdminClient, "the admin client was not restored")
+	case <-time.After(5 * time.Second):
+		assert.Fail("the watches were not restarted")
+	}
+}
+
+// TestWatchAndSyncTimeout tests the watchAndSync function
+// GIVEN an admin cluster that is not reachable
+// WHEN the watches of the admin cluster do not sync
+// THEN an error is returned
+func TestWatchAndSyncTimeout(t *testing.T) {
+	assert := asserts.New(t)
+
+	scheme := newAdminScheme()
+	adminClient := fake.NewFakeClientWithScheme(scheme)
+	synced := false
+	defer setAdminCache(func(config *rest.Config, namespaces []string) (cache.Cache, error) {
+		return &fakeAdminCache{FakeInformers: &informertest.FakeInformers{Scheme: scheme, Synced: &synced}, reader: adminClient}, nil
+	})()
+	savedTimeout := cacheSyncTimeout
+	cacheSyncTimeout = 10 * time.Millisecond
+	defer func() { cacheSyncTimeout = savedTimeout }()
+
+	s := &Syncer{
+		AdminClient:        adminClient,
+		LocalClient:        fake.NewFakeClientWithScheme(scheme),
+		Log:                ctrl.Log.WithName("test"),
+		ManagedClusterName: testClusterName,
+		Context:            context.TODO(),
+	}
+	assert.Error(s.watchAndSync())
+}
+
+// TestNewEventHandler tests the event handler of the informers
+// GIVEN events for objects in the watched namespaces
+// WHEN the event handler is called
+// THEN the key of the objects in the project namespaces is queued, including for deleted objects with a
+//      final state that is unknown
+func TestNewEventHandler(t *testing.T) {
+	assert := asserts.New(t)
+
+	queue := workqueue.New()
+	defer queue.ShutDown()
+	handler := newEventHandler(queue, func(namespace string) (syncKey, bool) {
+		return syncKey{kind: "test", namespace: namespace}, namespace == "project"
+	})
+
+	handler.OnAdd(&clustersv1alpha1.MultiClusterSecret{ObjectMeta: metav1.ObjectMeta{Namespace: "other"}})
+	assert.Equal(0, queue.Len())
+
+	handler.OnUpdate(nil, &clustersv1alpha1.MultiClusterSecret{ObjectMeta: metav1.ObjectMeta{Namespace: "project"}})
+	handler.OnDelete(toolscache.DeletedFinalStateUnknown{Obj: &clustersv1alpha1.MultiClusterSecret{ObjectMeta: metav1.ObjectMeta{Namespace: "project"}}})
+	assert.Equal(1, queue.Len())
+	item, _ := queue.Get()
+	assert.Equal(syncKey{kind: "test", namespace: "project"}, item)
+}
+
+// TestReconnectBackoff tests the nextReconnectBackoff and sameNamespaces functions
+// GIVEN a backoff or a list of namespaces
+// WHEN the functions are called
+// THEN the backoff is doubled up to the maximum, and the namespaces are compared in any order
+func TestReconnectBackoff(t *testing.T) {
+	assert := asserts.New(t)
+
+	assert.Equal(2*minReconnectBackoff, nextReconnectBackoff(minReconnectBackoff))
+	assert.Equal(maxReconnectBackoff, nextReconnectBackoff(maxReconnectBackoff))
+
+	assert.True(sameNamespaces([]string{"a", "b"}, []string{"b", "a"}))
+	assert.False(sameNamespaces([]string{"a", "b"}, []string{"a"}))
+	assert.False(sameNamespaces([]string{"a", "b"}, []string{"a", "c"}))
+}
+
+// setAdminCache replaces the function that creates the admin cluster cache, and returns a function that
+// restores it
+func setAdminCache(f func(config *rest.Config, namespaces []string) (cache.Cache, error)) func() {
+	saved := newAdminCache
+	newAdminCache = f
+	return func() { newAdminCache = saved }
+}
@@ -4,6 +4,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -34,13 +35,77 @@ type VerrazzanoManagedClusterSpec struct {
 	ManagedClusterManifestSecret string `json:"managedClusterManifestSecret,omitempty"`
 }
 
+// ConditionType identifies the condition of the managed cluster which can be checked with kubectl wait
+type ConditionType string
+
+const (
+	// ConditionReady means the multi-cluster agent of the managed cluster is connected to the admin cluster
+	ConditionReady ConditionType = "Ready"
+
+	// ConditionManifestPushed means the registration manifest was applied to the managed cluster
+	ConditionManifestPushed ConditionType = "ManifestPushed"
+)
+
+// Condition describes a condition that occurred on the managed cluster
+type Condition struct {
+	// Type of condition.
+	Type ConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// Last time the condition transitioned from one status to another.
+	// +optional
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+	// Human readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// StateType identifies the state of the managed cluster
+type StateType string
+
+const (
+	// StatePending is the state when the multi-cluster agent of the managed cluster has not connected yet
+	StatePending StateType = "Pending"
+
+	// StateActive is the state when the multi-cluster agent of the managed cluster is connected
+	StateActive StateType = "Active"
+
+	// StateUnreachable is the state when the multi-cluster agent of the managed cluster has not connected
+	// to the admin cluster recently
+	StateUnreachable StateType = "Unreachable"
+)
+
 // VerrazzanoManagedClusterStatus defines the observed state of VerrazzanoManagedCluster
 type VerrazzanoManagedClusterStatus struct {
+	// The latest available observations of the managed cluster's current state.
+	Conditions []Condition `json:"conditions,omitempty"`
+
+	// The state of the managed cluster, derived from the last time the multi-cluster agent connected.
+	State StateType `json:"state,omitempty"`
+
+	// The last time the multi-cluster agent of the managed cluster connected to the admin cluster.
+	// This field is updated by the multi-cluster agent.
+	LastAgentConnectTime *metav1.Time `json:"lastAgentConnectTime,omitempty"`
+
+	// The version of Verrazzano that is installed on the managed cluster.
+	// This field is updated by the multi-cluster agent.
+	Version string `json:"version,omitempty"`
+
+	// The Prometheus endpoint of the managed cluster.
+	// This field is updated by the multi-cluster agent.
+	PrometheusHost string `json:"prometheusHost,omitempty"`
+
+	// The Elasticsearch endpoint that the managed cluster sends its logs to.
+	// This field is updated by the multi-cluster agent.
+	ElasticsearchHost string `json:"elasticsearchHost,omitempty"`
 }
 
 // VerrazzanoManagedCluster is the Schema for the Verrazzanomanagedclusters API
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="The state of the managed cluster"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="The version of Verrazzano on the managed cluster"
+// +kubebuilder:printcolumn:name="Last Connect",type="date",JSONPath=".status.lastAgentConnectTime",description="The last time the agent of the managed cluster connected"
 // +kubebuilder:resource:shortName=vmc;vmcs
 // +genclient
 type VerrazzanoManagedCluster struct {
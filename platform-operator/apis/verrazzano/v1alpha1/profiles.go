@@ -0,0 +1,201 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/verrazzano/verrazzano/platform-operator/internal/certificate"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ProfileLabel is the label of the ConfigMaps in the operator namespace that define custom install
+	// profiles.  The value of the label is the name of the profile.
+	ProfileLabel = "install.verrazzano.io/profile"
+	// ProfileDataKey is the key of the profile definition in the data of a profile ConfigMap
+	ProfileDataKey = "profile.yaml"
+)
+
+// ProfileDefinition is the definition of an install profile.  Profiles are defined by files in the profiles
+// directory of the helm config, or by ConfigMaps in the operator namespace.
+type ProfileDefinition struct {
+	// Base is the built-in profile, dev or prod, that the profile is based on.  The base profile is used by
+	// the install scripts and determines the cluster requirements.  Default is prod.
+	// +optional
+	Base ProfileType `json:"base,omitempty"`
+	// Spec provides the default settings of the Verrazzano resources that use the profile, such as the
+	// enabled components, storage sizes and helm overrides
+	// +optional
+	Spec VerrazzanoSpec `json:"spec,omitempty"`
+}
+
+// ProfilesDir returns the directory that contains the profile definition files
+func ProfilesDir() string {
+	return filepath.Join(config.Get().HelmConfigDir, "profiles")
+}
+
+// GetProfile returns the definition of a profile.  The profile ConfigMaps are checked first, then the profile
+// definition files.  The dev and prod profiles always exist, even if there is no definition for them.
+// Custom profiles can only be defined by ConfigMaps when a client is provided.
+func GetProfile(ctx context.Context, c client.Client, name ProfileType) (*ProfileDefinition, error) {
+	if len(name) == 0 {
+		name = Prod
+	}
+	data, found, err := getProfileConfigMap(ctx, c, name)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		data, found, err = getProfileFile(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !found {
+		if name == Dev || name == Prod {
+			return &ProfileDefinition{Base: name}, nil
+		}
+		return nil, fmt.Errorf("Profile %s not found", name)
+	}
+
+	profile := &ProfileDefinition{}
+	if err := yaml.Unmarshal(data, profile); err != nil {
+		return nil, fmt.Errorf("Profile %s is not valid: %v", name, err)
+	}
+	if len(profile.Base) == 0 {
+		profile.Base = Prod
+	}
+	if profile.Base != Dev && profile.Base != Prod {
+		return nil, fmt.Errorf("Profile %s has an invalid base profile %s, the base profile must be %s or %s", name, profile.Base, Dev, Prod)
+	}
+	return profile, nil
+}
+
+// getProfileConfigMap returns the profile definition from the ConfigMap that is labeled with the profile name.
+// The built-in profiles can't be redefined by a ConfigMap.
+func getProfileConfigMap(ctx context.Context, c client.Client, name ProfileType) ([]byte, bool, error) {
+	if c == nil || name == Dev || name == Prod {
+		return nil, false, nil
+	}
+	cmList := corev1.ConfigMapList{}
+	err := c.List(ctx, &cmList, client.InNamespace(certificate.OperatorNamespace), client.MatchingLabels{ProfileLabel: string(name)})
+	if err != nil {
+		return nil, false, err
+	}
+	if len(cmList.Items) == 0 {
+		return nil, false, nil
+	}
+	if len(cmList.Items) > 1 {
+		return nil, false, fmt.Errorf("Profile %s is defined by more than one ConfigMap", name)
+	}
+	cm := cmList.Items[0]
+	data, ok := cm.Data[ProfileDataKey]
+	if !ok {
+		return nil, false, fmt.Errorf("key %s not found in profile ConfigMap %s/%s", ProfileDataKey, cm.Namespace, cm.Name)
+	}
+	return []byte(data), true, nil
+}
+
+// getProfileFile returns the profile definition from the file in the profiles directory
+func getProfileFile(name ProfileType) ([]byte, bool, error) {
+	data, err := ioutil.ReadFile(filepath.Join(ProfilesDir(), string(name)+".yaml"))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// GetEffectiveSpec returns the spec with the settings of the profile layered underneath it.  The fields that
+// are not set in the spec, or are set to an empty string, list or object, take the value of the profile.
+// Lists are not merged, a list in the spec replaces the list of the profile.  The profile of the effective
+// spec is the base profile.
+func GetEffectiveSpec(profile *ProfileDefinition, spec *VerrazzanoSpec) (*VerrazzanoSpec, error) {
+	profileValues, err := toValues(&profile.Spec)
+	if err != nil {
+		return nil, err
+	}
+	specValues, err := toValues(spec)
+	if err != nil {
+		return nil, err
+	}
+	mergeSpecValues(profileValues, specValues)
+
+	data, err := json.Marshal(profileValues)
+	if err != nil {
+		return nil, err
+	}
+	effective := &VerrazzanoSpec{}
+	if err := json.Unmarshal(data, effective); err != nil {
+		return nil, err
+	}
+	effective.Profile = profile.Base
+	return effective, nil
+}
+
+// toValues converts a spec to generic JSON values
+func toValues(spec *VerrazzanoSpec) (map[string]interface{}, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// mergeSpecValues merges the values of the spec into the values of the profile.  Nested objects are merged,
+// all other values that are set replace the values of the profile.
+func mergeSpecValues(profileValues map[string]interface{}, specValues map[string]interface{}) {
+	for key, value := range specValues {
+		if isEmptyValue(value) {
+			continue
+		}
+		specMap, specIsMap := value.(map[string]interface{})
+		profileMap, profileIsMap := profileValues[key].(map[string]interface{})
+		if specIsMap && profileIsMap {
+			mergeSpecValues(profileMap, specMap)
+			continue
+		}
+		profileValues[key] = value
+	}
+}
+
+// isEmptyValue returns true if a JSON value is null, or an empty string, list or object
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// ValidateProfile checks that the profile of the spec exists and can be applied to the spec.  The effective
+// spec is returned.
+func ValidateProfile(c client.Client, spec *VerrazzanoSpec) (*VerrazzanoSpec, error) {
+	profile, err := GetProfile(context.TODO(), c, spec.Profile)
+	if err != nil {
+		return nil, err
+	}
+	return GetEffectiveSpec(profile, spec)
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/certificate"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testProfile = `
+base: dev
+spec:
+  environmentName: small
+  components:
+    rancher:
+      enabled: false
+    prometheus:
+      overrides:
+      - values: "server:\n  retention: 1d"
+`
+
+// TestGetProfileFile tests getting the profiles that are defined by files
+// GIVEN the profile definition files of the helm config
+// WHEN GetProfile is called
+// THEN the profile definitions are returned, and the built-in profile is the default
+func TestGetProfileFile(t *testing.T) {
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../../helm_config"})
+
+	profile, err := GetProfile(context.TODO(), nil, "minimal")
+	assert.NoError(t, err)
+	assert.Equal(t, Dev, profile.Base)
+	assert.False(t, IsEnabled(profile.Spec.Components.Rancher.Enabled), "Rancher should be disabled")
+	assert.NotNil(t, profile.Spec.DefaultVolumeSource, "The default volume source should be set")
+
+	profile, err = GetProfile(context.TODO(), nil, "managed-cluster")
+	assert.NoError(t, err)
+	assert.Equal(t, Prod, profile.Base)
+
+	profile, err = GetProfile(context.TODO(), nil, "")
+	assert.NoError(t, err)
+	assert.Equal(t, Prod, profile.Base)
+}
+
+// TestGetProfileConfigMap tests getting a profile that is defined by a ConfigMap
+// GIVEN a ConfigMap in the operator namespace that is labeled with the profile name
+// WHEN GetProfile is called
+// THEN the profile definition of the ConfigMap is returned
+func TestGetProfileConfigMap(t *testing.T) {
+	c := fake.NewFakeClientWithScheme(newScheme(), newProfileConfigMap("small", testProfile))
+
+	profile, err := GetProfile(context.TODO(), c, "small")
+	assert.NoError(t, err)
+	assert.Equal(t, Dev, profile.Base)
+	assert.Equal(t, "small", profile.Spec.EnvironmentName)
+	assert.Len(t, profile.Spec.Components.Prometheus.Overrides, 1)
+}
+
+// TestGetProfileBuiltIn tests getting the built-in profiles
+// GIVEN no profile definition files
+// WHEN GetProfile is called for the dev and prod profiles
+// THEN empty profile definitions are returned
+func TestGetProfileBuiltIn(t *testing.T) {
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "/no-such-dir"})
+	c := fake.NewFakeClientWithScheme(newScheme(), newProfileConfigMap("dev", testProfile))
+
+	profile, err := GetProfile(context.TODO(), c, Dev)
+	assert.NoError(t, err)
+	assert.Equal(t, &ProfileDefinition{Base: Dev}, profile, "The built-in profile can't be redefined by a ConfigMap")
+
+	profile, err = GetProfile(context.TODO(), c, Prod)
+	assert.NoError(t, err)
+	assert.Equal(t, &ProfileDefinition{Base: Prod}, profile)
+}
+
+// TestGetProfileInvalid tests getting profiles that don't exist or are not valid
+// GIVEN profile ConfigMaps that are not valid
+// WHEN GetProfile is called
+// THEN an error is returned
+func TestGetProfileInvalid(t *testing.T) {
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../../helm_config"})
+	c := fake.NewFakeClientWithScheme(newScheme(),
+		newProfileConfigMap("bad-base", "base: large"),
+		newProfileConfigMap("bad-yaml", "- a"))
+
+	_, err := GetProfile(context.TODO(), c, "missing")
+	assert.EqualError(t, err, "Profile missing not found")
+
+	_, err = GetProfile(context.TODO(), c, "bad-base")
+	assert.EqualError(t, err, "Profile bad-base has an invalid base profile large, the base profile must be dev or prod")
+
+	_, err = GetProfile(context.TODO(), c, "bad-yaml")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Profile bad-yaml is not valid")
+	}
+}
+
+// TestGetEffectiveSpec tests layering a profile under a spec
+// GIVEN a profile and a spec that sets some of the same fields
+// WHEN GetEffectiveSpec is called
+// THEN the fields of the spec take precedence, the fields that are only in the profile are used and the
+//      profile of the effective spec is the base profile
+func TestGetEffectiveSpec(t *testing.T) {
+	disabled := false
+	enabled := true
+	profile := &ProfileDefinition{Base: Dev}
+	profile.Spec.EnvironmentName = "profile-env"
+	profile.Spec.Components.Rancher.Enabled = &disabled
+	profile.Spec.Components.Grafana.Enabled = &disabled
+	profile.Spec.Components.Prometheus.Overrides = []Overrides{{Values: "a: 1"}}
+	profile.Spec.Components.Keycloak.KeycloakInstallArgs = []InstallArgs{{Name: "replicas", Value: "1"}}
+
+	spec := &VerrazzanoSpec{Profile: "small", Version: "v0.11.0"}
+	spec.Components.Rancher.Enabled = &enabled
+	spec.Components.Prometheus.Overrides = []Overrides{{Values: "b: 2"}}
+
+	effective, err := GetEffectiveSpec(profile, spec)
+	assert.NoError(t, err)
+	assert.Equal(t, Dev, effective.Profile)
+	assert.Equal(t, "v0.11.0", effective.Version)
+	assert.Equal(t, "profile-env", effective.EnvironmentName)
+	assert.True(t, IsEnabled(effective.Components.Rancher.Enabled), "Rancher should be enabled by the spec")
+	assert.False(t, IsEnabled(effective.Components.Grafana.Enabled), "Grafana should be disabled by the profile")
+	assert.Equal(t, []Overrides{{Values: "b: 2"}}, effective.Components.Prometheus.Overrides)
+	assert.Equal(t, []InstallArgs{{Name: "replicas", Value: "1"}}, effective.Components.Keycloak.KeycloakInstallArgs)
+}
+
+// TestCreateCallbackProfile tests the validation of the profile when the resource is created
+// GIVEN a ValidateCreate() request
+// WHEN the profile doesn't exist, or the profile disables a component that the spec needs
+// THEN an error is returned
+func TestCreateCallbackProfile(t *testing.T) {
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../../helm_config", WebhookValidationEnabled: true, ComponentInstallEnabled: true})
+	getControllerRuntimeClient = func() (client.Client, error) {
+		return fake.NewFakeClientWithScheme(newScheme()), nil
+	}
+	defer func() { getControllerRuntimeClient = getClient }()
+
+	vz := &Verrazzano{Spec: VerrazzanoSpec{Profile: "managed-cluster"}}
+	assert.NoError(t, vz.ValidateCreate())
+
+	vz = &Verrazzano{Spec: VerrazzanoSpec{Profile: "missing"}}
+	assert.EqualError(t, vz.ValidateCreate(), "Profile missing not found")
+
+	enabled := true
+	vz = &Verrazzano{Spec: VerrazzanoSpec{Profile: "managed-cluster"}}
+	vz.Spec.Components.Console.Enabled = &enabled
+	assert.EqualError(t, vz.ValidateCreate(), "Keycloak cannot be disabled while the console is enabled")
+}
+
+// TestCreateCallbackProfileInstallJob tests the ValidateCreate function
+// GIVEN a ValidateCreate() request
+// WHEN the component install is not enabled in the operator
+// THEN the profiles that disable components are valid, the install job skips the disabled components
+func TestCreateCallbackProfileInstallJob(t *testing.T) {
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../../helm_config", WebhookValidationEnabled: true, ComponentInstallEnabled: false})
+	getControllerRuntimeClient = func() (client.Client, error) {
+		return fake.NewFakeClientWithScheme(newScheme()), nil
+	}
+	defer func() { getControllerRuntimeClient = getClient }()
+
+	vz := &Verrazzano{Spec: VerrazzanoSpec{Profile: "managed-cluster"}}
+	assert.NoError(t, vz.ValidateCreate())
+
+	vz = &Verrazzano{Spec: VerrazzanoSpec{Profile: "minimal"}}
+	assert.NoError(t, vz.ValidateCreate())
+
+	vz = &Verrazzano{Spec: VerrazzanoSpec{Profile: "dev"}}
+	assert.NoError(t, vz.ValidateCreate())
+}
+
+// TestUpdateCallbackProfile tests the ValidateUpdate function
+// GIVEN a ValidateUpdate() request for a resource that uses a profile
+// WHEN the update enables a component that needs a component that the profile disables
+// THEN an error is returned
+func TestUpdateCallbackProfile(t *testing.T) {
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../../helm_config", WebhookValidationEnabled: true, ComponentInstallEnabled: true})
+	getControllerRuntimeClient = func() (client.Client, error) {
+		return fake.NewFakeClientWithScheme(newScheme()), nil
+	}
+	defer func() { getControllerRuntimeClient = getClient }()
+
+	oldVz := &Verrazzano{Spec: VerrazzanoSpec{Profile: "minimal"}}
+	vz := &Verrazzano{Spec: VerrazzanoSpec{Profile: "minimal"}}
+	assert.NoError(t, vz.ValidateUpdate(oldVz))
+
+	enabled := true
+	vz.Spec.Components.Grafana.Enabled = &enabled
+	assert.EqualError(t, vz.ValidateUpdate(oldVz), "Prometheus cannot be disabled while Grafana is enabled")
+}
+
+// newProfileConfigMap creates a ConfigMap that defines a profile
+func newProfileConfigMap(name string, profile string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: certificate.OperatorNamespace,
+			Name:      name + "-profile",
+			Labels:    map[string]string{ProfileLabel: name},
+		},
+		Data: map[string]string{ProfileDataKey: profile},
+	}
+}
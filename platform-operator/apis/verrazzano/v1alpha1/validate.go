@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 	"reflect"
 
 	vzcomp "github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
@@ -22,6 +23,25 @@ import (
 // For unit test purposes
 var readFileFunction = ioutil.ReadFile
 
+// upgradePathsFile is the file in the helm config directory that defines the supported upgrade paths
+const upgradePathsFile = "upgrade-paths.yaml"
+
+// upgradePaths is the content of the upgrade paths file.  Upgrades to a target version that doesn't match a
+// rule are allowed from any older version.
+type upgradePaths struct {
+	Rules []upgradePathRule `json:"rules"`
+}
+
+// upgradePathRule defines the oldest version that can be upgraded directly to a range of target versions.
+// Older versions must first be upgraded to the minimum version, so that the migrations that only run in
+// the releases in between are not skipped.
+type upgradePathRule struct {
+	// To is the semver constraint of the target versions the rule applies to
+	To string `json:"to"`
+	// FromMinVersion is the oldest version that can be upgraded directly to the target versions
+	FromMinVersion string `json:"fromMinVersion"`
+}
+
 // GetCurrentChartVersion Load the current Chart.yaml into a chartVersion struct
 func GetCurrentChartVersion() (*semver.SemVersion, error) {
 	chartDir := vzcomp.VzChartDir()
@@ -95,6 +115,13 @@ func ValidateUpgradeRequest(currentSpec *VerrazzanoSpec, newSpec *VerrazzanoSpec
 		if requestedSemVer.IsLessThan(currentSemVer) {
 			return fmt.Errorf("Requested version %s is not newer than current version %s", requestedSemVer.ToString(), currentSemVer.ToString())
 		}
+		rules, err := getUpgradePathRules()
+		if err != nil {
+			return err
+		}
+		if err := validateUpgradePath(rules, currentSemVer, requestedSemVer); err != nil {
+			return err
+		}
 	}
 
 	// If any other field has changed from the stored spec return false
@@ -106,6 +133,180 @@ func ValidateUpgradeRequest(currentSpec *VerrazzanoSpec, newSpec *VerrazzanoSpec
 	return nil
 }
 
+// getUpgradePathRules reads the supported upgrade paths from the helm config directory
+func getUpgradePathRules() ([]upgradePathRule, error) {
+	data, err := ioutil.ReadFile(filepath.Join(config.Get().HelmConfigDir, upgradePathsFile))
+	if err != nil {
+		return nil, err
+	}
+	paths := upgradePaths{}
+	if err := yaml.Unmarshal(data, &paths); err != nil {
+		return nil, fmt.Errorf("Failed parsing the upgrade paths: %v", err)
+	}
+	return paths.Rules, nil
+}
+
+// validateUpgradePath checks the upgrade from the current version to the requested version against the
+// upgrade path rules.  An error naming the version to upgrade to first is returned if the upgrade would
+// skip a required version.
+func validateUpgradePath(rules []upgradePathRule, currentSemVer *semver.SemVersion, requestedSemVer *semver.SemVersion) error {
+	for _, rule := range rules {
+		to, err := semver.NewConstraint(rule.To)
+		if err != nil {
+			return err
+		}
+		fromMin, err := semver.NewSemVersion(rule.FromMinVersion)
+		if err != nil {
+			return err
+		}
+		if !requestedSemVer.Satisfies(to) {
+			continue
+		}
+		if currentSemVer.IsLessThan(fromMin) {
+			return fmt.Errorf("Upgrade from version %s to version %s is not supported, upgrade to version %s first",
+				currentSemVer.ToString(), requestedSemVer.ToString(), fromMin.ToString())
+		}
+	}
+	return nil
+}
+
+// ValidateConfigUpdate ensures that a configuration update of an installed Verrazzano only changes the
+// configuration that can be re-applied to the installed components.  The environment name, the DNS
+// configuration and the certificate issuer type can only be set at install time.
+func ValidateConfigUpdate(currentSpec *VerrazzanoSpec, newSpec *VerrazzanoSpec) error {
+	if newSpec.EnvironmentName != currentSpec.EnvironmentName {
+		return fmt.Errorf("Environment name change is not allowed from %s to %s", currentSpec.EnvironmentName, newSpec.EnvironmentName)
+	}
+	// The DNS overrides can be changed, they are re-applied to the external-dns component by the update
+	currentDNS := currentSpec.Components.DNS
+	newDNS := newSpec.Components.DNS
+	currentDNS.Overrides, newDNS.Overrides = nil, nil
+	if !reflect.DeepEqual(newDNS, currentDNS) {
+		return errors.New("DNS configuration updates are not allowed")
+	}
+	currentCert := currentSpec.Components.CertManager.Certificate
+	newCert := newSpec.Components.CertManager.Certificate
+	if (currentCert.CA == CA{}) != (newCert.CA == CA{}) || (currentCert.Acme == Acme{}) != (newCert.Acme == Acme{}) {
+		return errors.New("Certificate issuer type change is not allowed")
+	}
+	return nil
+}
+
+// ValidateOverrides ensures that each of the component overrides has exactly one source, and that the
+// inline values are a valid YAML document.  The ConfigMaps and Secrets are read when the overrides are
+// applied, so that they can be created after the Verrazzano resource.  The install job doesn't apply the
+// overrides, so they can only be used when the component install is enabled in the operator.
+func ValidateOverrides(spec *VerrazzanoSpec) error {
+	comps := &spec.Components
+	overridesByComp := []struct {
+		name      string
+		overrides []Overrides
+	}{
+		{"certManager", comps.CertManager.Overrides},
+		{"dns", comps.DNS.Overrides},
+		{"ingress", comps.Ingress.Overrides},
+		{"istio", comps.Istio.Overrides},
+		{"keycloak", comps.Keycloak.Overrides},
+		{"keycloak.mysql", comps.Keycloak.MySQL.Overrides},
+		{"grafana", comps.Grafana.Overrides},
+		{"prometheus", comps.Prometheus.Overrides},
+		{"rancher", comps.Rancher.Overrides},
+		{"verrazzano", comps.Verrazzano.Overrides},
+		{"coherenceOperator", comps.CoherenceOperator.Overrides},
+		{"weblogicOperator", comps.WebLogicOperator.Overrides},
+		{"oam", comps.OAM.Overrides},
+		{"applicationOperator", comps.ApplicationOperator.Overrides},
+	}
+	for _, comp := range overridesByComp {
+		if len(comp.overrides) > 0 && !config.Get().ComponentInstallEnabled {
+			return fmt.Errorf("Invalid overrides spec.components.%s.overrides: overrides can only be used when the component install is enabled in the operator", comp.name)
+		}
+		for i, override := range comp.overrides {
+			if err := validateOverride(override); err != nil {
+				return fmt.Errorf("Invalid overrides spec.components.%s.overrides[%d]: %v", comp.name, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateOverride validates a single override
+func validateOverride(override Overrides) error {
+	sources := 0
+	if len(override.Values) > 0 {
+		sources++
+	}
+	if override.ConfigMapRef != nil {
+		sources++
+	}
+	if override.SecretRef != nil {
+		sources++
+	}
+	if sources != 1 {
+		return errors.New("exactly one of values, configMapRef or secretRef must be specified")
+	}
+	if len(override.Values) > 0 {
+		values := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(override.Values), &values); err != nil {
+			return fmt.Errorf("values are not a valid YAML document: %v", err)
+		}
+	}
+	return nil
+}
+
+// IsEnabled returns true unless an optional enabled flag is set to false
+func IsEnabled(enabled *bool) bool {
+	return enabled == nil || *enabled
+}
+
+// IsComponentEnabled returns true if the registered component with the given name is enabled in the spec.
+// MySQL is only installed for Keycloak.  Components that can't be disabled are always enabled.
+func IsComponentEnabled(comps *ComponentSpec, name string) bool {
+	switch name {
+	case "rancher":
+		return IsEnabled(comps.Rancher.Enabled)
+	case "keycloak", "mysql":
+		return IsEnabled(comps.Keycloak.Enabled)
+	case "prometheus":
+		return IsEnabled(comps.Prometheus.Enabled)
+	case "grafana":
+		return IsEnabled(comps.Grafana.Enabled)
+	case "istio-egress":
+		return IsEnabled(comps.Istio.EgressGateway.Enabled)
+	case "istiocoredns":
+		return IsEnabled(comps.Istio.CoreDNS.Enabled)
+	}
+	return true
+}
+
+// ValidateEnabledComponents ensures that the enabled components don't need a component that is disabled.
+// The disabled components are skipped by both the component install and the install job.
+func ValidateEnabledComponents(spec *VerrazzanoSpec) error {
+	comps := &spec.Components
+	if IsEnabled(comps.Console.Enabled) && !IsEnabled(comps.Keycloak.Enabled) {
+		return errors.New("Keycloak cannot be disabled while the console is enabled")
+	}
+	if IsEnabled(comps.Grafana.Enabled) && !IsEnabled(comps.Prometheus.Enabled) {
+		return errors.New("Prometheus cannot be disabled while Grafana is enabled")
+	}
+	return nil
+}
+
+// GetDisabledComponents returns the names of the registered components that are disabled in the spec, followed
+// by the console if it is disabled
+func GetDisabledComponents(comps *ComponentSpec) []string {
+	var disabled []string
+	for _, comp := range vzcomp.GetComponents() {
+		if !IsComponentEnabled(comps, comp.Name()) {
+			disabled = append(disabled, comp.Name())
+		}
+	}
+	if !IsEnabled(comps.Console.Enabled) {
+		disabled = append(disabled, "console")
+	}
+	return disabled
+}
+
 // ValidateActiveInstall enforces that only one install of Verrazzano is allowed.
 func ValidateActiveInstall(client client.Client) error {
 	vzList := &VerrazzanoList{}
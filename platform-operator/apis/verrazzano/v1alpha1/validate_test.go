@@ -53,6 +53,10 @@ func TestValidUpgradeRequestNoCurrentVersion(t *testing.T) {
 // WHEN the new version is valid and the current version is less than the current version
 // THEN ensure no error is returned from ValidateUpgradeRequest
 func TestValidUpgradeRequestCurrentVersionExists(t *testing.T) {
+	defer config.Set(config.Get())
+	helmConfig := config.Get()
+	helmConfig.HelmConfigDir = "../../../helm_config"
+	config.Set(helmConfig)
 	chartYaml := validChartYAML
 	readFileFunction = func(string) ([]byte, error) {
 		return []byte(chartYaml), nil
@@ -71,6 +75,92 @@ func TestValidUpgradeRequestCurrentVersionExists(t *testing.T) {
 	assert.NoError(t, ValidateUpgradeRequest(currentSpec, newSpec))
 }
 
+// TestValidateUpgradePathAllowed Tests the condition for valid upgrade where an upgrade path rule applies
+// GIVEN an edit to update a Verrazzano spec to a new version
+// WHEN the current version is at least the minimum version of the upgrade path rule for the new version
+// THEN ensure no error is returned from ValidateUpgradeRequest
+func TestValidateUpgradePathAllowed(t *testing.T) {
+	defer config.Set(config.Get())
+	helmConfig := config.Get()
+	helmConfig.HelmConfigDir = "../../../helm_config"
+	config.Set(helmConfig)
+	readFileFunction = func(string) ([]byte, error) {
+		return []byte(`version: 0.10.0`), nil
+	}
+	defer func() {
+		readFileFunction = ioutil.ReadFile
+	}()
+	currentSpec := &VerrazzanoSpec{
+		Version: "v0.8.1",
+		Profile: "dev",
+	}
+	newSpec := &VerrazzanoSpec{
+		Version: "v0.10.0",
+		Profile: "dev",
+	}
+	assert.NoError(t, ValidateUpgradeRequest(currentSpec, newSpec))
+}
+
+// TestValidateUpgradePathSkipped Tests the condition where the upgrade skips a required version
+// GIVEN an edit to update a Verrazzano spec to a new version
+// WHEN the current version is older than the minimum version of the upgrade path rule for the new version
+// THEN ensure an error naming the version to upgrade to first is returned from ValidateUpgradeRequest
+func TestValidateUpgradePathSkipped(t *testing.T) {
+	defer config.Set(config.Get())
+	helmConfig := config.Get()
+	helmConfig.HelmConfigDir = "../../../helm_config"
+	config.Set(helmConfig)
+	readFileFunction = func(string) ([]byte, error) {
+		return []byte(`version: 0.10.0`), nil
+	}
+	defer func() {
+		readFileFunction = ioutil.ReadFile
+	}()
+	currentSpec := &VerrazzanoSpec{
+		Version: "v0.7.0",
+		Profile: "dev",
+	}
+	newSpec := &VerrazzanoSpec{
+		Version: "v0.10.0",
+		Profile: "dev",
+	}
+	err := ValidateUpgradeRequest(currentSpec, newSpec)
+	assert.EqualError(t, err, "Upgrade from version 0.7.0 to version 0.10.0 is not supported, upgrade to version 0.8.0 first")
+}
+
+// TestValidateUpgradePathRules Tests the upgrade path rules
+// GIVEN a set of upgrade path rules
+// WHEN upgrades from different versions are validated
+// THEN ensure that only the upgrades that skip a required version are rejected
+func TestValidateUpgradePathRules(t *testing.T) {
+	rules := []upgradePathRule{
+		{To: "0.8.x", FromMinVersion: "v0.7.0"},
+		{To: ">=0.9.0 <1.0.0", FromMinVersion: "v0.8.0"},
+	}
+	tests := []struct {
+		from    string
+		to      string
+		message string
+	}{
+		{from: "v0.6.0", to: "v0.7.5"},
+		{from: "v0.7.0", to: "v0.8.2"},
+		{from: "v0.6.3", to: "v0.8.0", message: "Upgrade from version 0.6.3 to version 0.8.0 is not supported, upgrade to version 0.7.0 first"},
+		{from: "v0.8.0", to: "v0.9.1"},
+		{from: "v0.7.9", to: "v0.9.0", message: "Upgrade from version 0.7.9 to version 0.9.0 is not supported, upgrade to version 0.8.0 first"},
+		{from: "v0.7.0", to: "v1.0.0"},
+	}
+	for _, test := range tests {
+		from, _ := semver.NewSemVersion(test.from)
+		to, _ := semver.NewSemVersion(test.to)
+		err := validateUpgradePath(rules, from, to)
+		if len(test.message) == 0 {
+			assert.NoError(t, err, "Upgrade from %s to %s should be allowed", test.from, test.to)
+			continue
+		}
+		assert.EqualError(t, err, test.message)
+	}
+}
+
 // TestValidUpgradeRequestCurrentVersionExists Tests the condition where both specs are at the same version
 // GIVEN an edit to update a Verrazzano spec to a new version
 // WHEN the new version and the current version are at the latest version
@@ -564,3 +654,173 @@ func TestValidateInProgress(t *testing.T) {
 		assert.Equal(t, "Updates to resource not allowed while install, uninstall or upgrade is in progress", err.Error())
 	}
 }
+
+// TestValidateConfigUpdate tests the validation of configuration updates to an installed Verrazzano
+// GIVEN an edit to the configuration of a Verrazzano spec
+// WHEN the component install args are changed
+// THEN no error is returned from ValidateConfigUpdate
+func TestValidateConfigUpdate(t *testing.T) {
+	currentSpec := &VerrazzanoSpec{
+		Components: ComponentSpec{
+			CertManager: CertManagerComponent{
+				Certificate: Certificate{CA: CA{SecretName: "secret1", ClusterResourceNamespace: "cert-manager"}},
+			},
+		},
+	}
+	newSpec := &VerrazzanoSpec{
+		Components: ComponentSpec{
+			CertManager: CertManagerComponent{
+				Certificate: Certificate{CA: CA{SecretName: "secret2", ClusterResourceNamespace: "cert-manager"}},
+			},
+			Ingress: IngressNginxComponent{
+				NGINXInstallArgs: []InstallArgs{{Name: "arg1", Value: "val1"}},
+			},
+			Keycloak: KeycloakComponent{
+				KeycloakInstallArgs: []InstallArgs{{Name: "arg2", Value: "val2"}},
+			},
+			DNS: DNSComponent{
+				Overrides: []Overrides{{Values: "a: b"}},
+			},
+		},
+	}
+	assert.NoError(t, ValidateConfigUpdate(currentSpec, newSpec))
+}
+
+// TestValidateConfigUpdateNotAllowed tests the validation of configuration updates to an installed Verrazzano
+// GIVEN an edit to the configuration of a Verrazzano spec
+// WHEN the environment name, DNS or certificate issuer type is changed
+// THEN an error is returned from ValidateConfigUpdate
+func TestValidateConfigUpdateNotAllowed(t *testing.T) {
+	currentSpec := &VerrazzanoSpec{
+		Components: ComponentSpec{
+			CertManager: CertManagerComponent{
+				Certificate: Certificate{CA: CA{SecretName: "secret1", ClusterResourceNamespace: "cert-manager"}},
+			},
+		},
+	}
+
+	newSpec := currentSpec.DeepCopy()
+	newSpec.EnvironmentName = "newEnv"
+	err := ValidateConfigUpdate(currentSpec, newSpec)
+	if assert.Error(t, err) {
+		assert.Equal(t, "Environment name change is not allowed from  to newEnv", err.Error())
+	}
+
+	newSpec = currentSpec.DeepCopy()
+	newSpec.Components.DNS.External.Suffix = "example.com"
+	err = ValidateConfigUpdate(currentSpec, newSpec)
+	if assert.Error(t, err) {
+		assert.Equal(t, "DNS configuration updates are not allowed", err.Error())
+	}
+
+	newSpec = currentSpec.DeepCopy()
+	newSpec.Components.CertManager.Certificate = Certificate{Acme: Acme{Provider: LetsEncrypt, EmailAddress: "a@b.com"}}
+	err = ValidateConfigUpdate(currentSpec, newSpec)
+	if assert.Error(t, err) {
+		assert.Equal(t, "Certificate issuer type change is not allowed", err.Error())
+	}
+}
+
+// TestValidateOverrides tests the validation of the component overrides
+// GIVEN overrides that have one source each and valid inline values
+// WHEN ValidateOverrides is called
+// THEN no error is returned
+func TestValidateOverrides(t *testing.T) {
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{ComponentInstallEnabled: true})
+	spec := &VerrazzanoSpec{
+		Components: ComponentSpec{
+			Prometheus: PrometheusComponent{
+				Overrides: []Overrides{
+					{Values: "server:\n  retention: 10d"},
+					{ConfigMapRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "cm"}, Key: "values"}},
+					{SecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "secret"}, Key: "values"}},
+				},
+			},
+		},
+	}
+	assert.NoError(t, ValidateOverrides(spec))
+}
+
+// TestValidateOverridesInvalid tests the validation of invalid component overrides
+// GIVEN overrides that have no source, more than one source or invalid inline values
+// WHEN ValidateOverrides is called
+// THEN an error is returned that identifies the override
+func TestValidateOverridesInvalid(t *testing.T) {
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{ComponentInstallEnabled: true})
+	spec := &VerrazzanoSpec{}
+	spec.Components.Istio.Overrides = []Overrides{{Values: "a: b"}, {}}
+	err := ValidateOverrides(spec)
+	assert.EqualError(t, err, "Invalid overrides spec.components.istio.overrides[1]: exactly one of values, configMapRef or secretRef must be specified")
+
+	spec = &VerrazzanoSpec{}
+	spec.Components.Keycloak.MySQL.Overrides = []Overrides{{Values: "a: b",
+		SecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "secret"}, Key: "values"}}}
+	err = ValidateOverrides(spec)
+	assert.EqualError(t, err, "Invalid overrides spec.components.keycloak.mysql.overrides[0]: exactly one of values, configMapRef or secretRef must be specified")
+
+	spec = &VerrazzanoSpec{}
+	spec.Components.Rancher.Overrides = []Overrides{{Values: "- a\n- b"}}
+	err = ValidateOverrides(spec)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid overrides spec.components.rancher.overrides[0]: values are not a valid YAML document")
+}
+
+// TestValidateOverridesInstallJob tests the validation of the component overrides
+// GIVEN a spec with component overrides
+// WHEN ValidateOverrides is called and the component install is not enabled in the operator
+// THEN an error is returned, since the install job doesn't apply the overrides
+func TestValidateOverridesInstallJob(t *testing.T) {
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{ComponentInstallEnabled: false})
+
+	spec := &VerrazzanoSpec{}
+	assert.NoError(t, ValidateOverrides(spec))
+	spec.Components.Grafana.Overrides = []Overrides{{Values: "a: b"}}
+	assert.EqualError(t, ValidateOverrides(spec),
+		"Invalid overrides spec.components.grafana.overrides: overrides can only be used when the component install is enabled in the operator")
+}
+
+// TestValidateEnabledComponents tests the validation of the enabled components
+// GIVEN specs with combinations of disabled components
+// WHEN ValidateEnabledComponents is called
+// THEN an error is returned for the combinations that are not supported
+func TestValidateEnabledComponents(t *testing.T) {
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{ComponentInstallEnabled: true})
+	disabled := false
+
+	spec := &VerrazzanoSpec{}
+	assert.NoError(t, ValidateEnabledComponents(spec))
+
+	spec.Components.Rancher.Enabled = &disabled
+	spec.Components.Istio.CoreDNS.Enabled = &disabled
+	assert.NoError(t, ValidateEnabledComponents(spec))
+
+	spec.Components.Keycloak.Enabled = &disabled
+	assert.EqualError(t, ValidateEnabledComponents(spec), "Keycloak cannot be disabled while the console is enabled")
+	spec.Components.Console.Enabled = &disabled
+	assert.NoError(t, ValidateEnabledComponents(spec))
+
+	spec.Components.Prometheus.Enabled = &disabled
+	assert.EqualError(t, ValidateEnabledComponents(spec), "Prometheus cannot be disabled while Grafana is enabled")
+	spec.Components.Grafana.Enabled = &disabled
+	assert.NoError(t, ValidateEnabledComponents(spec))
+	assert.ElementsMatch(t, []string{"rancher", "istiocoredns", "mysql", "keycloak", "console", "prometheus", "grafana"},
+		GetDisabledComponents(&spec.Components))
+}
+
+// TestValidateEnabledComponentsInstallJob tests the validation of the enabled components
+// GIVEN a spec with a disabled component
+// WHEN ValidateEnabledComponents is called and the component install is not enabled in the operator
+// THEN no error is returned, the install job skips the disabled components
+func TestValidateEnabledComponentsInstallJob(t *testing.T) {
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{ComponentInstallEnabled: false})
+	disabled := false
+
+	spec := &VerrazzanoSpec{}
+	spec.Components.Rancher.Enabled = &disabled
+	assert.NoError(t, ValidateEnabledComponents(spec))
+}
@@ -30,6 +30,7 @@ const (
 // +kubebuilder:resource:shortName=vz;vzs
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[-1:].type",description="The current status of the install/uninstall"
 // +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="The current version of the Verrazzano installation"
+// +kubebuilder:printcolumn:name="Progress",type="string",JSONPath=".status.installProgress.message",description="The progress of the install"
 // +genclient
 
 // Verrazzano is the Schema for the verrazzanos API
@@ -55,7 +56,8 @@ type VerrazzanoSpec struct {
 	// Version is the Verrazzano version
 	// +optional
 	Version string `json:"version,omitempty"`
-	// Profile is the name of the profile to install.  Default is "prod".
+	// Profile is the name of the profile to install, either a built-in profile (dev or prod) or a custom
+	// profile.  The settings of the profile are used for the fields that are not set in the spec.  Default is "prod".
 	// +optional
 	Profile ProfileType `json:"profile,omitempty"`
 	// EnvironmentName identifies install environment.  Default environment name is "default".
@@ -78,6 +80,60 @@ type VerrazzanoSpec struct {
 	// VolumeClaimSpecTemplates Defines a named set of PVC configurations that can be referenced from components using persistent volumes.
 	// +optional
 	VolumeClaimSpecTemplates []VolumeClaimSpecTemplate `json:"volumeClaimSpecTemplates,omitempty"`
+
+	// InstallPolicy specifies how the operator handles a failed install of the Verrazzano components
+	// +optional
+	InstallPolicy InstallPolicy `json:"installPolicy,omitempty"`
+
+	// UpgradePolicy specifies how the operator handles upgrades of the Verrazzano components
+	// +optional
+	UpgradePolicy UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+	// UninstallPolicy specifies how the operator handles a failed uninstall of the Verrazzano components
+	// +optional
+	UninstallPolicy UninstallPolicy `json:"uninstallPolicy,omitempty"`
+}
+
+// InstallPolicy specifies how the operator handles a failed install of the Verrazzano components
+type InstallPolicy struct {
+	// MaxRetries is the number of times a failed install is retried.  The install job is recreated for each
+	// retry, an install of the components by the operator resumes from the component that failed.  Default is 0,
+	// a failed install is not retried.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries int `json:"maxRetries,omitempty"`
+	// Backoff is the time to wait before the first retry of a failed install, for example 1m.  The time to wait
+	// doubles for each retry after that, up to one hour.  Default is 30s.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+	// RetryOnGenerationChange only counts the failures of the current generation of the resource against
+	// MaxRetries, so that a failed install is retried when the spec is changed.  Default is false.
+	// +optional
+	RetryOnGenerationChange bool `json:"retryOnGenerationChange,omitempty"`
+}
+
+// UpgradePolicy specifies how the operator handles upgrades of the Verrazzano components
+type UpgradePolicy struct {
+	// RollbackOnFailure rolls back the helm release of a component that failed to upgrade.  Default is false.
+	// +optional
+	RollbackOnFailure bool `json:"rollbackOnFailure,omitempty"`
+	// RollbackUpgraded also rolls back the helm releases of the components that were upgraded before the failure,
+	// in the reverse order they were upgraded.  Only used when RollbackOnFailure is true.  Default is false.
+	// +optional
+	RollbackUpgraded bool `json:"rollbackUpgraded,omitempty"`
+	// PreflightOnly runs the upgrade preflight checks and reports the result in the PreflightPassed or
+	// PreflightFailed condition without upgrading any component.  Default is false.
+	// +optional
+	PreflightOnly bool `json:"preflightOnly,omitempty"`
+}
+
+// UninstallPolicy specifies how the operator handles a failed uninstall of the Verrazzano components
+type UninstallPolicy struct {
+	// RemoveFinalizerOnFailure removes the finalizer when the uninstall fails, so that the resource is deleted
+	// even though some of the components may not be uninstalled.  Otherwise the finalizer is kept and the
+	// uninstall is retried until it succeeds.  Default is false.
+	// +optional
+	RemoveFinalizerOnFailure bool `json:"removeFinalizerOnFailure,omitempty"`
 }
 
 // RoleBindingSubject specifes the kind and name of a subject to bind to
@@ -137,6 +193,57 @@ type VerrazzanoStatus struct {
 	Conditions []Condition `json:"conditions,omitempty"`
 	// State of the Verrazzano custom resource
 	State StateType `json:"state,omitempty"`
+	// States of the individual installed components, keyed by component name
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+	// The progress of the install that is reported by the install job
+	// +optional
+	InstallProgress *InstallProgress `json:"installProgress,omitempty"`
+}
+
+// InstallProgress describes the progress of the install that is reported by the install job
+type InstallProgress struct {
+	// The summary of the install progress, for example "installing keycloak (13/13)"
+	// +optional
+	Message string `json:"message,omitempty"`
+	// The install step that is running
+	// +optional
+	Step string `json:"step,omitempty"`
+	// The component that is being installed
+	// +optional
+	Component string `json:"component,omitempty"`
+	// The position of the component in the install order, starting at 1
+	// +optional
+	Index int `json:"index,omitempty"`
+	// The number of components that are installed
+	// +optional
+	Total int `json:"total,omitempty"`
+	// The percentage of the components that have been installed
+	// +optional
+	Percentage int `json:"percentage,omitempty"`
+	// The error of the install step that failed most recently
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// Last time the install progress was updated
+	// +optional
+	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
+}
+
+// ComponentStatus describes the current state of an individual Verrazzano component
+type ComponentStatus struct {
+	// State of the component
+	State ComponentStateType `json:"state,omitempty"`
+	// The version of the helm chart that is deployed for the component
+	// +optional
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// The revision of the helm release that is deployed for the component
+	// +optional
+	HelmRevision int `json:"helmRevision,omitempty"`
+	// The error from the last failed install or upgrade of the component
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// Last time the component transitioned from one state to another.
+	// +optional
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
 }
 
 // ConditionType identifies the condition of the install/uninstall/upgrade which can be checked with kubectl wait
@@ -169,6 +276,15 @@ const (
 
 	// UpgradeComplete means the upgrade has completed successfully
 	UpgradeComplete ConditionType = "UpgradeComplete"
+
+	// UpgradeRolledBack means the release of a component was rolled back after the upgrade failed
+	UpgradeRolledBack ConditionType = "UpgradeRolledBack"
+
+	// PreflightPassed means that the checks run before an upgrade have passed
+	PreflightPassed ConditionType = "PreflightPassed"
+
+	// PreflightFailed means that at least one of the checks run before an upgrade has failed
+	PreflightFailed ConditionType = "PreflightFailed"
 )
 
 // Condition describes current state of an install.
@@ -205,6 +321,32 @@ const (
 	Failed StateType = "Failed"
 )
 
+// ComponentStateType identifies the state of an individual Verrazzano component
+type ComponentStateType string
+
+const (
+	// CompStateNotInstalled is the state when a component is not installed
+	CompStateNotInstalled ComponentStateType = "NotInstalled"
+
+	// CompStateInstalling is the state when an install of the component is in progress
+	CompStateInstalling ComponentStateType = "Installing"
+
+	// CompStateReady is the state when a component is installed and ready
+	CompStateReady ComponentStateType = "Ready"
+
+	// CompStateFailed is the state when an install, upgrade or uninstall of the component has failed
+	CompStateFailed ComponentStateType = "Failed"
+
+	// CompStateUpgrading is the state when an upgrade of the component is in progress
+	CompStateUpgrading ComponentStateType = "Upgrading"
+
+	// CompStateUninstalling is the state when an uninstall of the component is in progress
+	CompStateUninstalling ComponentStateType = "Uninstalling"
+
+	// CompStateDisabled is the state when a component is disabled in the spec and is not installed
+	CompStateDisabled ComponentStateType = "Disabled"
+)
+
 // ComponentSpec contains a set of components used by Verrazzano
 type ComponentSpec struct {
 	// CertManager contains the CertManager component configuration
@@ -222,6 +364,48 @@ type ComponentSpec struct {
 	// Keycloak contains the Keycloak component configuration
 	// +optional
 	Keycloak KeycloakComponent `json:"keycloak,omitempty"`
+	// Grafana contains the Grafana component configuration
+	// +optional
+	Grafana GrafanaComponent `json:"grafana,omitempty"`
+	// Prometheus contains the Prometheus component configuration
+	// +optional
+	Prometheus PrometheusComponent `json:"prometheus,omitempty"`
+	// Rancher contains the Rancher component configuration
+	// +optional
+	Rancher RancherComponent `json:"rancher,omitempty"`
+	// Verrazzano contains the configuration of the Verrazzano helm chart
+	// +optional
+	Verrazzano VerrazzanoComponent `json:"verrazzano,omitempty"`
+	// Console contains the Verrazzano console configuration
+	// +optional
+	Console ConsoleComponent `json:"console,omitempty"`
+	// CoherenceOperator contains the Coherence operator component configuration
+	// +optional
+	CoherenceOperator CoherenceOperatorComponent `json:"coherenceOperator,omitempty"`
+	// WebLogicOperator contains the WebLogic operator component configuration
+	// +optional
+	WebLogicOperator WebLogicOperatorComponent `json:"weblogicOperator,omitempty"`
+	// OAM contains the OAM Kubernetes runtime component configuration
+	// +optional
+	OAM OAMComponent `json:"oam,omitempty"`
+	// ApplicationOperator contains the Verrazzano application operator component configuration
+	// +optional
+	ApplicationOperator ApplicationOperatorComponent `json:"applicationOperator,omitempty"`
+}
+
+// Overrides identifies a source of helm values that override the values of a component chart.  Exactly one
+// of Values, ConfigMapRef or SecretRef must be specified.  The ConfigMap and the Secret must be in the
+// namespace of the Verrazzano resource.
+type Overrides struct {
+	// Values is a YAML document of helm values
+	// +optional
+	Values string `json:"values,omitempty"`
+	// ConfigMapRef selects a key of a ConfigMap that contains a YAML document of helm values
+	// +optional
+	ConfigMapRef *corev1.ConfigMapKeySelector `json:"configMapRef,omitempty"`
+	// SecretRef selects a key of a Secret that contains a YAML document of helm values
+	// +optional
+	SecretRef *corev1.SecretKeySelector `json:"secretRef,omitempty"`
 }
 
 // CertManagerComponent specifies the core CertManagerComponent config.
@@ -229,6 +413,9 @@ type CertManagerComponent struct {
 	// Certificate used for an install
 	// +optional
 	Certificate Certificate `json:"certificate,omitempty"`
+	// Overrides are merged in order on top of the helm values of the component
+	// +optional
+	Overrides []Overrides `json:"overrides,omitempty"`
 }
 
 // DNSComponent specifies the DNS configuration
@@ -242,6 +429,9 @@ type DNSComponent struct {
 	// DNS type of external. For example, OLCNE uses this type.
 	// +optional
 	External External `json:"external,omitempty"`
+	// Overrides are merged in order on top of the helm values of the component
+	// +optional
+	Overrides []Overrides `json:"overrides,omitempty"`
 }
 
 // IngressNginxComponent specifies the ingress-nginx configuration
@@ -255,6 +445,9 @@ type IngressNginxComponent struct {
 	// Ports to be used for NGINX
 	// +optional
 	Ports []corev1.ServicePort `json:"ports,omitempty"`
+	// Overrides are merged in order on top of the helm values of the component
+	// +optional
+	Overrides []Overrides `json:"overrides,omitempty"`
 }
 
 // IstioComponent specifies the Istio configuration
@@ -262,16 +455,45 @@ type IstioComponent struct {
 	// Arguments for installing Istio
 	// +optional
 	IstioInstallArgs []InstallArgs `json:"istioInstallArgs,omitempty"`
+	// EgressGateway contains the configuration of the istio egress gateway
+	// +optional
+	EgressGateway IstioEgressGatewayComponent `json:"egressGateway,omitempty"`
+	// CoreDNS contains the configuration of istiocoredns
+	// +optional
+	CoreDNS IstioCoreDNSComponent `json:"coreDNS,omitempty"`
+	// Overrides are merged in order on top of the helm values of the component
+	// +optional
+	Overrides []Overrides `json:"overrides,omitempty"`
+}
+
+// IstioEgressGatewayComponent specifies the istio egress gateway configuration
+type IstioEgressGatewayComponent struct {
+	// Enabled specifies whether the component is installed.  Default is true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// IstioCoreDNSComponent specifies the istiocoredns configuration
+type IstioCoreDNSComponent struct {
+	// Enabled specifies whether the component is installed.  Default is true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
 }
 
 // KeycloakComponent specifies the Keycloak configuration
 type KeycloakComponent struct {
+	// Enabled specifies whether the component is installed.  Default is true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
 	// Arguments for installing Keycloak
 	// +optional
 	KeycloakInstallArgs []InstallArgs `json:"keycloakInstallArgs,omitempty"`
 	// MySQL contains the MySQL component configuration needed for Keycloak
 	// +optional
 	MySQL MySQLComponent `json:"mysql,omitempty"`
+	// Overrides are merged in order on top of the helm values of the component
+	// +optional
+	Overrides []Overrides `json:"overrides,omitempty"`
 }
 
 // MySQLComponent specifies the MySQL configuration
@@ -284,6 +506,82 @@ type MySQLComponent struct {
 	// is used, it must reference a VolumeClaimSpecTemplate in the VolumeClaimSpecTemplates section.
 	// +optional
 	VolumeSource *corev1.VolumeSource `json:"volumeSource,omitempty"`
+	// Overrides are merged in order on top of the helm values of the component
+	// +optional
+	Overrides []Overrides `json:"overrides,omitempty"`
+}
+
+// GrafanaComponent specifies the Grafana configuration
+type GrafanaComponent struct {
+	// Enabled specifies whether the component is installed.  Default is true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Overrides are merged in order on top of the helm values of the component
+	// +optional
+	Overrides []Overrides `json:"overrides,omitempty"`
+}
+
+// PrometheusComponent specifies the Prometheus configuration
+type PrometheusComponent struct {
+	// Enabled specifies whether the component is installed.  Default is true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Overrides are merged in order on top of the helm values of the component
+	// +optional
+	Overrides []Overrides `json:"overrides,omitempty"`
+}
+
+// RancherComponent specifies the Rancher configuration
+type RancherComponent struct {
+	// Enabled specifies whether the component is installed.  Default is true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Overrides are merged in order on top of the helm values of the component
+	// +optional
+	Overrides []Overrides `json:"overrides,omitempty"`
+}
+
+// VerrazzanoComponent specifies the Verrazzano helm chart configuration
+type VerrazzanoComponent struct {
+	// Overrides are merged in order on top of the helm values of the component
+	// +optional
+	Overrides []Overrides `json:"overrides,omitempty"`
+}
+
+// ConsoleComponent specifies the Verrazzano console configuration.  The console is installed by the
+// Verrazzano helm chart and needs Keycloak.
+type ConsoleComponent struct {
+	// Enabled specifies whether the component is installed.  Default is true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// CoherenceOperatorComponent specifies the Coherence operator configuration
+type CoherenceOperatorComponent struct {
+	// Overrides are merged in order on top of the helm values of the component
+	// +optional
+	Overrides []Overrides `json:"overrides,omitempty"`
+}
+
+// WebLogicOperatorComponent specifies the WebLogic operator configuration
+type WebLogicOperatorComponent struct {
+	// Overrides are merged in order on top of the helm values of the component
+	// +optional
+	Overrides []Overrides `json:"overrides,omitempty"`
+}
+
+// OAMComponent specifies the OAM Kubernetes runtime configuration
+type OAMComponent struct {
+	// Overrides are merged in order on top of the helm values of the component
+	// +optional
+	Overrides []Overrides `json:"overrides,omitempty"`
+}
+
+// ApplicationOperatorComponent specifies the Verrazzano application operator configuration
+type ApplicationOperatorComponent struct {
+	// Overrides are merged in order on top of the helm values of the component
+	// +optional
+	Overrides []Overrides `json:"overrides,omitempty"`
 }
 
 // InstallArgs identifies a name/value or name/value list needed for install.
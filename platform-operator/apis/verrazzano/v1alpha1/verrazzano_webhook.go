@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -48,6 +49,15 @@ func (v *Verrazzano) ValidateCreate() error {
 		return err
 	}
 
+	if err := ValidateOverrides(&v.Spec); err != nil {
+		return err
+	}
+
+	// The profile must exist, and the components that the profile disables are validated with the spec
+	if err := validateEffectiveSpec(client, &v.Spec); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -75,15 +85,54 @@ func (v *Verrazzano) ValidateUpdate(old runtime.Object) error {
 		return fmt.Errorf("Profile change is not allowed oldResource %s to %s", oldResource.Spec.Profile, v.Spec.Profile)
 	}
 
-	// Check to see if the update is an upgrade request, and if it is valid and allowable
-	err := ValidateUpgradeRequest(&oldResource.Spec, &v.Spec)
+	// Check to see if the update is an upgrade request, and if it is valid and allowable.  A Verrazzano that
+	// was installed without a version in the spec is at the version of its status.
+	currentSpec := oldResource.Spec
+	if len(currentSpec.Version) == 0 && len(v.Spec.Version) > 0 {
+		currentSpec.Version = oldResource.Status.Version
+	}
+	err := ValidateUpgradeRequest(&currentSpec, &v.Spec)
 	if err != nil {
 		log.Errorf("Invalid upgrade request: %s", err.Error())
 		return err
 	}
+
+	// Check that the configuration changes can be applied to the installed components
+	if err := ValidateConfigUpdate(&oldResource.Spec, &v.Spec); err != nil {
+		log.Errorf("Invalid configuration update: %s", err.Error())
+		return err
+	}
+
+	if err := ValidateOverrides(&v.Spec); err != nil {
+		log.Errorf("Invalid overrides: %s", err.Error())
+		return err
+	}
+
+	// Custom profiles are defined by ConfigMaps, the client is only needed to read them
+	var client client.Client
+	if v.Spec.Profile != "" && v.Spec.Profile != Dev && v.Spec.Profile != Prod {
+		client, err = getControllerRuntimeClient()
+		if err != nil {
+			return err
+		}
+	}
+	if err := validateEffectiveSpec(client, &v.Spec); err != nil {
+		log.Errorf("Invalid enabled components: %s", err.Error())
+		return err
+	}
 	return nil
 }
 
+// validateEffectiveSpec validates the enabled components of the effective spec, which has the settings of the
+// profile layered under the spec
+func validateEffectiveSpec(c client.Client, spec *VerrazzanoSpec) error {
+	effectiveSpec, err := ValidateProfile(c, spec)
+	if err != nil {
+		return err
+	}
+	return ValidateEnabledComponents(effectiveSpec)
+}
+
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
 func (v *Verrazzano) ValidateDelete() error {
 
@@ -106,5 +155,6 @@ func getClient() (client.Client, error) {
 func newScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	AddToScheme(scheme)
+	corev1.AddToScheme(scheme)
 	return scheme
 }
@@ -148,6 +148,10 @@ func TestUpdateCallbackSuccessWithNewVersion(t *testing.T) {
 // WHEN valid versions exist in both specs, and the new version > old version
 // THEN no error is returned
 func TestUpdateCallbackSuccessWithOldAndNewVersion(t *testing.T) {
+	defer config.Set(config.Get())
+	helmConfig := config.Get()
+	helmConfig.HelmConfigDir = "../../../helm_config"
+	config.Set(helmConfig)
 	chartYaml := webhookTestValidChartYAML
 	readFileFunction = func(string) ([]byte, error) {
 		return []byte(chartYaml), nil
@@ -197,6 +201,69 @@ func TestUpdateCallbackFailsWithOldGreaterThanNewVersion(t *testing.T) {
 	assert.Error(t, newSpec.ValidateUpdate(oldSpec))
 }
 
+// TestUpdateCallbackFailsWithStatusVersionGreaterThanNewVersion Tests the update callback of a Verrazzano that
+// was installed without a spec version
+// GIVEN a ValidateUpdate() request
+// WHEN the old spec has no version, and the version in the old status is greater than the new version
+// THEN an error is returned
+func TestUpdateCallbackFailsWithStatusVersionGreaterThanNewVersion(t *testing.T) {
+	chartYaml := webhookTestValidChartYAML
+	readFileFunction = func(string) ([]byte, error) {
+		return []byte(chartYaml), nil
+	}
+	defer func() {
+		readFileFunction = ioutil.ReadFile
+	}()
+	oldSpec := &Verrazzano{
+		Spec: VerrazzanoSpec{
+			Profile: "dev",
+		},
+		Status: VerrazzanoStatus{
+			Version: "v0.8.0",
+		},
+	}
+	newSpec := &Verrazzano{
+		Spec: VerrazzanoSpec{
+			Version: "v0.6.0",
+			Profile: "dev",
+		},
+	}
+	assert.EqualError(t, newSpec.ValidateUpdate(oldSpec), "Requested version 0.6.0 is not newer than current version 0.8.0")
+}
+
+// TestUpdateCallbackFailsWithStatusVersionSkipped Tests the update callback of a Verrazzano that was installed
+// without a spec version
+// GIVEN a ValidateUpdate() request
+// WHEN the old spec has no version, and the upgrade from the version in the old status skips a required version
+// THEN an error is returned
+func TestUpdateCallbackFailsWithStatusVersionSkipped(t *testing.T) {
+	readFileFunction = func(string) ([]byte, error) {
+		return []byte(`version: 0.10.0`), nil
+	}
+	defer func() {
+		readFileFunction = ioutil.ReadFile
+	}()
+	defer config.Set(config.Get())
+	helmConfig := config.Get()
+	helmConfig.HelmConfigDir = "../../../helm_config"
+	config.Set(helmConfig)
+	oldSpec := &Verrazzano{
+		Spec: VerrazzanoSpec{
+			Profile: "dev",
+		},
+		Status: VerrazzanoStatus{
+			Version: "v0.7.0",
+		},
+	}
+	newSpec := &Verrazzano{
+		Spec: VerrazzanoSpec{
+			Version: "v0.10.0",
+			Profile: "dev",
+		},
+	}
+	assert.EqualError(t, newSpec.ValidateUpdate(oldSpec), "Upgrade from version 0.7.0 to version 0.10.0 is not supported, upgrade to version 0.8.0 first")
+}
+
 // TestUpdateCallbackFailsWithInvalidNewVersion Tests the create callback with invalid new version
 // GIVEN a ValidateUpdate() request
 // WHEN the new version is valid but not the same as the chart version
@@ -307,3 +374,21 @@ func runDeleteCallbackTest() error {
 	}
 	return deletedSpec.ValidateDelete()
 }
+
+// TestUpdateCallbackFailsChangeEnvironmentName Tests the update callback with a changed environment name
+// GIVEN a ValidateUpdate() request
+// WHEN the environment name is changed without changing the version
+// THEN an error is returned
+func TestUpdateCallbackFailsChangeEnvironmentName(t *testing.T) {
+	oldSpec := &Verrazzano{
+		Spec: VerrazzanoSpec{
+			EnvironmentName: "env1",
+		},
+	}
+	newSpec := &Verrazzano{
+		Spec: VerrazzanoSpec{
+			EnvironmentName: "env2",
+		},
+	}
+	assert.Error(t, newSpec.ValidateUpdate(oldSpec))
+}
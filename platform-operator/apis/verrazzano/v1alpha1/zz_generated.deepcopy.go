@@ -9,7 +9,8 @@ package v1alpha1
 
 import (
 	"k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/runtime"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -27,6 +28,28 @@ func (in *Acme) DeepCopy() *Acme {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationOperatorComponent) DeepCopyInto(out *ApplicationOperatorComponent) {
+	*out = *in
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationOperatorComponent.
+func (in *ApplicationOperatorComponent) DeepCopy() *ApplicationOperatorComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationOperatorComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CA) DeepCopyInto(out *CA) {
 	*out = *in
@@ -46,6 +69,13 @@ func (in *CA) DeepCopy() *CA {
 func (in *CertManagerComponent) DeepCopyInto(out *CertManagerComponent) {
 	*out = *in
 	out.Certificate = in.Certificate
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerComponent.
@@ -75,14 +105,45 @@ func (in *Certificate) DeepCopy() *Certificate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoherenceOperatorComponent) DeepCopyInto(out *CoherenceOperatorComponent) {
+	*out = *in
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoherenceOperatorComponent.
+func (in *CoherenceOperatorComponent) DeepCopy() *CoherenceOperatorComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(CoherenceOperatorComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 	*out = *in
-	out.CertManager = in.CertManager
-	out.DNS = in.DNS
+	in.CertManager.DeepCopyInto(&out.CertManager)
+	in.DNS.DeepCopyInto(&out.DNS)
 	in.Ingress.DeepCopyInto(&out.Ingress)
 	in.Istio.DeepCopyInto(&out.Istio)
 	in.Keycloak.DeepCopyInto(&out.Keycloak)
+	in.Grafana.DeepCopyInto(&out.Grafana)
+	in.Prometheus.DeepCopyInto(&out.Prometheus)
+	in.Rancher.DeepCopyInto(&out.Rancher)
+	in.Verrazzano.DeepCopyInto(&out.Verrazzano)
+	in.Console.DeepCopyInto(&out.Console)
+	in.CoherenceOperator.DeepCopyInto(&out.CoherenceOperator)
+	in.WebLogicOperator.DeepCopyInto(&out.WebLogicOperator)
+	in.OAM.DeepCopyInto(&out.OAM)
+	in.ApplicationOperator.DeepCopyInto(&out.ApplicationOperator)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentSpec.
@@ -95,6 +156,21 @@ func (in *ComponentSpec) DeepCopy() *ComponentSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -110,12 +186,39 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsoleComponent) DeepCopyInto(out *ConsoleComponent) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleComponent.
+func (in *ConsoleComponent) DeepCopy() *ConsoleComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsoleComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSComponent) DeepCopyInto(out *DNSComponent) {
 	*out = *in
 	out.XIPIO = in.XIPIO
 	out.OCI = in.OCI
 	out.External = in.External
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSComponent.
@@ -143,6 +246,33 @@ func (in *External) DeepCopy() *External {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaComponent) DeepCopyInto(out *GrafanaComponent) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaComponent.
+func (in *GrafanaComponent) DeepCopy() *GrafanaComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressNginxComponent) DeepCopyInto(out *IngressNginxComponent) {
 	*out = *in
@@ -160,6 +290,13 @@ func (in *IngressNginxComponent) DeepCopyInto(out *IngressNginxComponent) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressNginxComponent.
@@ -192,6 +329,91 @@ func (in *InstallArgs) DeepCopy() *InstallArgs {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallPolicy) DeepCopyInto(out *InstallPolicy) {
+	*out = *in
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallPolicy.
+func (in *InstallPolicy) DeepCopy() *InstallPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallProgress) DeepCopyInto(out *InstallProgress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallProgress.
+func (in *InstallProgress) DeepCopy() *InstallProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceInfo) DeepCopyInto(out *InstanceInfo) {
+	*out = *in
+	if in.Console != nil {
+		in, out := &in.Console, &out.Console
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeyCloakURL != nil {
+		in, out := &in.KeyCloakURL, &out.KeyCloakURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.RancherURL != nil {
+		in, out := &in.RancherURL, &out.RancherURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.ElasticURL != nil {
+		in, out := &in.ElasticURL, &out.ElasticURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.KibanaURL != nil {
+		in, out := &in.KibanaURL, &out.KibanaURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.GrafanaURL != nil {
+		in, out := &in.GrafanaURL, &out.GrafanaURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.PrometheusURL != nil {
+		in, out := &in.PrometheusURL, &out.PrometheusURL
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceInfo.
+func (in *InstanceInfo) DeepCopy() *InstanceInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IstioComponent) DeepCopyInto(out *IstioComponent) {
 	*out = *in
@@ -202,6 +424,15 @@ func (in *IstioComponent) DeepCopyInto(out *IstioComponent) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.EgressGateway.DeepCopyInto(&out.EgressGateway)
+	in.CoreDNS.DeepCopyInto(&out.CoreDNS)
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioComponent.
@@ -214,9 +445,54 @@ func (in *IstioComponent) DeepCopy() *IstioComponent {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IstioCoreDNSComponent) DeepCopyInto(out *IstioCoreDNSComponent) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioCoreDNSComponent.
+func (in *IstioCoreDNSComponent) DeepCopy() *IstioCoreDNSComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(IstioCoreDNSComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IstioEgressGatewayComponent) DeepCopyInto(out *IstioEgressGatewayComponent) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioEgressGatewayComponent.
+func (in *IstioEgressGatewayComponent) DeepCopy() *IstioEgressGatewayComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(IstioEgressGatewayComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeycloakComponent) DeepCopyInto(out *KeycloakComponent) {
 	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
 	if in.KeycloakInstallArgs != nil {
 		in, out := &in.KeycloakInstallArgs, &out.KeycloakInstallArgs
 		*out = make([]InstallArgs, len(*in))
@@ -225,6 +501,13 @@ func (in *KeycloakComponent) DeepCopyInto(out *KeycloakComponent) {
 		}
 	}
 	in.MySQL.DeepCopyInto(&out.MySQL)
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakComponent.
@@ -252,6 +535,13 @@ func (in *MySQLComponent) DeepCopyInto(out *MySQLComponent) {
 		*out = new(v1.VolumeSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MySQLComponent.
@@ -264,6 +554,28 @@ func (in *MySQLComponent) DeepCopy() *MySQLComponent {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAMComponent) DeepCopyInto(out *OAMComponent) {
+	*out = *in
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAMComponent.
+func (in *OAMComponent) DeepCopy() *OAMComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(OAMComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OCI) DeepCopyInto(out *OCI) {
 	*out = *in
@@ -279,6 +591,101 @@ func (in *OCI) DeepCopy() *OCI {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Overrides) DeepCopyInto(out *Overrides) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Overrides.
+func (in *Overrides) DeepCopy() *Overrides {
+	if in == nil {
+		return nil
+	}
+	out := new(Overrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileDefinition) DeepCopyInto(out *ProfileDefinition) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileDefinition.
+func (in *ProfileDefinition) DeepCopy() *ProfileDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusComponent) DeepCopyInto(out *PrometheusComponent) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusComponent.
+func (in *PrometheusComponent) DeepCopy() *PrometheusComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RancherComponent) DeepCopyInto(out *RancherComponent) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RancherComponent.
+func (in *RancherComponent) DeepCopy() *RancherComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(RancherComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoleBindingSubject) DeepCopyInto(out *RoleBindingSubject) {
 	*out = *in
@@ -311,6 +718,36 @@ func (in *SecuritySpec) DeepCopy() *SecuritySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UninstallPolicy) DeepCopyInto(out *UninstallPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UninstallPolicy.
+func (in *UninstallPolicy) DeepCopy() *UninstallPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UninstallPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePolicy) DeepCopyInto(out *UpgradePolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePolicy.
+func (in *UpgradePolicy) DeepCopy() *UpgradePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Verrazzano) DeepCopyInto(out *Verrazzano) {
 	*out = *in
@@ -338,6 +775,28 @@ func (in *Verrazzano) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerrazzanoComponent) DeepCopyInto(out *VerrazzanoComponent) {
+	*out = *in
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoComponent.
+func (in *VerrazzanoComponent) DeepCopy() *VerrazzanoComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(VerrazzanoComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VerrazzanoList) DeepCopyInto(out *VerrazzanoList) {
 	*out = *in
@@ -387,6 +846,9 @@ func (in *VerrazzanoSpec) DeepCopyInto(out *VerrazzanoSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.InstallPolicy.DeepCopyInto(&out.InstallPolicy)
+	out.UpgradePolicy = in.UpgradePolicy
+	out.UninstallPolicy = in.UninstallPolicy
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoSpec.
@@ -402,11 +864,28 @@ func (in *VerrazzanoSpec) DeepCopy() *VerrazzanoSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VerrazzanoStatus) DeepCopyInto(out *VerrazzanoStatus) {
 	*out = *in
+	if in.VerrazzanoInstance != nil {
+		in, out := &in.VerrazzanoInstance, &out.VerrazzanoInstance
+		*out = new(InstanceInfo)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]Condition, len(*in))
 		copy(*out, *in)
 	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make(map[string]ComponentStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.InstallProgress != nil {
+		in, out := &in.InstallProgress, &out.InstallProgress
+		*out = new(InstallProgress)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerrazzanoStatus.
@@ -436,6 +915,28 @@ func (in *VolumeClaimSpecTemplate) DeepCopy() *VolumeClaimSpecTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebLogicOperatorComponent) DeepCopyInto(out *WebLogicOperatorComponent) {
+	*out = *in
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebLogicOperatorComponent.
+func (in *WebLogicOperatorComponent) DeepCopy() *WebLogicOperatorComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(WebLogicOperatorComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *XIPIO) DeepCopyInto(out *XIPIO) {
 	*out = *in
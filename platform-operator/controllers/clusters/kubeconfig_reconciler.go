@@ -139,10 +139,13 @@ func (r *VerrazzanoManagedClusterReconciler) reconcileKubeConfig(vmc *clusterapi
 	if err != nil {
 		return err
 	}
-	_, err = r.createOrUpdateSecret(vmc, string(kcBytes), secretName, managedNamespace)
+	result, err := r.createOrUpdateSecret(vmc, string(kcBytes), secretName, managedNamespace)
 	if err != nil {
 		return err
 	}
+	if result != controllerutil.OperationResultNone {
+		r.Recorder.Eventf(vmc, corev1.EventTypeNormal, kubeconfigGeneratedReason, "Generated the kubeconfig secret %s for the managed cluster", secretName)
+	}
 
 	// Save the KubeconfigSecret in the VMC
 	vmc.Spec.KubeconfigSecret = secretName
@@ -0,0 +1,207 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	clusterapi "github.com/verrazzano/verrazzano/platform-operator/apis/clusters/v1alpha1"
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+)
+
+// The registration secret that is applied to the managed cluster, and read by the multi-cluster agent
+const (
+	registrationSecretName      = "verrazzano-cluster"
+	registrationSecretNamespace = "verrazzano-system"
+	clusterNameData             = "managed-cluster-name"
+	adminKubeconfigData         = "admin-kubeconfig"
+	elasticsearchHostData       = "elasticsearch-host"
+	elasticsearchPortData       = "elasticsearch-port"
+	elasticsearchSecretName     = "verrazzano-cluster-elasticsearch"
+)
+
+// The secret of the admin cluster that contains the Elasticsearch credentials
+const (
+	vmiSecretName      = "verrazzano"
+	vmiSecretNamespace = "verrazzano-system"
+)
+
+// The key of the manifest in the manifest secret
+const manifestData = "yaml"
+
+// Create the YAML manifest that registers the managed cluster with the admin cluster, and save it in a secret.  The
+// manifest is applied to the managed cluster with kubectl apply.  The manifest is regenerated on every reconcile,
+// so that it is rotated when the kubeconfig, the Elasticsearch endpoint or the Elasticsearch credentials change.
+// The code does the following:
+//   1. get the kubeconfig that was generated for the managed cluster
+//   2. get the Elasticsearch endpoint and credentials of the admin cluster
+//   3. build the registration secret and the Elasticsearch credentials secret for the managed cluster
+//   4. save the manifest as a secret
+//   5. update VMC with the manifest secret name
+func (r *VerrazzanoManagedClusterReconciler) reconcileManifestSecret(vmc *clusterapi.VerrazzanoManagedCluster) error {
+	secretName := generateManifestSecretName(vmc.Name)
+
+	// Get the kubeconfig that was generated for the managed cluster
+	var kcSecret corev1.Secret
+	kcNsn := types.NamespacedName{
+		Namespace: vmc.Namespace,
+		Name:      generateManagedResourceName(vmc.Name),
+	}
+	if err := r.Get(context.TODO(), kcNsn, &kcSecret); err != nil {
+		return fmt.Errorf("Failed to fetch the kubeconfig secret %s/%s, %v", kcNsn.Namespace, kcNsn.Name, err)
+	}
+
+	// Build the registration secret
+	regSecret := newManifestSecret(registrationSecretName, map[string][]byte{
+		clusterNameData:     []byte(vmc.Name),
+		adminKubeconfigData: kcSecret.Data["kubeconfig"],
+	})
+	host, port, err := r.getElasticsearchEndpoint()
+	if err != nil {
+		return err
+	}
+	if len(host) > 0 {
+		regSecret.Data[elasticsearchHostData] = []byte(host)
+		regSecret.Data[elasticsearchPortData] = []byte(port)
+	} else {
+		r.log.Info("The Elasticsearch endpoint of the admin cluster is not known, it is not included in the manifest")
+	}
+	manifest, err := toManifestYaml(regSecret)
+	if err != nil {
+		return err
+	}
+
+	// Add the Elasticsearch credentials
+	var vmiSecret corev1.Secret
+	err = r.Get(context.TODO(), types.NamespacedName{Namespace: vmiSecretNamespace, Name: vmiSecretName}, &vmiSecret)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("Failed to fetch the Elasticsearch secret %s/%s, %v", vmiSecretNamespace, vmiSecretName, err)
+	}
+	if err == nil {
+		esSecret, err := toManifestYaml(newManifestSecret(elasticsearchSecretName, map[string][]byte{
+			"username": vmiSecret.Data["username"],
+			"password": vmiSecret.Data["password"],
+		}))
+		if err != nil {
+			return err
+		}
+		manifest = manifest + "---\n" + esSecret
+	} else {
+		r.log.Info("The Elasticsearch credentials of the admin cluster are not known, they are not included in the manifest")
+	}
+
+	// Save the manifest in a secret
+	var secret corev1.Secret
+	secret.Namespace = vmc.Namespace
+	secret.Name = secretName
+	result, err := controllerutil.CreateOrUpdate(context.TODO(), r.Client, &secret, func() error {
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = map[string][]byte{
+			manifestData: []byte(manifest),
+		}
+		// This SetControllerReference call will trigger garbage collection i.e. the secret
+		// will automatically get deleted when the VerrazzanoManagedCluster is deleted
+		return controllerutil.SetControllerReference(vmc, &secret, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		r.Recorder.Eventf(vmc, corev1.EventTypeNormal, manifestGeneratedReason, "Generated the registration manifest secret %s for the managed cluster", secretName)
+	}
+
+	// Save the ManagedClusterManifestSecret in the VMC
+	if vmc.Spec.ManagedClusterManifestSecret != secretName {
+		vmc.Spec.ManagedClusterManifestSecret = secretName
+		return r.Update(context.TODO(), vmc)
+	}
+	return nil
+}
+
+// Get the host and port of the Elasticsearch endpoint of the admin cluster from the instance info of the
+// Verrazzano installation.  Empty strings are returned if Verrazzano is not installed yet.
+func (r *VerrazzanoManagedClusterReconciler) getElasticsearchEndpoint() (string, string, error) {
+	vzList := installv1alpha1.VerrazzanoList{}
+	if err := r.List(context.TODO(), &vzList); err != nil {
+		return "", "", fmt.Errorf("Failed to list the Verrazzano resources, %v", err)
+	}
+	for _, vz := range vzList.Items {
+		if vz.Status.VerrazzanoInstance == nil || vz.Status.VerrazzanoInstance.ElasticURL == nil {
+			continue
+		}
+		esURL, err := url.Parse(*vz.Status.VerrazzanoInstance.ElasticURL)
+		if err != nil {
+			return "", "", fmt.Errorf("Invalid Elasticsearch URL %s, %v", *vz.Status.VerrazzanoInstance.ElasticURL, err)
+		}
+		port := esURL.Port()
+		if len(port) == 0 {
+			port = "443"
+			if strings.EqualFold(esURL.Scheme, "http") {
+				port = "80"
+			}
+		}
+		return esURL.Hostname(), port, nil
+	}
+	return "", "", nil
+}
+
+// newManifestSecret creates a secret in the verrazzano-system namespace of the managed cluster
+func newManifestSecret(name string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: registrationSecretNamespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
+// toManifestYaml converts the secret to YAML, without the creation timestamp that is always serialized
+func toManifestYaml(secret *corev1.Secret) (string, error) {
+	b, err := yaml.Marshal(secret)
+	if err != nil {
+		return "", err
+	}
+	return strings.Replace(string(b), "  creationTimestamp: null\n", "", 1), nil
+}
+
+// Generate the name of the secret that contains the registration manifest of the managed cluster
+func generateManifestSecretName(clusterName string) string {
+	return fmt.Sprintf("verrazzano-cluster-%s-manifest", clusterName)
+}
+
+// manifestInputRequests maps a change to the inputs of the registration manifests of the managed clusters, the
+// Elasticsearch credentials secret and the Verrazzano resources, to requests to reconcile all the
+// VerrazzanoManagedCluster resources
+func (r *VerrazzanoManagedClusterReconciler) manifestInputRequests(o handler.MapObject) []reconcile.Request {
+	if _, ok := o.Object.(*corev1.Secret); ok && (o.Meta.GetNamespace() != vmiSecretNamespace || o.Meta.GetName() != vmiSecretName) {
+		return nil
+	}
+	vmcList := clusterapi.VerrazzanoManagedClusterList{}
+	if err := r.List(context.TODO(), &vmcList, client.InNamespace(clusterapi.MultiClusterNamespace)); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, vmc := range vmcList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: vmc.Namespace, Name: vmc.Name}})
+	}
+	return requests
+}
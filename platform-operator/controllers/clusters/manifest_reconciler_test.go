@@ -0,0 +1,117 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	clustersapi "github.com/verrazzano/verrazzano/platform-operator/apis/clusters/v1alpha1"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/yaml"
+)
+
+// TestReconcileManifestSecret tests the reconcileManifestSecret method for the following use case
+// GIVEN a VerrazzanoManagedCluster resource with a generated kubeconfig
+// WHEN the registration manifest is reconciled
+// THEN ensure that the manifest secret contains the registration secret and the Elasticsearch credentials,
+//      and that the manifest is rotated when the Elasticsearch credentials change
+func TestReconcileManifestSecret(t *testing.T) {
+	asserts := assert.New(t)
+
+	vmc := &clustersapi.VerrazzanoManagedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano-mc", Name: "managed1"}}
+	esURL := "https://elasticsearch.vmi.system.example.com"
+	vz := &vzapi.Verrazzano{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "verrazzano"},
+		Status:     vzapi.VerrazzanoStatus{VerrazzanoInstance: &vzapi.InstanceInfo{ElasticURL: &esURL}},
+	}
+	kubeconfig := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano-mc", Name: generateManagedResourceName("managed1")},
+		Data:       map[string][]byte{"kubeconfig": []byte("admin-kubeconfig-yaml")},
+	}
+	vmiSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: vmiSecretNamespace, Name: vmiSecretName},
+		Data:       map[string][]byte{"username": []byte("verrazzano"), "password": []byte("password1")},
+	}
+	c := fake.NewFakeClientWithScheme(newManifestScheme(), vmc, vz, kubeconfig, vmiSecret)
+	reconciler := newVMCReconciler(c)
+	reconciler.log = zap.S()
+
+	asserts.NoError(reconciler.reconcileManifestSecret(vmc))
+	asserts.Equal(generateManifestSecretName("managed1"), vmc.Spec.ManagedClusterManifestSecret)
+	secrets := getManifestSecrets(t, c)
+	if asserts.Len(secrets, 2, "The manifest should contain two secrets") {
+		asserts.Equal(registrationSecretName, secrets[0].Name)
+		asserts.Equal(registrationSecretNamespace, secrets[0].Namespace)
+		asserts.Equal("managed1", string(secrets[0].Data[clusterNameData]))
+		asserts.Equal("admin-kubeconfig-yaml", string(secrets[0].Data[adminKubeconfigData]))
+		asserts.Equal("elasticsearch.vmi.system.example.com", string(secrets[0].Data[elasticsearchHostData]))
+		asserts.Equal("443", string(secrets[0].Data[elasticsearchPortData]))
+		asserts.Equal(elasticsearchSecretName, secrets[1].Name)
+		asserts.Equal("password1", string(secrets[1].Data["password"]))
+	}
+
+	// The manifest is rotated when the credentials change
+	vmiSecret.Data["password"] = []byte("password2")
+	asserts.NoError(c.Update(context.TODO(), vmiSecret))
+	asserts.NoError(reconciler.reconcileManifestSecret(vmc))
+	secrets = getManifestSecrets(t, c)
+	asserts.Equal("password2", string(secrets[1].Data["password"]))
+}
+
+// TestManifestInputRequests tests the manifestInputRequests method
+// GIVEN a change to a secret or a Verrazzano resource
+// WHEN manifestInputRequests is called
+// THEN requests for all the VerrazzanoManagedCluster resources are returned for the Elasticsearch credentials
+//      secret and the Verrazzano resources, and no requests are returned for other secrets
+func TestManifestInputRequests(t *testing.T) {
+	asserts := assert.New(t)
+
+	c := fake.NewFakeClientWithScheme(newManifestScheme(),
+		&clustersapi.VerrazzanoManagedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano-mc", Name: "managed1"}},
+		&clustersapi.VerrazzanoManagedCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano-mc", Name: "managed2"}})
+	reconciler := newVMCReconciler(c)
+
+	vmiSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: vmiSecretNamespace, Name: vmiSecretName}}
+	requests := reconciler.manifestInputRequests(handler.MapObject{Meta: vmiSecret, Object: vmiSecret})
+	asserts.Len(requests, 2)
+	asserts.Equal(types.NamespacedName{Namespace: "verrazzano-mc", Name: "managed1"}, requests[0].NamespacedName)
+
+	vz := &vzapi.Verrazzano{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "verrazzano"}}
+	asserts.Len(reconciler.manifestInputRequests(handler.MapObject{Meta: vz, Object: vz}), 2)
+
+	other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: vmiSecretNamespace, Name: "other"}}
+	asserts.Empty(reconciler.manifestInputRequests(handler.MapObject{Meta: other, Object: other}))
+}
+
+// newManifestScheme creates a new scheme that includes the objects that are used to generate the manifest
+func newManifestScheme() *runtime.Scheme {
+	scheme := newScheme()
+	corev1.AddToScheme(scheme)
+	vzapi.AddToScheme(scheme)
+	return scheme
+}
+
+// getManifestSecrets gets the manifest of the managed1 cluster and returns the secrets in the manifest
+func getManifestSecrets(t *testing.T, c client.Client) []corev1.Secret {
+	secret := corev1.Secret{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano-mc", Name: generateManifestSecretName("managed1")}, &secret))
+	var secrets []corev1.Secret
+	for _, doc := range strings.Split(string(secret.Data[manifestData]), "---\n") {
+		s := corev1.Secret{}
+		assert.NoError(t, yaml.Unmarshal([]byte(doc), &s))
+		secrets = append(secrets, s)
+	}
+	return secrets
+}
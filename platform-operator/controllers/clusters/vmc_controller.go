@@ -7,27 +7,45 @@ import (
 	"context"
 	"fmt"
 	clustersv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/clusters/v1alpha1"
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const roleForManagedClusterName = "verrazzano-managed-cluster"
 
+// The reasons of the events that are recorded for the VerrazzanoManagedCluster resource
+const (
+	serviceAccountCreatedReason = "ServiceAccountCreated"
+	serviceAccountFailedReason  = "ServiceAccountFailed"
+	roleBindingFailedReason     = "RoleBindingFailed"
+	kubeconfigGeneratedReason   = "KubeconfigGenerated"
+	kubeconfigFailedReason      = "KubeconfigFailed"
+	clusterConnectedReason      = "ClusterConnected"
+	clusterUnreachableReason    = "ClusterUnreachable"
+	manifestGeneratedReason     = "ManifestGenerated"
+	manifestFailedReason        = "ManifestFailed"
+)
+
 // VerrazzanoManagedClusterReconciler reconciles a VerrazzanoManagedCluster object.
 // The reconciler will create a ServiceAcount, ClusterRoleBinding, and a Secret which
 // contains the kubeconfig to be used by the Multi-Cluster Agent to access the admin cluster.
 type VerrazzanoManagedClusterReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
-	log    *zap.SugaredLogger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	log      *zap.SugaredLogger
 }
 
 // bindingParams used to mutate the ClusterRoleBinding
@@ -41,6 +59,8 @@ type bindingParams struct {
 
 // +kubebuilder:rbac:groups=clusters.verrazzano.io,resources=verrazzanomanagedclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=clusters.verrazzano.io,resources=verrazzanomanagedclusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
 
 // Reconcile reconciles a VerrazzanoManagedCluster object
 func (r *VerrazzanoManagedClusterReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
@@ -72,29 +92,48 @@ func (r *VerrazzanoManagedClusterReconciler) Reconcile(req ctrl.Request) (ctrl.R
 	err = r.reconcileServiceAccount(vmc)
 	if err != nil {
 		log.Infof("Failed to reconcile the ServiceAccount: %v", err)
+		r.Recorder.Eventf(vmc, corev1.EventTypeWarning, serviceAccountFailedReason, "Failed to reconcile the service account: %v", err)
 		return ctrl.Result{}, err
 	}
 
 	err = r.reconcileManagedRoleBinding(vmc)
 	if err != nil {
 		log.Infof("Failed to reconcile the ServiceAccount: %v", err)
+		r.Recorder.Eventf(vmc, corev1.EventTypeWarning, roleBindingFailedReason, "Failed to reconcile the cluster role binding: %v", err)
 		return ctrl.Result{}, err
 	}
 
 	err = r.reconcileKubeConfig(vmc)
 	if err != nil {
 		log.Infof("Failed to reconcile the kubeconfig used by managed cluster: %v", err)
+		r.Recorder.Eventf(vmc, corev1.EventTypeWarning, kubeconfigFailedReason, "Failed to generate the kubeconfig for the managed cluster: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	err = r.reconcileManifestSecret(vmc)
+	if err != nil {
+		log.Infof("Failed to reconcile the registration manifest of the managed cluster: %v", err)
+		r.Recorder.Eventf(vmc, corev1.EventTypeWarning, manifestFailedReason, "Failed to generate the registration manifest for the managed cluster: %v", err)
 		return ctrl.Result{}, err
 	}
-	return ctrl.Result{}, nil
+
+	result, err := r.reconcileStatus(ctx, vmc)
+	if err != nil {
+		log.Infof("Failed to update the status of the managed cluster: %v", err)
+		return ctrl.Result{}, err
+	}
+	return result, nil
 }
 
 func (r *VerrazzanoManagedClusterReconciler) reconcileServiceAccount(vmc *clustersv1alpha1.VerrazzanoManagedCluster) error {
 	// Create or update the service account
-	_, err := r.createOrUpdateServiceAccount(context.TODO(), vmc)
+	result, err := r.createOrUpdateServiceAccount(context.TODO(), vmc)
 	if err != nil {
 		return err
 	}
+	if result == controllerutil.OperationResultCreated {
+		r.Recorder.Eventf(vmc, corev1.EventTypeNormal, serviceAccountCreatedReason, "Created service account %s", generateManagedResourceName(vmc.Name))
+	}
 
 	// Does the VerrazzanoManagedCluster object contain the service account name?
 	saName := generateManagedResourceName(vmc.Name)
@@ -189,9 +228,15 @@ func generateManagedResourceName(clusterName string) string {
 	return fmt.Sprintf("%s-managed-cluster", clusterName)
 }
 
-// SetupWithManager creates a new controller and adds it to the manager
+// SetupWithManager creates a new controller and adds it to the manager.  The secrets that are owned by the
+// VerrazzanoManagedCluster, the Elasticsearch credentials and the Verrazzano resources are watched, so that the
+// registration manifest is regenerated when any of them change.
 func (r *VerrazzanoManagedClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	manifestInputs := &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.manifestInputRequests)}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&clustersv1alpha1.VerrazzanoManagedCluster{}).
+		Owns(&corev1.Secret{}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, manifestInputs).
+		Watches(&source.Kind{Type: &installv1alpha1.Verrazzano{}}, manifestInputs).
 		Complete(r)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	clustersapi "github.com/verrazzano/verrazzano/platform-operator/apis/clusters/v1alpha1"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/mocks"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -20,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -138,9 +140,75 @@ func TestCreateVMC(t *testing.T) {
 			return nil
 		})
 
+	// Expect a call to get the kubeconfig secret, return one with the kubeconfig set
+	mock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: namespace, Name: generateManagedResourceName(name)}, gomock.Not(gomock.Nil())).
+		DoAndReturn(func(ctx context.Context, name types.NamespacedName, secret *corev1.Secret) error {
+			secret.Data = map[string][]byte{
+				"kubeconfig": []byte("kubeconfig"),
+			}
+			return nil
+		})
+
+	// Expect a call to list the Verrazzano resources, return one with the Elasticsearch URL set
+	mock.EXPECT().
+		List(gomock.Any(), gomock.Not(gomock.Nil())).
+		DoAndReturn(func(ctx context.Context, vzList *vzapi.VerrazzanoList, opts ...client.ListOption) error {
+			esURL := "https://elasticsearch.vmi.system.example.com"
+			vzList.Items = []vzapi.Verrazzano{{Status: vzapi.VerrazzanoStatus{VerrazzanoInstance: &vzapi.InstanceInfo{ElasticURL: &esURL}}}}
+			return nil
+		})
+
+	// Expect a call to get the Elasticsearch credentials secret
+	mock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: vmiSecretNamespace, Name: vmiSecretName}, gomock.Not(gomock.Nil())).
+		DoAndReturn(func(ctx context.Context, name types.NamespacedName, secret *corev1.Secret) error {
+			secret.Data = map[string][]byte{
+				"username": []byte("verrazzano"),
+				"password": []byte("password"),
+			}
+			return nil
+		})
+
+	// Expect a call to get the manifest secret - return that it does not exist
+	mock.EXPECT().
+		Get(gomock.Any(), types.NamespacedName{Namespace: namespace, Name: generateManifestSecretName(name)}, gomock.Not(gomock.Nil())).
+		Return(errors.NewNotFound(schema.GroupResource{Group: namespace, Resource: "Secret"}, generateManifestSecretName(name)))
+
+	// Expect a call to create the manifest secret
+	mock.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, secret *corev1.Secret, opts ...client.CreateOption) error {
+			manifest := string(secret.Data[manifestData])
+			asserts.Contains(manifest, "name: verrazzano-cluster\n", "Manifest does not contain the registration secret")
+			asserts.Contains(manifest, "name: verrazzano-cluster-elasticsearch\n", "Manifest does not contain the Elasticsearch secret")
+			return nil
+		})
+
+	// Expect a call to update the VerrazzanoManagedCluster manifest secret name - return success
+	mock.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, vmc *clustersapi.VerrazzanoManagedCluster, opts ...client.UpdateOption) error {
+			asserts.Equal(generateManifestSecretName(name), vmc.Spec.ManagedClusterManifestSecret, "ManagedClusterManifestSecret name did not match")
+			return nil
+		})
+
+	// Expect a call to update the VerrazzanoManagedCluster status - the agent has not connected yet
+	mock.EXPECT().Status().Return(mockStatus)
+	mockStatus.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, vmc *clustersapi.VerrazzanoManagedCluster, opts ...client.UpdateOption) error {
+			asserts.Equal(clustersapi.StatePending, vmc.Status.State, "State did not match")
+			asserts.Equal(clustersapi.ConditionReady, vmc.Status.Conditions[0].Type, "Condition did not match")
+			asserts.Equal(corev1.ConditionFalse, vmc.Status.Conditions[0].Status, "Condition status did not match")
+			return nil
+		})
+
 	// Create and make the request
 	request := newRequest(namespace, name)
 	reconciler := newVMCReconciler(mock)
+	events := make(chan string, 10)
+	reconciler.Recorder = &record.FakeRecorder{Events: events}
 	result, err := reconciler.Reconcile(request)
 
 	// Validate the results
@@ -148,6 +216,9 @@ func TestCreateVMC(t *testing.T) {
 	asserts.NoError(err)
 	asserts.Equal(false, result.Requeue)
 	asserts.Equal(time.Duration(0), result.RequeueAfter)
+	asserts.Equal("Normal ServiceAccountCreated Created service account test-managed-cluster", <-events)
+	asserts.Equal("Normal KubeconfigGenerated Generated the kubeconfig secret test-managed-cluster for the managed cluster", <-events)
+	asserts.Equal("Normal ManifestGenerated Generated the registration manifest secret verrazzano-cluster-test-manifest for the managed cluster", <-events)
 }
 
 // TestDeleteVMC tests the Reconcile method for the following use case
@@ -213,8 +284,9 @@ func newRequest(namespace string, name string) ctrl.Request {
 func newVMCReconciler(c client.Client) VerrazzanoManagedClusterReconciler {
 	scheme := newScheme()
 	reconciler := VerrazzanoManagedClusterReconciler{
-		Client: c,
-		Scheme: scheme}
+		Client:   c,
+		Scheme:   scheme,
+		Recorder: &record.FakeRecorder{}}
 	return reconciler
 }
 
@@ -0,0 +1,81 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	clustersv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/clusters/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// AgentConnectTimeout is the time after which a managed cluster is unreachable if its multi-cluster agent has not
+// connected to the admin cluster.  The agent connects every minute while it syncs the multi-cluster resources.
+var AgentConnectTimeout = 5 * time.Minute
+
+// reconcileStatus updates the state and the ready condition of the managed cluster from the last time its
+// multi-cluster agent connected.  An active cluster is requeued so that it is marked unreachable when the agent
+// stops connecting.
+func (r *VerrazzanoManagedClusterReconciler) reconcileStatus(ctx context.Context, vmc *clustersv1alpha1.VerrazzanoManagedCluster) (ctrl.Result, error) {
+	previous := vmc.Status.DeepCopy()
+	result := ctrl.Result{}
+
+	connected := vmc.Status.LastAgentConnectTime
+	switch {
+	case connected == nil:
+		vmc.Status.State = clustersv1alpha1.StatePending
+		setCondition(vmc, clustersv1alpha1.ConditionReady, corev1.ConditionFalse, "The multi-cluster agent has not connected to the admin cluster")
+	case time.Since(connected.Time) > AgentConnectTimeout:
+		vmc.Status.State = clustersv1alpha1.StateUnreachable
+		setCondition(vmc, clustersv1alpha1.ConditionReady, corev1.ConditionFalse,
+			fmt.Sprintf("The multi-cluster agent has not connected to the admin cluster since %s", connected.UTC().Format(time.RFC3339)))
+	default:
+		vmc.Status.State = clustersv1alpha1.StateActive
+		setCondition(vmc, clustersv1alpha1.ConditionReady, corev1.ConditionTrue, "The multi-cluster agent is connected to the admin cluster")
+		result.RequeueAfter = AgentConnectTimeout - time.Since(connected.Time) + time.Second
+	}
+
+	if reflect.DeepEqual(previous, &vmc.Status) {
+		return result, nil
+	}
+	if previous.State != vmc.Status.State {
+		r.log.Infof("Managed cluster state changed from %q to %q", previous.State, vmc.Status.State)
+		switch vmc.Status.State {
+		case clustersv1alpha1.StateUnreachable:
+			r.Recorder.Eventf(vmc, corev1.EventTypeWarning, clusterUnreachableReason, "The managed cluster %s is unreachable", vmc.Name)
+		case clustersv1alpha1.StateActive:
+			r.Recorder.Eventf(vmc, corev1.EventTypeNormal, clusterConnectedReason, "The managed cluster %s is connected", vmc.Name)
+		}
+	}
+	if err := r.Status().Update(ctx, vmc); err != nil {
+		return ctrl.Result{}, err
+	}
+	return result, nil
+}
+
+// setCondition sets the status and message of the condition of the managed cluster.  The transition time is only
+// changed when the status of the condition changes.
+func setCondition(vmc *clustersv1alpha1.VerrazzanoManagedCluster, conditionType clustersv1alpha1.ConditionType, status corev1.ConditionStatus, message string) {
+	for i, condition := range vmc.Status.Conditions {
+		if condition.Type != conditionType {
+			continue
+		}
+		if condition.Status != status {
+			vmc.Status.Conditions[i].LastTransitionTime = time.Now().UTC().Format(time.RFC3339)
+		}
+		vmc.Status.Conditions[i].Status = status
+		vmc.Status.Conditions[i].Message = message
+		return
+	}
+	vmc.Status.Conditions = append(vmc.Status.Conditions, clustersv1alpha1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		LastTransitionTime: time.Now().UTC().Format(time.RFC3339),
+		Message:            message,
+	})
+}
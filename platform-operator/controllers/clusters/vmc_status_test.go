@@ -0,0 +1,89 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	clustersapi "github.com/verrazzano/verrazzano/platform-operator/apis/clusters/v1alpha1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestReconcileStatus tests the reconcileStatus method for the following use case
+// GIVEN a VerrazzanoManagedCluster resource whose agent connected to the admin cluster
+// WHEN the status is reconciled
+// THEN ensure that the cluster is active and requeued, and becomes unreachable when the agent connect is stale
+func TestReconcileStatus(t *testing.T) {
+	asserts := assert.New(t)
+
+	vmc := newStatusVMC()
+	vmc.Status.LastAgentConnectTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	c := fake.NewFakeClientWithScheme(newScheme(), vmc)
+	events := make(chan string, 10)
+	reconciler := newVMCReconciler(c)
+	reconciler.Recorder = &record.FakeRecorder{Events: events}
+	reconciler.log = zap.S()
+
+	result, err := reconciler.reconcileStatus(context.TODO(), vmc)
+	asserts.NoError(err)
+	asserts.True(result.RequeueAfter > 3*time.Minute && result.RequeueAfter <= AgentConnectTimeout, "Incorrect requeue %v", result.RequeueAfter)
+	vmc = getStatusVMC(t, reconciler)
+	asserts.Equal(clustersapi.StateActive, vmc.Status.State)
+	asserts.Equal(corev1.ConditionTrue, vmc.Status.Conditions[0].Status)
+	asserts.Equal("Normal ClusterConnected The managed cluster managed1 is connected", <-events)
+
+	// The agent has not connected recently
+	vmc.Status.LastAgentConnectTime = &metav1.Time{Time: time.Now().Add(-AgentConnectTimeout - time.Minute)}
+	result, err = reconciler.reconcileStatus(context.TODO(), vmc)
+	asserts.NoError(err)
+	asserts.Equal(time.Duration(0), result.RequeueAfter)
+	vmc = getStatusVMC(t, reconciler)
+	asserts.Equal(clustersapi.StateUnreachable, vmc.Status.State)
+	asserts.Equal(corev1.ConditionFalse, vmc.Status.Conditions[0].Status)
+	asserts.Contains(vmc.Status.Conditions[0].Message, "has not connected to the admin cluster since")
+	asserts.Equal("Warning ClusterUnreachable The managed cluster managed1 is unreachable", <-events)
+	asserts.Len(vmc.Status.Conditions, 1, "The ready condition should be replaced")
+}
+
+// TestSetCondition tests the setCondition function
+// GIVEN a VerrazzanoManagedCluster resource with a condition
+// WHEN the condition is set again
+// THEN the transition time is only changed when the status of the condition changes
+func TestSetCondition(t *testing.T) {
+	asserts := assert.New(t)
+
+	vmc := newStatusVMC()
+	vmc.Status.Conditions = []clustersapi.Condition{{Type: clustersapi.ConditionReady, Status: corev1.ConditionTrue, LastTransitionTime: "2021-01-01T00:00:00Z"}}
+	setCondition(vmc, clustersapi.ConditionReady, corev1.ConditionTrue, "connected")
+	asserts.Equal("2021-01-01T00:00:00Z", vmc.Status.Conditions[0].LastTransitionTime)
+	asserts.Equal("connected", vmc.Status.Conditions[0].Message)
+
+	setCondition(vmc, clustersapi.ConditionReady, corev1.ConditionFalse, "unreachable")
+	asserts.NotEqual("2021-01-01T00:00:00Z", vmc.Status.Conditions[0].LastTransitionTime)
+
+	setCondition(vmc, clustersapi.ConditionManifestPushed, corev1.ConditionTrue, "pushed")
+	asserts.Len(vmc.Status.Conditions, 2)
+}
+
+// newStatusVMC creates a VerrazzanoManagedCluster resource for the status tests
+func newStatusVMC() *clustersapi.VerrazzanoManagedCluster {
+	return &clustersapi.VerrazzanoManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano-mc", Name: "managed1"},
+	}
+}
+
+// getStatusVMC gets the VerrazzanoManagedCluster resource of the status tests
+func getStatusVMC(t *testing.T, r VerrazzanoManagedClusterReconciler) *clustersapi.VerrazzanoManagedCluster {
+	vmc := &clustersapi.VerrazzanoManagedCluster{}
+	assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano-mc", Name: "managed1"}, vmc))
+	return vmc
+}
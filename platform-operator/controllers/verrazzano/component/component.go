@@ -4,6 +4,7 @@
 package component
 
 import (
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
 	"go.uber.org/zap"
 	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -13,6 +14,40 @@ type Component interface {
 	// Name returns the name of the Verrazzano component
 	Name() string
 
-	// Upgrade will upgrade the Verrazzano component specified in the CR.Version field
-	Upgrade(log *zap.SugaredLogger, client clipkg.Client, namespace string) error
+	// GetDependencies returns the names of the components that must be installed or upgraded before this component
+	GetDependencies() []string
+
+	// PreInstall allows components to perform any processing needed before the component is installed
+	PreInstall(log *zap.SugaredLogger, client clipkg.Client, namespace string) error
+
+	// Install will install the Verrazzano component.  The overrides are YAML helm values that are merged in
+	// order on top of the values of the component.
+	Install(log *zap.SugaredLogger, client clipkg.Client, namespace string, overrides []string) error
+
+	// PostInstall allows components to perform any processing needed after the component is installed
+	PostInstall(log *zap.SugaredLogger, client clipkg.Client, namespace string) error
+
+	// IsReady returns true if the Verrazzano component is installed and ready
+	IsReady(log *zap.SugaredLogger, client clipkg.Client, namespace string) bool
+
+	// GetChartDir returns the directory of the helm chart that is used to install and upgrade the component
+	GetChartDir() string
+
+	// GetReleaseInfo returns the helm release info for the component, or nil if the component is not installed
+	GetReleaseInfo(namespace string) (*helm.ReleaseInfo, error)
+
+	// Uninstall will uninstall the Verrazzano component
+	Uninstall(log *zap.SugaredLogger, client clipkg.Client, namespace string) error
+
+	// Upgrade will upgrade the Verrazzano component specified in the CR.Version field.  The overrides are YAML
+	// helm values that are merged in order on top of the values of the component.
+	Upgrade(log *zap.SugaredLogger, client clipkg.Client, namespace string, overrides []string) error
+
+	// Reconfigure will re-apply the Verrazzano component using the existing values and the specified set arguments.
+	// The values of the previous set arguments that are no longer specified are removed.
+	Reconfigure(log *zap.SugaredLogger, client clipkg.Client, namespace string, values helm.ReconfigureValues) error
+
+	// Rollback will roll back the Verrazzano component to the specified helm revision, or to the
+	// previous revision if the revision is 0
+	Rollback(log *zap.SugaredLogger, client clipkg.Client, namespace string, revision int) error
 }
@@ -0,0 +1,95 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package component
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortByDependencies returns the components sorted so that every component comes after the components
+// it depends on.  Components keep the order they were passed in, unless a dependency requires otherwise.
+// An error is returned if a dependency is not in the list of components or if the dependencies contain a cycle.
+func SortByDependencies(comps []Component) ([]Component, error) {
+	// Build the map of component name to the index in the list
+	indexes := make(map[string]int, len(comps))
+	for i, comp := range comps {
+		if _, ok := indexes[comp.Name()]; ok {
+			return nil, fmt.Errorf("Component %s is registered more than once", comp.Name())
+		}
+		indexes[comp.Name()] = i
+	}
+
+	// Count the unresolved dependencies of each component, and track the dependents of each component
+	unresolved := make([]int, len(comps))
+	dependents := make([][]int, len(comps))
+	for i, comp := range comps {
+		for _, dep := range comp.GetDependencies() {
+			depIndex, ok := indexes[dep]
+			if !ok {
+				return nil, fmt.Errorf("Component %s depends on component %s which is not registered", comp.Name(), dep)
+			}
+			unresolved[i]++
+			dependents[depIndex] = append(dependents[depIndex], i)
+		}
+	}
+
+	// Repeatedly take the first component in the original order that has no unresolved dependencies
+	sorted := make([]Component, 0, len(comps))
+	done := make([]bool, len(comps))
+	for len(sorted) < len(comps) {
+		next := -1
+		for i := range comps {
+			if !done[i] && unresolved[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, fmt.Errorf("Component dependencies contain a cycle between components %s", strings.Join(unsortedNames(comps, done), ", "))
+		}
+		done[next] = true
+		sorted = append(sorted, comps[next])
+		for _, dependent := range dependents[next] {
+			unresolved[dependent]--
+		}
+	}
+	return sorted, nil
+}
+
+// GroupByDependencies returns the components in groups, where the components in a group only depend on components
+// in earlier groups.  The components in the same group don't depend on each other and can be processed in parallel.
+func GroupByDependencies(comps []Component) ([][]Component, error) {
+	sorted, err := SortByDependencies(comps)
+	if err != nil {
+		return nil, err
+	}
+	levels := make(map[string]int, len(sorted))
+	var groups [][]Component
+	for _, comp := range sorted {
+		level := 0
+		for _, dep := range comp.GetDependencies() {
+			if levels[dep]+1 > level {
+				level = levels[dep] + 1
+			}
+		}
+		levels[comp.Name()] = level
+		if level == len(groups) {
+			groups = append(groups, []Component{})
+		}
+		groups[level] = append(groups[level], comp)
+	}
+	return groups, nil
+}
+
+// unsortedNames returns the names of the components that have not been sorted
+func unsortedNames(comps []Component, done []bool) []string {
+	var names []string
+	for i, comp := range comps {
+		if !done[i] {
+			names = append(names, comp.Name())
+		}
+	}
+	return names
+}
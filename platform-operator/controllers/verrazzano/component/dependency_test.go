@@ -0,0 +1,99 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package component
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSortByDependencies tests sorting components by their dependencies
+// GIVEN a list of components where a component is listed before one of its dependencies
+//  WHEN I call SortByDependencies
+//  THEN the dependency is moved before the component and the other components keep their order
+func TestSortByDependencies(t *testing.T) {
+	assert := assert.New(t)
+	comps := []Component{
+		helmComponent{releaseName: "a", dependencies: []string{"c"}},
+		helmComponent{releaseName: "b"},
+		helmComponent{releaseName: "c"},
+		helmComponent{releaseName: "d", dependencies: []string{"a", "b"}},
+	}
+	sorted, err := SortByDependencies(comps)
+	assert.NoError(err, "SortByDependencies returned an error")
+	assert.Equal([]string{"b", "c", "a", "d"}, getNames(sorted), "Incorrect component order")
+}
+
+// TestSortByDependenciesCycle tests sorting components that have a dependency cycle
+// GIVEN a list of components with a dependency cycle
+//  WHEN I call SortByDependencies
+//  THEN an error naming the components in the cycle is returned
+func TestSortByDependenciesCycle(t *testing.T) {
+	assert := assert.New(t)
+	comps := []Component{
+		helmComponent{releaseName: "a"},
+		helmComponent{releaseName: "b", dependencies: []string{"c"}},
+		helmComponent{releaseName: "c", dependencies: []string{"b"}},
+	}
+	_, err := SortByDependencies(comps)
+	assert.EqualError(err, "Component dependencies contain a cycle between components b, c")
+}
+
+// TestSortByDependenciesMissing tests sorting components that depend on a component that isn't in the list
+// GIVEN a list of components with a dependency that isn't registered
+//  WHEN I call SortByDependencies
+//  THEN an error is returned
+func TestSortByDependenciesMissing(t *testing.T) {
+	assert := assert.New(t)
+	comps := []Component{
+		helmComponent{releaseName: "a", dependencies: []string{"z"}},
+	}
+	_, err := SortByDependencies(comps)
+	assert.EqualError(err, "Component a depends on component z which is not registered")
+}
+
+// TestSortByDependenciesDuplicate tests sorting components with the same name
+// GIVEN a list of components where a name is used twice
+//  WHEN I call SortByDependencies
+//  THEN an error is returned
+func TestSortByDependenciesDuplicate(t *testing.T) {
+	assert := assert.New(t)
+	comps := []Component{
+		helmComponent{releaseName: "a"},
+		helmComponent{releaseName: "a"},
+	}
+	_, err := SortByDependencies(comps)
+	assert.EqualError(err, "Component a is registered more than once")
+}
+
+// TestGroupByDependencies tests grouping components by their dependencies
+// GIVEN a list of components with dependencies
+//  WHEN I call GroupByDependencies
+//  THEN each component is in the group after the last of its dependencies
+func TestGroupByDependencies(t *testing.T) {
+	assert := assert.New(t)
+	comps := []Component{
+		helmComponent{releaseName: "a"},
+		helmComponent{releaseName: "b", dependencies: []string{"a"}},
+		helmComponent{releaseName: "c"},
+		helmComponent{releaseName: "d", dependencies: []string{"b", "c"}},
+		helmComponent{releaseName: "e", dependencies: []string{"c"}},
+	}
+	groups, err := GroupByDependencies(comps)
+	assert.NoError(err, "GroupByDependencies returned an error")
+	assert.Len(groups, 3, "Incorrect number of groups")
+	assert.Equal([]string{"a", "c"}, getNames(groups[0]), "Incorrect components in group 0")
+	assert.Equal([]string{"b", "e"}, getNames(groups[1]), "Incorrect components in group 1")
+	assert.Equal([]string{"d"}, getNames(groups[2]), "Incorrect components in group 2")
+}
+
+// getNames returns the names of the components
+func getNames(comps []Component) []string {
+	var names []string
+	for _, comp := range comps {
+		names = append(names, comp.Name())
+	}
+	return names
+}
@@ -4,8 +4,14 @@
 package component
 
 import (
+	"context"
+
 	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -27,6 +33,9 @@ type helmComponent struct {
 	// valuesFile is the helm chart values override file
 	valuesFile string
 
+	// dependencies is the list of component names that this component depends on
+	dependencies []string
+
 	// preUpgradeFunc is an optional function to run before upgrading
 	preUpgradeFunc preUpgradeFuncSig
 }
@@ -37,28 +46,103 @@ var _ Component = helmComponent{}
 // preUpgradeFuncSig is the signature for the optional preUgrade function
 type preUpgradeFuncSig func(log *zap.SugaredLogger, client clipkg.Client, releaseName string, namespace string, chartDir string) error
 
+// installFuncSig is needed for unit test override
+type installFuncSig func(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overridesYaml string, overrides ...string) (stdout []byte, stderr []byte, err error)
+
+// uninstallFuncSig is needed for unit test override
+type uninstallFuncSig func(log *zap.SugaredLogger, releaseName string, namespace string) (stdout []byte, stderr []byte, err error)
+
 // upgradeFuncSig is needed for unit test override
-type upgradeFuncSig func(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overwriteYaml string) (stdout []byte, stderr []byte, err error)
+type upgradeFuncSig func(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overwriteYaml string, overrides ...string) (stdout []byte, stderr []byte, err error)
+
+// installFunc is the default install function
+var installFunc installFuncSig = helm.Install
+
+// uninstallFunc is the default uninstall function
+var uninstallFunc uninstallFuncSig = helm.Uninstall
+
+// reconfigureFuncSig is needed for unit test override
+type reconfigureFuncSig func(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, values helm.ReconfigureValues) (stdout []byte, stderr []byte, err error)
+
+// rollbackFuncSig is needed for unit test override
+type rollbackFuncSig func(log *zap.SugaredLogger, releaseName string, namespace string, revision int) (stdout []byte, stderr []byte, err error)
 
 // upgradeFunc is the default upgrade function
 var upgradeFunc upgradeFuncSig = helm.Upgrade
 
+// reconfigureFunc is the default reconfigure function
+var reconfigureFunc reconfigureFuncSig = helm.Reconfigure
+
+// rollbackFunc is the default rollback function
+var rollbackFunc rollbackFuncSig = helm.Rollback
+
 // Name returns the component name
 func (h helmComponent) Name() string {
 	return h.releaseName
 }
 
+// GetDependencies returns the names of the components that this component depends on
+func (h helmComponent) GetDependencies() []string {
+	return h.dependencies
+}
+
+// PreInstall creates the chart namespace if it doesn't exist
+func (h helmComponent) PreInstall(log *zap.SugaredLogger, client clipkg.Client, ns string) error {
+	return createNamespaceIfMissing(log, client, h.resolveNamespace(ns))
+}
+
+// Install is done by using the helm chart upgrade command with the install flag.  This command will apply the chart
+// that is included in the operator image using the values override file for the component, followed by the
+// overrides from the Verrazzano resource.
+func (h helmComponent) Install(log *zap.SugaredLogger, _ clipkg.Client, ns string, overrides []string) error {
+	_, _, err := installFunc(log, h.releaseName, h.resolveNamespace(ns), h.chartDir, h.valuesFile, overrides...)
+	return err
+}
+
+// PostInstall is a no-op for helm components
+func (h helmComponent) PostInstall(_ *zap.SugaredLogger, _ clipkg.Client, _ string) error {
+	return nil
+}
+
+// IsReady returns true if the helm release for the component has been deployed, and the workloads of the release
+// are available
+func (h helmComponent) IsReady(log *zap.SugaredLogger, client clipkg.Client, ns string) bool {
+	return isReleaseReady(log, client, h.releaseName, h.resolveNamespace(ns))
+}
+
+// GetChartDir returns the helm chart directory of the component
+func (h helmComponent) GetChartDir() string {
+	return h.chartDir
+}
+
+// GetReleaseInfo returns the helm release info for the component
+func (h helmComponent) GetReleaseInfo(ns string) (*helm.ReleaseInfo, error) {
+	return helm.GetReleaseInfo(h.releaseName, h.resolveNamespace(ns))
+}
+
+// Uninstall is done by using the helm uninstall command.  Components that are not installed are skipped.
+func (h helmComponent) Uninstall(log *zap.SugaredLogger, _ clipkg.Client, ns string) error {
+	namespace := h.resolveNamespace(ns)
+	found, err := helm.IsReleaseInstalled(h.releaseName, namespace)
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Infof("Skipping uninstall of component %s since it is not installed", h.releaseName)
+		return nil
+	}
+	_, _, err = uninstallFunc(log, h.releaseName, namespace)
+	return err
+}
+
 // UpgradePrehooksEnabled is needed so that higher level units tests can disable as needed
 var UpgradePrehooksEnabled = true
 
 // Upgrade is done by using the helm chart upgrade command.   This command will apply the latest chart
 // that is included in the operator image, while retaining any helm value overrides that were applied during
-// install.
-func (h helmComponent) Upgrade(log *zap.SugaredLogger, client clipkg.Client, ns string) error {
-	namespace := ns
-	if h.ignoreNamespaceOverride {
-		namespace = h.chartNamespace
-	}
+// install.  The values override file and the overrides from the Verrazzano resource are applied on top of them.
+func (h helmComponent) Upgrade(log *zap.SugaredLogger, client clipkg.Client, ns string, overrides []string) error {
+	namespace := h.resolveNamespace(ns)
 	// Check if the component is installed before trying to upgrade
 	found, err := helm.IsReleaseInstalled(h.releaseName, namespace)
 	if err != nil {
@@ -68,6 +152,9 @@ func (h helmComponent) Upgrade(log *zap.SugaredLogger, client clipkg.Client, ns
 		log.Infof("Skipping upgrade of component %s since it is not installed", h.releaseName)
 		return nil
 	}
+	if isChartVersionDeployed(log, h.releaseName, namespace, h.chartDir) {
+		return nil
+	}
 
 	// Do the preUpgrade if the function is defined
 	if h.preUpgradeFunc != nil && UpgradePrehooksEnabled {
@@ -78,10 +165,107 @@ func (h helmComponent) Upgrade(log *zap.SugaredLogger, client clipkg.Client, ns
 	}
 
 	// Do the upgrade
-	_, _, err = upgradeFunc(log, h.releaseName, namespace, h.chartDir, h.valuesFile)
+	_, _, err = upgradeFunc(log, h.releaseName, namespace, h.chartDir, h.valuesFile, overrides...)
 	return err
 }
 
+// Reconfigure is done by using the helm chart upgrade command with the values override file of the component,
+// the existing values and the set arguments.  Components that are not installed are skipped.
+func (h helmComponent) Reconfigure(log *zap.SugaredLogger, _ clipkg.Client, ns string, values helm.ReconfigureValues) error {
+	namespace := h.resolveNamespace(ns)
+	found, err := helm.IsReleaseInstalled(h.releaseName, namespace)
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Infof("Skipping reconfigure of component %s since it is not installed", h.releaseName)
+		return nil
+	}
+	values.ValuesFile = h.valuesFile
+	_, _, err = reconfigureFunc(log, h.releaseName, namespace, h.chartDir, values)
+	return err
+}
+
+// Rollback is done by using the helm rollback command.  Components that are not installed are skipped.
+func (h helmComponent) Rollback(log *zap.SugaredLogger, _ clipkg.Client, ns string, revision int) error {
+	namespace := h.resolveNamespace(ns)
+	found, err := helm.IsReleaseInstalled(h.releaseName, namespace)
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Infof("Skipping rollback of component %s since it is not installed", h.releaseName)
+		return nil
+	}
+	_, _, err = rollbackFunc(log, h.releaseName, namespace, revision)
+	return err
+}
+
+// resolveNamespace returns the chart namespace if the namespace override is ignored, otherwise
+// the namespace that was passed in
+func (h helmComponent) resolveNamespace(ns string) string {
+	if h.ignoreNamespaceOverride {
+		return h.chartNamespace
+	}
+	return ns
+}
+
+// isChartVersionDeployed returns true if the release is deployed with the same chart version as the chart
+// in the chart directory, meaning that the release doesn't need to be upgraded.  Errors getting the
+// chart or release versions are logged and false is returned, so that the release is upgraded.
+func isChartVersionDeployed(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string) bool {
+	chartInfo, err := helm.GetChartInfo(chartDir)
+	if err != nil {
+		log.Errorf("Error reading the chart info for component %s: %v", releaseName, err)
+		return false
+	}
+	releaseInfo, err := helm.GetReleaseInfo(releaseName, namespace)
+	if err != nil {
+		log.Errorf("Error getting the release info for component %s: %v", releaseName, err)
+		return false
+	}
+	if releaseInfo == nil || releaseInfo.Status != helm.ReleaseStatusDeployed || releaseInfo.ChartVersion != chartInfo.Version {
+		return false
+	}
+	log.Infof("Skipping upgrade of component %s since chart version %s is already deployed", releaseName, chartInfo.Version)
+	return true
+}
+
+// createNamespaceIfMissing creates the namespace if it doesn't already exist
+func createNamespaceIfMissing(log *zap.SugaredLogger, client clipkg.Client, namespace string) error {
+	ns := corev1.Namespace{}
+	err := client.Get(context.TODO(), types.NamespacedName{Name: namespace}, &ns)
+	if err == nil {
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return err
+	}
+	log.Infof("Creating namespace %s", namespace)
+	ns = corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespace,
+		},
+	}
+	return client.Create(context.TODO(), &ns)
+}
+
+func setInstallFunc(f installFuncSig) {
+	installFunc = f
+}
+
+func setDefaultInstallFunc() {
+	installFunc = helm.Install
+}
+
+func setUninstallFunc(f uninstallFuncSig) {
+	uninstallFunc = f
+}
+
+func setDefaultUninstallFunc() {
+	uninstallFunc = helm.Uninstall
+}
+
 func setUpgradeFunc(f upgradeFuncSig) {
 	upgradeFunc = f
 }
@@ -89,3 +273,19 @@ func setUpgradeFunc(f upgradeFuncSig) {
 func setDefaultUpgradeFunc() {
 	upgradeFunc = helm.Upgrade
 }
+
+func setReconfigureFunc(f reconfigureFuncSig) {
+	reconfigureFunc = f
+}
+
+func setDefaultReconfigureFunc() {
+	reconfigureFunc = helm.Reconfigure
+}
+
+func setRollbackFunc(f rollbackFuncSig) {
+	rollbackFunc = f
+}
+
+func setDefaultRollbackFunc() {
+	rollbackFunc = helm.Rollback
+}
@@ -4,6 +4,7 @@
 package component
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os/exec"
@@ -12,7 +13,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
 	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
 	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 // helmFakeRunner is used to test helm without actually running an OS exec command
@@ -52,12 +59,250 @@ func TestUpgrade(t *testing.T) {
 	defer helm.SetDefaultRunner()
 	setUpgradeFunc(fakeUpgrade)
 	defer setDefaultUpgradeFunc()
-	err := comp.Upgrade(zap.S(), nil, "")
+	err := comp.Upgrade(zap.S(), nil, "", nil)
 	assert.NoError(err, "Upgrade returned an error")
 }
 
+// TestUpgradeChartVersionDeployed tests the component upgrade when the chart version is already deployed
+// GIVEN a component with a release that is deployed using the same chart version as the chart directory
+//  WHEN I call Upgrade
+//  THEN the upgrade is skipped
+func TestUpgradeChartVersionDeployed(t *testing.T) {
+	assert := assert.New(t)
+
+	comp := helmComponent{
+		releaseName:             "mysql",
+		chartDir:                "../../../thirdparty/charts/mysql",
+		chartNamespace:          "keycloak",
+		ignoreNamespaceOverride: true,
+	}
+	chartInfo, err := helm.GetChartInfo(comp.chartDir)
+	assert.NoError(err, "Error reading the chart info")
+
+	upgraded := false
+	setUpgradeFunc(func(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overwriteYaml string, overrides ...string) (stdout []byte, stderr []byte, err error) {
+		upgraded = true
+		return []byte("success"), []byte(""), nil
+	})
+	defer setDefaultUpgradeFunc()
+	defer helm.SetDefaultRunner()
+
+	helm.SetCmdRunner(helmStatusRunner{status: helm.ReleaseStatusDeployed, chartVersion: chartInfo.Version})
+	assert.NoError(comp.Upgrade(zap.S(), nil, "", nil), "Upgrade returned an error")
+	assert.False(upgraded, "Upgrade should be skipped when the chart version is deployed")
+
+	helm.SetCmdRunner(helmStatusRunner{status: helm.ReleaseStatusDeployed, chartVersion: "0.0.1"})
+	assert.NoError(comp.Upgrade(zap.S(), nil, "", nil), "Upgrade returned an error")
+	assert.True(upgraded, "Upgrade should be done when the chart version is different")
+}
+
+// TestReconfigure tests the component reconfigure
+// GIVEN a component
+//  WHEN I call Reconfigure
+//  THEN the reconfigure returns success and passes the correct values to the reconfigure function
+func TestReconfigure(t *testing.T) {
+	assert := assert.New(t)
+
+	comp := helmComponent{
+		releaseName:             "release1",
+		chartDir:                "chartDir",
+		chartNamespace:          "chartNS",
+		ignoreNamespaceOverride: true,
+		valuesFile:              "valuesFile",
+	}
+
+	helm.SetCmdRunner(helmFakeRunner{})
+	defer helm.SetDefaultRunner()
+	setReconfigureFunc(func(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, values helm.ReconfigureValues) (stdout []byte, stderr []byte, err error) {
+		if releaseName != "release1" || namespace != "chartNS" || chartDir != "chartDir" || values.ValuesFile != "valuesFile" ||
+			len(values.PreviousSetArgs) != 1 || len(values.SetArgs) != 1 {
+			return []byte("error"), []byte(""), errors.New("Invalid reconfigure parameters")
+		}
+		return []byte("success"), []byte(""), nil
+	})
+	defer setDefaultReconfigureFunc()
+	err := comp.Reconfigure(zap.S(), nil, "", helm.ReconfigureValues{
+		PreviousSetArgs: []helm.SetArg{{Name: "a", Value: "a"}},
+		SetArgs:         []helm.SetArg{{Name: "a", Value: "b"}},
+	})
+	assert.NoError(err, "Reconfigure returned an error")
+}
+
+// TestRollback tests the component rollback
+// GIVEN a component
+//  WHEN I call Rollback
+//  THEN the rollback returns success and passes the correct values to the rollback function
+func TestRollback(t *testing.T) {
+	assert := assert.New(t)
+
+	comp := helmComponent{
+		releaseName:             "release1",
+		chartNamespace:          "chartNS",
+		ignoreNamespaceOverride: true,
+	}
+
+	helm.SetCmdRunner(helmFakeRunner{})
+	defer helm.SetDefaultRunner()
+	setRollbackFunc(func(log *zap.SugaredLogger, releaseName string, namespace string, revision int) (stdout []byte, stderr []byte, err error) {
+		if releaseName != "release1" || namespace != "chartNS" || revision != 3 {
+			return []byte("error"), []byte(""), errors.New("Invalid rollback parameters")
+		}
+		return []byte("success"), []byte(""), nil
+	})
+	defer setDefaultRollbackFunc()
+	err := comp.Rollback(zap.S(), nil, "", 3)
+	assert.NoError(err, "Rollback returned an error")
+}
+
+// TestPreInstall tests the component pre-install
+// GIVEN a component
+//  WHEN I call PreInstall
+//  THEN the chart namespace is created
+func TestPreInstall(t *testing.T) {
+	assert := assert.New(t)
+
+	comp := helmComponent{
+		releaseName:             "release1",
+		chartDir:                "chartDir",
+		chartNamespace:          "chartNS",
+		ignoreNamespaceOverride: true,
+	}
+
+	client := fake.NewFakeClientWithScheme(k8scheme.Scheme)
+	err := comp.PreInstall(zap.S(), client, "")
+	assert.NoError(err, "PreInstall returned an error")
+
+	ns := corev1.Namespace{}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: "chartNS"}, &ns)
+	assert.NoError(err, "chart namespace was not created")
+}
+
+// TestInstall tests the component install
+// GIVEN a component
+//  WHEN I call Install
+//  THEN the install returns success and passes the correct values to the install function
+func TestInstall(t *testing.T) {
+	assert := assert.New(t)
+
+	comp := helmComponent{
+		releaseName:             "release1",
+		chartDir:                "chartDir",
+		chartNamespace:          "chartNS",
+		ignoreNamespaceOverride: true,
+		valuesFile:              "valuesFile",
+	}
+
+	setInstallFunc(fakeUpgrade)
+	defer setDefaultInstallFunc()
+	err := comp.Install(zap.S(), nil, "", nil)
+	assert.NoError(err, "Install returned an error")
+}
+
+// TestInstallOverrides tests the component install with overrides
+// GIVEN a component and overrides from the Verrazzano resource
+//  WHEN I call Install and Upgrade
+//  THEN the overrides are passed in order after the values file of the component
+func TestInstallOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	comp := helmComponent{
+		releaseName:             "release1",
+		chartDir:                "chartDir",
+		chartNamespace:          "chartNS",
+		ignoreNamespaceOverride: true,
+		valuesFile:              "valuesFile",
+	}
+	var passed []string
+	fakeFunc := func(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, valuesFile string, overrides ...string) (stdout []byte, stderr []byte, err error) {
+		passed = overrides
+		return fakeUpgrade(log, releaseName, namespace, chartDir, valuesFile)
+	}
+	setInstallFunc(fakeFunc)
+	defer setDefaultInstallFunc()
+	setUpgradeFunc(fakeFunc)
+	defer setDefaultUpgradeFunc()
+	helm.SetCmdRunner(helmFakeRunner{})
+	defer helm.SetDefaultRunner()
+
+	overrides := []string{"a: 1", "a: 2"}
+	assert.NoError(comp.Install(zap.S(), nil, "", overrides), "Install returned an error")
+	assert.Equal(overrides, passed, "Incorrect install overrides")
+	passed = nil
+	assert.NoError(comp.Upgrade(zap.S(), nil, "", overrides), "Upgrade returned an error")
+	assert.Equal(overrides, passed, "Incorrect upgrade overrides")
+}
+
+// TestUninstall tests the component uninstall
+// GIVEN a component that is installed
+//  WHEN I call Uninstall
+//  THEN the uninstall function is called with the correct values
+func TestUninstall(t *testing.T) {
+	assert := assert.New(t)
+
+	comp := helmComponent{
+		releaseName:             "release1",
+		chartNamespace:          "chartNS",
+		ignoreNamespaceOverride: true,
+	}
+
+	helm.SetCmdRunner(helmFakeRunner{})
+	defer helm.SetDefaultRunner()
+	var uninstalled string
+	setUninstallFunc(func(log *zap.SugaredLogger, releaseName string, namespace string) ([]byte, []byte, error) {
+		uninstalled = releaseName + "/" + namespace
+		return []byte("success"), []byte(""), nil
+	})
+	defer setDefaultUninstallFunc()
+	err := comp.Uninstall(zap.S(), nil, "")
+	assert.NoError(err, "Uninstall returned an error")
+	assert.Equal("release1/chartNS", uninstalled, "Uninstall called with the wrong release")
+}
+
+// TestIsReady tests the component readiness check
+// GIVEN a component
+//  WHEN I call IsReady
+//  THEN true is returned only when the helm release is deployed and the workloads of the release are available
+func TestIsReady(t *testing.T) {
+	assert := assert.New(t)
+
+	comp := helmComponent{
+		releaseName:             "release1",
+		chartNamespace:          "chartNS",
+		ignoreNamespaceOverride: true,
+	}
+	defer helm.SetDefaultRunner()
+	replicas := int32(2)
+	deployment := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "chartNS",
+			Name:        "release1-deployment",
+			Annotations: map[string]string{releaseNameAnnotation: "release1"},
+		},
+		Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{UpdatedReplicas: 2, AvailableReplicas: 1},
+	}
+	otherDeployment := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "chartNS",
+			Name:        "release2-deployment",
+			Annotations: map[string]string{releaseNameAnnotation: "release2"},
+		},
+	}
+	client := fake.NewFakeClientWithScheme(k8scheme.Scheme, &deployment, &otherDeployment)
+
+	helm.SetCmdRunner(helmStatusRunner{status: "failed"})
+	assert.False(comp.IsReady(zap.S(), client, ""), "Component should not be ready when the release is not deployed")
+
+	helm.SetCmdRunner(helmStatusRunner{status: helm.ReleaseStatusDeployed})
+	assert.False(comp.IsReady(zap.S(), client, ""), "Component should not be ready when the deployment is not available")
+
+	deployment.Status.AvailableReplicas = 2
+	assert.NoError(client.Update(context.TODO(), &deployment))
+	assert.True(comp.IsReady(zap.S(), client, ""), "Component should be ready")
+}
+
 // fakeUpgrade verifies that the correct parameter values are passed to upgrade
-func fakeUpgrade(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overwriteYaml string) (stdout []byte, stderr []byte, err error) {
+func fakeUpgrade(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overwriteYaml string, overrides ...string) (stdout []byte, stderr []byte, err error) {
 	if releaseName != "release1" {
 		return []byte("error"), []byte(""), errors.New("Invalid release name")
 	}
@@ -73,6 +318,17 @@ func fakeUpgrade(log *zap.SugaredLogger, releaseName string, namespace string, c
 	return []byte("success"), []byte(""), nil
 }
 
+// helmStatusRunner returns the helm status JSON output with the configured status
+type helmStatusRunner struct {
+	status       string
+	chartVersion string
+}
+
+// Run returns the helm status JSON output
+func (r helmStatusRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	return []byte(`{"info":{"status":"` + r.status + `"},"chart":{"metadata":{"version":"` + r.chartVersion + `"}}}`), []byte(""), nil
+}
+
 // helmFakeRunner overrides the helm run command
 func (r helmFakeRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
 	return []byte("success"), []byte(""), nil
@@ -0,0 +1,93 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package component
+
+import (
+	"context"
+
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// releaseNameAnnotation is the annotation that helm sets on the objects of a release
+const releaseNameAnnotation = "meta.helm.sh/release-name"
+
+// isReleaseReady returns true if the helm release is deployed, and the deployments, stateful sets and daemon sets
+// of the release in the release namespace are available.  This is the readiness that the install scripts wait for
+// with helm --wait and kubectl rollout status.
+func isReleaseReady(log *zap.SugaredLogger, client clipkg.Client, releaseName string, namespace string) bool {
+	deployed, err := helm.IsReleaseDeployed(releaseName, namespace)
+	if err != nil {
+		log.Errorf("Error checking the status of component %s: %v", releaseName, err)
+		return false
+	}
+	if !deployed {
+		return false
+	}
+	ready, err := areReleaseWorkloadsReady(client, releaseName, namespace)
+	if err != nil {
+		log.Errorf("Error checking the workloads of component %s: %v", releaseName, err)
+		return false
+	}
+	if !ready {
+		log.Infof("Component %s is deployed, waiting for its workloads to be available", releaseName)
+	}
+	return ready
+}
+
+// areReleaseWorkloadsReady returns true if all the deployments, stateful sets and daemon sets of the release in
+// the namespace have the desired number of updated and available replicas
+func areReleaseWorkloadsReady(client clipkg.Client, releaseName string, namespace string) (bool, error) {
+	deployments := appsv1.DeploymentList{}
+	if err := client.List(context.TODO(), &deployments, clipkg.InNamespace(namespace)); err != nil {
+		return false, err
+	}
+	for _, deployment := range deployments.Items {
+		if deployment.Annotations[releaseNameAnnotation] != releaseName {
+			continue
+		}
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		if deployment.Status.ObservedGeneration < deployment.Generation ||
+			deployment.Status.UpdatedReplicas < replicas || deployment.Status.AvailableReplicas < replicas {
+			return false, nil
+		}
+	}
+
+	statefulSets := appsv1.StatefulSetList{}
+	if err := client.List(context.TODO(), &statefulSets, clipkg.InNamespace(namespace)); err != nil {
+		return false, err
+	}
+	for _, statefulSet := range statefulSets.Items {
+		if statefulSet.Annotations[releaseNameAnnotation] != releaseName {
+			continue
+		}
+		replicas := int32(1)
+		if statefulSet.Spec.Replicas != nil {
+			replicas = *statefulSet.Spec.Replicas
+		}
+		if statefulSet.Status.ObservedGeneration < statefulSet.Generation || statefulSet.Status.ReadyReplicas < replicas {
+			return false, nil
+		}
+	}
+
+	daemonSets := appsv1.DaemonSetList{}
+	if err := client.List(context.TODO(), &daemonSets, clipkg.InNamespace(namespace)); err != nil {
+		return false, err
+	}
+	for _, daemonSet := range daemonSets.Items {
+		if daemonSet.Annotations[releaseNameAnnotation] != releaseName {
+			continue
+		}
+		if daemonSet.Status.ObservedGeneration < daemonSet.Generation ||
+			daemonSet.Status.NumberAvailable < daemonSet.Status.DesiredNumberScheduled {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -10,8 +10,29 @@ import (
 )
 
 // GetComponents returns the list of components that are installable and upgradeable.
-// The components will be processed in the order items in the array
+// The components are sorted so that each component comes after the components it depends on.
 func GetComponents() []Component {
+	comps, err := SortByDependencies(getRegisteredComponents())
+	if err != nil {
+		// The registry is static, so this can only be caused by a coding error in the registry
+		panic(err)
+	}
+	return comps
+}
+
+// GetComponentGroups returns the components grouped so that the components in a group only depend on
+// components in earlier groups.  The components in a group can be processed in parallel.
+func GetComponentGroups() [][]Component {
+	groups, err := GroupByDependencies(getRegisteredComponents())
+	if err != nil {
+		// The registry is static, so this can only be caused by a coding error in the registry
+		panic(err)
+	}
+	return groups
+}
+
+// getRegisteredComponents returns the list of registered components along with their dependencies
+func getRegisteredComponents() []Component {
 	overridesDir := filepath.Join(config.Get().HelmConfigDir, "overrides")
 	vzChartsDir := filepath.Join(config.Get().HelmConfigDir, "charts")
 	thirdPartyChartsDir := config.Get().ThirdpartyChartsDir
@@ -29,6 +50,7 @@ func GetComponents() []Component {
 			chartNamespace:          "istio-system",
 			ignoreNamespaceOverride: true,
 			valuesFile:              filepath.Join(overridesDir, "istio-values.yaml"),
+			dependencies:            []string{"istio-base"},
 		},
 		helmComponent{
 			releaseName:             "istio-ingress",
@@ -36,6 +58,7 @@ func GetComponents() []Component {
 			chartNamespace:          "istio-system",
 			ignoreNamespaceOverride: true,
 			valuesFile:              filepath.Join(overridesDir, "istio-values.yaml"),
+			dependencies:            []string{"istiod"},
 		},
 		helmComponent{
 			releaseName:             "istio-egress",
@@ -43,6 +66,7 @@ func GetComponents() []Component {
 			chartNamespace:          "istio-system",
 			ignoreNamespaceOverride: true,
 			valuesFile:              filepath.Join(overridesDir, "istio-values.yaml"),
+			dependencies:            []string{"istiod"},
 		},
 		helmComponent{
 			releaseName:             "istiocoredns",
@@ -50,6 +74,7 @@ func GetComponents() []Component {
 			chartNamespace:          "istio-system",
 			ignoreNamespaceOverride: true,
 			valuesFile:              filepath.Join(overridesDir, "istio-values.yaml"),
+			dependencies:            []string{"istiod"},
 		},
 		helmComponent{
 			releaseName:             "grafana",
@@ -57,6 +82,7 @@ func GetComponents() []Component {
 			chartNamespace:          "istio-system",
 			ignoreNamespaceOverride: true,
 			valuesFile:              filepath.Join(overridesDir, "istio-values.yaml"),
+			dependencies:            []string{"istiod"},
 		},
 		helmComponent{
 			releaseName:             "prometheus",
@@ -64,6 +90,7 @@ func GetComponents() []Component {
 			chartNamespace:          "istio-system",
 			ignoreNamespaceOverride: true,
 			valuesFile:              filepath.Join(overridesDir, "istio-values.yaml"),
+			dependencies:            []string{"istiod"},
 		},
 		helmComponent{
 			releaseName:             "ingress-controller",
@@ -85,6 +112,7 @@ func GetComponents() []Component {
 			chartNamespace:          "cert-manager",
 			ignoreNamespaceOverride: true,
 			valuesFile:              filepath.Join(overridesDir, "external-dns-values.yaml"),
+			dependencies:            []string{"cert-manager"},
 		},
 		helmComponent{
 			releaseName:             "rancher",
@@ -92,6 +120,7 @@ func GetComponents() []Component {
 			chartNamespace:          "cattle-system",
 			ignoreNamespaceOverride: true,
 			valuesFile:              filepath.Join(overridesDir, "rancher-values.yaml"),
+			dependencies:            []string{"cert-manager", "ingress-controller"},
 		},
 		Verrazzano{},
 		helmComponent{
@@ -121,6 +150,7 @@ func GetComponents() []Component {
 			chartNamespace:          "verrazzano-system",
 			ignoreNamespaceOverride: true,
 			valuesFile:              filepath.Join(overridesDir, "verrazzano-application-operator-values.yaml"),
+			dependencies:            []string{"oam-kubernetes-runtime"},
 		},
 		helmComponent{
 			releaseName:             "mysql",
@@ -135,6 +165,7 @@ func GetComponents() []Component {
 			chartNamespace:          "keycloak",
 			ignoreNamespaceOverride: true,
 			valuesFile:              filepath.Join(overridesDir, "keycloak-values.yaml"),
+			dependencies:            []string{"mysql", "cert-manager", "ingress-controller"},
 		},
 	}
 }
@@ -37,3 +37,40 @@ func TestGetComponents(t *testing.T) {
 	assert.Equal(comps[16].Name(), "mysql")
 	assert.Equal(comps[17].Name(), "keycloak")
 }
+
+// TestGetComponentsDependencies tests the dependency order of the registered components
+// GIVEN the registered components
+//  WHEN I call GetComponents
+//  THEN every component comes after the components it depends on
+func TestGetComponentsDependencies(t *testing.T) {
+	assert := assert.New(t)
+	seen := map[string]bool{}
+	for _, comp := range GetComponents() {
+		for _, dep := range comp.GetDependencies() {
+			assert.True(seen[dep], "Component %s is before its dependency %s", comp.Name(), dep)
+		}
+		seen[comp.Name()] = true
+	}
+}
+
+// TestGetComponentGroups tests grouping the registered components
+// GIVEN the registered components
+//  WHEN I call GetComponentGroups
+//  THEN the components are grouped so that independent components are in the same group
+func TestGetComponentGroups(t *testing.T) {
+	assert := assert.New(t)
+	groups := GetComponentGroups()
+
+	var count int
+	for _, group := range groups {
+		count += len(group)
+	}
+	assert.Equal(18, count, "Wrong number of components")
+	assert.Len(groups, 3, "Wrong number of groups")
+	assert.Equal([]string{"istio-base", "ingress-controller", "cert-manager", "coherence-operator",
+		"weblogic-operator", "oam-kubernetes-runtime", "mysql"}, getNames(groups[0]), "Wrong components in group 0")
+	assert.Equal([]string{"istiod", "external-dns", "rancher", "verrazzano", "verrazzano-application-operator",
+		"keycloak"}, getNames(groups[1]), "Wrong components in group 1")
+	assert.Equal([]string{"istio-ingress", "istio-egress", "istiocoredns", "grafana", "prometheus"},
+		getNames(groups[2]), "Wrong components in group 2")
+}
@@ -28,6 +28,58 @@ func (v Verrazzano) Name() string {
 	return "verrazzano"
 }
 
+// GetDependencies returns the names of the components that Verrazzano depends on
+func (v Verrazzano) GetDependencies() []string {
+	return []string{"cert-manager", "ingress-controller"}
+}
+
+// PreInstall creates the Verrazzano system namespace if it doesn't exist
+func (v Verrazzano) PreInstall(log *zap.SugaredLogger, client clipkg.Client, namespace string) error {
+	return createNamespaceIfMissing(log, client, resolveNamespace(namespace))
+}
+
+// Install installs all of the Verrazzano home-grown components using the verrazzano helm chart
+// that is included in the operator image, with the overrides from the Verrazzano resource.
+func (v Verrazzano) Install(log *zap.SugaredLogger, _ clipkg.Client, namespace string, overrides []string) error {
+	_, _, err := installFunc(log, vzReleaseName, resolveNamespace(namespace), VzChartDir(), "", overrides...)
+	return err
+}
+
+// PostInstall is a no-op for the Verrazzano component
+func (v Verrazzano) PostInstall(_ *zap.SugaredLogger, _ clipkg.Client, _ string) error {
+	return nil
+}
+
+// IsReady returns true if the verrazzano helm release has been deployed, and the workloads of the release are
+// available
+func (v Verrazzano) IsReady(log *zap.SugaredLogger, client clipkg.Client, namespace string) bool {
+	return isReleaseReady(log, client, vzReleaseName, resolveNamespace(namespace))
+}
+
+// GetChartDir returns the chart directory of the verrazzano helm chart
+func (v Verrazzano) GetChartDir() string {
+	return VzChartDir()
+}
+
+// GetReleaseInfo returns the helm release info for the verrazzano component
+func (v Verrazzano) GetReleaseInfo(namespace string) (*helm.ReleaseInfo, error) {
+	return helm.GetReleaseInfo(vzReleaseName, resolveNamespace(namespace))
+}
+
+// Uninstall uninstalls the verrazzano helm release if it is installed
+func (v Verrazzano) Uninstall(log *zap.SugaredLogger, _ clipkg.Client, namespace string) error {
+	found, err := helm.IsReleaseInstalled(vzReleaseName, resolveNamespace(namespace))
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Infof("Skipping uninstall of component %s since it is not installed", vzReleaseName)
+		return nil
+	}
+	_, _, err = uninstallFunc(log, vzReleaseName, resolveNamespace(namespace))
+	return err
+}
+
 // Upgrade upgrades all of the Verrazzano home-grown components including the following:
 //  Verrazzano operator
 //  Verrazzano WLS micro-operator
@@ -40,8 +92,33 @@ func (v Verrazzano) Name() string {
 // Upgrade is done by using the helm chart upgrade command.   This command will apply the latest chart
 // that is included in the operator image, while retaining any helm value overrides that were applied during
 // install.
-func (v Verrazzano) Upgrade(log *zap.SugaredLogger, _ clipkg.Client, namespace string) error {
-	_, _, err := helm.Upgrade(log, vzReleaseName, resolveNamespace(namespace), VzChartDir(), "")
+func (v Verrazzano) Upgrade(log *zap.SugaredLogger, _ clipkg.Client, namespace string, overrides []string) error {
+	if isChartVersionDeployed(log, vzReleaseName, resolveNamespace(namespace), VzChartDir()) {
+		return nil
+	}
+	_, _, err := upgradeFunc(log, vzReleaseName, resolveNamespace(namespace), VzChartDir(), "", overrides...)
+	return err
+}
+
+// Reconfigure will re-apply the verrazzano helm release with the existing values and the set arguments.
+// The reconfigure is skipped if the release is not installed.
+func (v Verrazzano) Reconfigure(log *zap.SugaredLogger, _ clipkg.Client, namespace string, values helm.ReconfigureValues) error {
+	ns := resolveNamespace(namespace)
+	found, err := helm.IsReleaseInstalled(vzReleaseName, ns)
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Infof("Skipping reconfigure of component %s since it is not installed", vzReleaseName)
+		return nil
+	}
+	_, _, err = reconfigureFunc(log, vzReleaseName, ns, VzChartDir(), values)
+	return err
+}
+
+// Rollback will roll back the verrazzano helm release
+func (v Verrazzano) Rollback(log *zap.SugaredLogger, _ clipkg.Client, namespace string, revision int) error {
+	_, _, err := rollbackFunc(log, vzReleaseName, resolveNamespace(namespace), revision)
 	return err
 }
 
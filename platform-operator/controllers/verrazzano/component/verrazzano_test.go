@@ -4,12 +4,18 @@
 package component
 
 import (
+	"context"
+	"errors"
 	"os/exec"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 // fakeRunner is used to test helm without actually running an OS exec command
@@ -36,10 +42,110 @@ func TestVzUpgrade(t *testing.T) {
 	vz := Verrazzano{}
 	helm.SetCmdRunner(fakeRunner{})
 	defer helm.SetDefaultRunner()
-	err := vz.Upgrade(zap.S(), nil, "")
+	err := vz.Upgrade(zap.S(), nil, "", nil)
 	assert.NoError(err, "Upgrade returned an error")
 }
 
+// TestVzInstall tests the Verrazzano component install
+// GIVEN a Verrazzano component
+//  WHEN I call PreInstall and Install
+//  THEN the Verrazzano namespace is created and the install returns success
+func TestVzInstall(t *testing.T) {
+	assert := assert.New(t)
+	vz := Verrazzano{}
+	helm.SetCmdRunner(fakeRunner{})
+	defer helm.SetDefaultRunner()
+	client := fake.NewFakeClientWithScheme(k8scheme.Scheme)
+	err := vz.PreInstall(zap.S(), client, "")
+	assert.NoError(err, "PreInstall returned an error")
+	ns := corev1.Namespace{}
+	err = client.Get(context.TODO(), types.NamespacedName{Name: vzDefaultNamespace}, &ns)
+	assert.NoError(err, "Verrazzano namespace was not created")
+	err = vz.Install(zap.S(), client, "", nil)
+	assert.NoError(err, "Install returned an error")
+	assert.NoError(vz.PostInstall(zap.S(), client, ""), "PostInstall returned an error")
+}
+
+// TestVzInstallOverrides tests the Verrazzano component install
+// GIVEN a Verrazzano component
+//  WHEN I call Install with overrides
+//  THEN the verrazzano chart is installed in the Verrazzano namespace with the overrides
+func TestVzInstallOverrides(t *testing.T) {
+	assert := assert.New(t)
+	vz := Verrazzano{}
+	setInstallFunc(func(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overridesYaml string, overrides ...string) (stdout []byte, stderr []byte, err error) {
+		if releaseName != vzReleaseName || namespace != vzDefaultNamespace || chartDir != VzChartDir() ||
+			len(overrides) != 2 || overrides[1] != "config:\n  envName: test" {
+			return []byte("error"), []byte(""), errors.New("Invalid install parameters")
+		}
+		return []byte("success"), []byte(""), nil
+	})
+	defer setDefaultInstallFunc()
+	err := vz.Install(zap.S(), nil, "", []string{"verrazzanoOperator: {}", "config:\n  envName: test"})
+	assert.NoError(err, "Install returned an error")
+}
+
+// TestVzRollback tests the Verrazzano component rollback
+// GIVEN a Verrazzano component
+//  WHEN I call Rollback with a revision
+//  THEN the verrazzano release in the Verrazzano namespace is rolled back to the revision
+func TestVzRollback(t *testing.T) {
+	assert := assert.New(t)
+	vz := Verrazzano{}
+	setRollbackFunc(func(log *zap.SugaredLogger, releaseName string, namespace string, revision int) (stdout []byte, stderr []byte, err error) {
+		if releaseName != vzReleaseName || namespace != vzDefaultNamespace || revision != 2 {
+			return []byte("error"), []byte(""), errors.New("Invalid rollback parameters")
+		}
+		return []byte("success"), []byte(""), nil
+	})
+	defer setDefaultRollbackFunc()
+	assert.NoError(vz.Rollback(zap.S(), nil, "", 2), "Rollback returned an error")
+}
+
+// TestVzReconfigure tests the Verrazzano component reconfigure
+// GIVEN a Verrazzano component
+//  WHEN I call Reconfigure
+//  THEN the reconfigure function is called with the verrazzano release when the release is installed
+//   AND the reconfigure is skipped when the release is not installed
+func TestVzReconfigure(t *testing.T) {
+	assert := assert.New(t)
+	vz := Verrazzano{}
+	called := false
+	setReconfigureFunc(func(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, values helm.ReconfigureValues) (stdout []byte, stderr []byte, err error) {
+		called = true
+		if releaseName != vzReleaseName || namespace != vzDefaultNamespace || len(values.SetArgs) != 1 {
+			return []byte("error"), []byte(""), errors.New("Invalid reconfigure parameters")
+		}
+		return []byte("success"), []byte(""), nil
+	})
+	defer setDefaultReconfigureFunc()
+	defer helm.SetDefaultRunner()
+	values := helm.ReconfigureValues{SetArgs: []helm.SetArg{{Name: "a", Value: "b"}}}
+
+	helm.SetCmdRunner(vzNotFoundRunner{})
+	assert.NoError(vz.Reconfigure(zap.S(), nil, "", values), "Reconfigure returned an error")
+	assert.False(called, "Reconfigure should be skipped when the release is not installed")
+
+	helm.SetCmdRunner(fakeRunner{})
+	assert.NoError(vz.Reconfigure(zap.S(), nil, "", values), "Reconfigure returned an error")
+	assert.True(called, "Reconfigure should be called when the release is installed")
+}
+
+// TestVzIsReady tests the Verrazzano component readiness check
+// GIVEN a Verrazzano component
+//  WHEN I call IsReady
+//  THEN true is returned when the verrazzano helm release is deployed
+func TestVzIsReady(t *testing.T) {
+	assert := assert.New(t)
+	vz := Verrazzano{}
+	defer helm.SetDefaultRunner()
+	client := fake.NewFakeClientWithScheme(k8scheme.Scheme)
+	helm.SetCmdRunner(helmStatusRunner{status: helm.ReleaseStatusDeployed})
+	assert.True(vz.IsReady(zap.S(), client, ""), "Verrazzano should be ready")
+	helm.SetCmdRunner(fakeRunner{})
+	assert.False(vz.IsReady(zap.S(), client, ""), "Verrazzano should not be ready when the status can't be parsed")
+}
+
 // TestVzResolveNamespace tests the Verrazzano component name
 // GIVEN a Verrazzano component
 //  WHEN I call resolveNamespace
@@ -58,3 +164,12 @@ func TestVzResolveNamespace(t *testing.T) {
 func (r fakeRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
 	return []byte("success"), []byte(""), nil
 }
+
+// vzNotFoundRunner returns the helm error for a release that is not installed
+type vzNotFoundRunner struct {
+}
+
+// Run returns the helm release not found error
+func (r vzNotFoundRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	return []byte(""), []byte("Error: release: not found"), errors.New("not found error")
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// initComponentStatus adds a NotInstalled status for each registered component that doesn't have a status yet
+func initComponentStatus(cr *installv1alpha1.Verrazzano) {
+	for _, comp := range component.GetComponents() {
+		if _, ok := cr.Status.Components[comp.Name()]; !ok {
+			setComponentState(cr, comp.Name(), installv1alpha1.CompStateNotInstalled, nil)
+		}
+	}
+}
+
+// setComponentState sets the state of a component in the resource status.  The transition time is only
+// changed when the state changes.  The last error is cleared unless an error is passed in.
+func setComponentState(cr *installv1alpha1.Verrazzano, name string, state installv1alpha1.ComponentStateType, compErr error) {
+	if cr.Status.Components == nil {
+		cr.Status.Components = make(map[string]installv1alpha1.ComponentStatus)
+	}
+	compStatus := cr.Status.Components[name]
+	if compStatus.State != state {
+		compStatus.State = state
+		compStatus.LastTransitionTime = getTransitionTime()
+	}
+	compStatus.LastError = ""
+	if compErr != nil {
+		compStatus.LastError = compErr.Error()
+	}
+	cr.Status.Components[name] = compStatus
+}
+
+// setComponentReleaseStatus sets the state of a component that is not being installed or upgraded from its
+// helm release.  The chart version and helm revision are also recorded if the release is installed.  The component
+// is failed if the release info can't be read, since its state is unknown.
+func setComponentReleaseStatus(log *zap.SugaredLogger, cr *installv1alpha1.Verrazzano, comp component.Component) {
+	info, err := comp.GetReleaseInfo(cr.Namespace)
+	if err != nil {
+		// An error getting the release info is non-fatal, the component is marked as failed with the error
+		log.Errorf("Error getting the release info for component %s: %v", comp.Name(), err)
+		setComponentState(cr, comp.Name(), installv1alpha1.CompStateFailed, err)
+		return
+	}
+	if info == nil {
+		setComponentState(cr, comp.Name(), installv1alpha1.CompStateNotInstalled, nil)
+		return
+	}
+	state := installv1alpha1.CompStateReady
+	if info.Status != helm.ReleaseStatusDeployed {
+		state = installv1alpha1.CompStateFailed
+	}
+	setComponentState(cr, comp.Name(), state, nil)
+	compStatus := cr.Status.Components[comp.Name()]
+	compStatus.ChartVersion = info.ChartVersion
+	compStatus.HelmRevision = info.Revision
+	cr.Status.Components[comp.Name()] = compStatus
+}
+
+// updateComponentStatus saves the component states in the resource status without adding a condition
+func (r *Reconciler) updateComponentStatus(log *zap.SugaredLogger, cr *installv1alpha1.Verrazzano) error {
+	err := r.updateVerrazzanoStatus(context.TODO(), cr)
+	if err != nil && !errors.IsConflict(err) {
+		log.Errorf("Failed to update verrazzano resource status: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"go.uber.org/zap"
+)
+
+// TestSetComponentReleaseStatus tests the setComponentReleaseStatus function
+// GIVEN a component with a deployed release
+//  WHEN the status of the component is set from the release
+//  THEN the component is ready and the chart version and revision are recorded
+func TestSetComponentReleaseStatus(t *testing.T) {
+	asserts := assert.New(t)
+	vz := newInstallVerrazzano()
+
+	setComponentReleaseStatus(zap.S(), vz, fakeComponent{name: "fake"})
+	asserts.Equal(vzapi.CompStateReady, vz.Status.Components["fake"].State)
+	asserts.Equal("0.1.0", vz.Status.Components["fake"].ChartVersion)
+	asserts.Equal(1, vz.Status.Components["fake"].HelmRevision)
+	asserts.Empty(vz.Status.Components["fake"].LastError)
+}
+
+// TestSetComponentReleaseStatusError tests the setComponentReleaseStatus function
+// GIVEN a ready component whose release info can't be read
+//  WHEN the status of the component is set from the release
+//  THEN the component is failed with the error
+func TestSetComponentReleaseStatusError(t *testing.T) {
+	asserts := assert.New(t)
+	vz := newInstallVerrazzano()
+	setComponentState(vz, "fake", vzapi.CompStateReady, nil)
+
+	setComponentReleaseStatus(zap.S(), vz, fakeComponent{name: "fake", releaseInfoErr: errors.New("unexpected error")})
+	asserts.Equal(vzapi.CompStateFailed, vz.Status.Components["fake"].State)
+	asserts.Equal("unexpected error", vz.Status.Components["fake"].LastError)
+}
@@ -14,6 +14,7 @@ import (
 	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/installjob"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/uninstalljob"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/k8s"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/vzinstance"
 	"go.uber.org/zap"
@@ -23,7 +24,9 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -38,6 +41,7 @@ type Reconciler struct {
 	client.Client
 	Scheme     *runtime.Scheme
 	Controller controller.Controller
+	Recorder   record.EventRecorder
 	DryRun     bool
 }
 
@@ -51,6 +55,7 @@ const configDataKey = "spec"
 // +kubebuilder:rbac:groups=install.verrazzano.io,resources=verrazzanos,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=install.verrazzano.io,resources=verrazzanos/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;watch;list;create;update;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.TODO()
 	log := zap.S().With("resource", fmt.Sprintf("%s:%s", req.Namespace, req.Name))
@@ -62,6 +67,7 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		// If the resource is not found, that means all of the finalizers have been removed,
 		// and the verrazzano resource has been deleted, so there is nothing left to do.
 		if errors.IsNotFound(err) {
+			deleteStateMetric(req.Namespace, req.Name)
 			return reconcile.Result{}, nil
 		}
 
@@ -69,31 +75,41 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		log.Errorf("Failed to fetch verrazzano resource: %v", err)
 		return reconcile.Result{}, err
 	}
+	recordStateMetric(vz)
 
 	// The verrazzano resource is being deleted
 	if !vz.ObjectMeta.DeletionTimestamp.IsZero() {
+		// Uninstall the components from the operator if enabled, otherwise the uninstall job is used
+		if config.Get().ComponentInstallEnabled {
+			return r.reconcileComponentUninstall(ctx, log, vz)
+		}
+
 		// Finalizer is present, so lets do the uninstall
 		if containsString(vz.ObjectMeta.Finalizers, finalizerName) {
+			// A failed uninstall is retried unless the uninstall policy removes the finalizer on failure
+			if isLastCondition(vz.Status, installv1alpha1.UninstallFailed) && !vz.Spec.UninstallPolicy.RemoveFinalizerOnFailure {
+				return r.retryUninstall(ctx, log, vz)
+			}
 			if err := r.createUninstallJob(log, vz); err != nil {
 				// If fail to start the uninstall, return with error so that it can be retried
 				return reconcile.Result{}, err
 			}
 
 			// Remove the finalizer and update the verrazzano resource if the uninstall has finished.
-			for _, condition := range vz.Status.Conditions {
-				if condition.Type == installv1alpha1.UninstallComplete || condition.Type == installv1alpha1.UninstallFailed {
-					log.Infof("Removing finalizer %s", finalizerName)
-					vz.ObjectMeta.Finalizers = removeString(vz.ObjectMeta.Finalizers, finalizerName)
-					err := r.Update(ctx, vz)
-					if err != nil && !errors.IsConflict(err) {
-						return reconcile.Result{}, err
-					}
+			if isUninstallFinished(log, vz) {
+				if err := r.removeFinalizer(ctx, log, vz); err != nil {
+					return reconcile.Result{}, err
 				}
 			}
 		}
 		return reconcile.Result{}, nil
 	}
 
+	// Layer the settings of the profile under the spec
+	if err := r.applyProfile(ctx, log, vz); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// If Verrazzano is installed see if upgrade is needed
 	if isInstalled(vz.Status) {
 		// If the version is specified and different than the current version of the installation
@@ -101,8 +117,22 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		if len(vz.Spec.Version) > 0 && vz.Spec.Version != vz.Status.Version {
 			return r.reconcileUpgrade(log, req, vz)
 		}
-		// nothing to do, installation already at target version
-		return ctrl.Result{}, nil
+		// Installation already at target version, re-apply any components whose configuration changed
+		return r.reconcileUpdate(ctx, log, vz)
+	}
+
+	// Retry a failed install if the install policy allows it
+	if isLastCondition(vz.Status, installv1alpha1.InstallFailed) {
+		result, retry, err := r.retryInstall(ctx, log, vz)
+		if err != nil || !retry {
+			return result, err
+		}
+	}
+
+	// Install the components from the operator if enabled, otherwise the install job is used
+	if config.Get().ComponentInstallEnabled {
+		result, err := r.reconcileComponentInstall(ctx, log, vz)
+		return installRetryResult(log, vz, result), err
 	}
 
 	if err := r.createServiceAccount(ctx, log, vz); err != nil {
@@ -117,6 +147,8 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	if vz.Spec.Components.DNS.OCI != (installv1alpha1.OCI{}) {
 		err := r.doesOCIDNSConfigSecretExist(vz)
 		if err != nil {
+			r.Recorder.Eventf(vz, corev1.EventTypeWarning, validationFailedReason, "Failed to get the OCI DNS config secret %s: %v",
+				vz.Spec.Components.DNS.OCI.OCIConfigSecret, err)
 			return reconcile.Result{}, err
 		}
 	}
@@ -134,7 +166,7 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return reconcile.Result{}, err
 	}
 
-	return ctrl.Result{}, err
+	return installRetryResult(log, vz, ctrl.Result{}), err
 }
 
 func (r *Reconciler) doesOCIDNSConfigSecretExist(vz *installv1alpha1.Verrazzano) error {
@@ -249,7 +281,9 @@ func (r *Reconciler) createInstallJob(ctx context.Context, log *zap.SugaredLogge
 				JobImage:           os.Getenv("VZ_INSTALL_IMAGE"),
 				DryRun:             r.DryRun,
 			},
-			ConfigMapName: configMapName,
+			ConfigMapName:         configMapName,
+			ProgressConfigMapName: buildProgressConfigMapName(vz.Name),
+			VerrazzanoName:        vz.Name,
 		})
 
 	// Set verrazzano resource as the owner and controller of the job resource.
@@ -267,14 +301,11 @@ func (r *Reconciler) createInstallJob(ctx context.Context, log *zap.SugaredLogge
 		if err != nil {
 			return err
 		}
+		r.Recorder.Eventf(vz, corev1.EventTypeNormal, installJobCreatedReason, "Created install job %s", job.Name)
 
 		// Add our finalizer if not already added
-		if !containsString(vz.ObjectMeta.Finalizers, finalizerName) {
-			log.Infof("Adding finalizer %s", finalizerName)
-			vz.ObjectMeta.Finalizers = append(vz.ObjectMeta.Finalizers, finalizerName)
-			if err := r.Update(ctx, vz); err != nil {
-				return err
-			}
+		if err := r.addFinalizer(ctx, log, vz); err != nil {
+			return err
 		}
 
 		// Delete leftover uninstall job if we find one.
@@ -294,7 +325,16 @@ func (r *Reconciler) createInstallJob(ctx context.Context, log *zap.SugaredLogge
 	}
 	vz.Status.Version = chartSemVer.ToString()
 
-	err = r.setInstallCondition(log, jobFound, vz)
+	// Surface the progress that the running install job reported, including the error of a failed install
+	progressChanged := false
+	if len(jobFound.Name) > 0 && jobFound.Status.Succeeded == 0 {
+		progressChanged, err = r.updateInstallProgress(ctx, log, vz, jobFound)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = r.setInstallCondition(log, jobFound, vz, progressChanged)
 
 	return err
 }
@@ -320,6 +360,7 @@ func (r *Reconciler) cleanupUninstallJob(jobName string, namespace string, log *
 
 // SetupWithManager creates a new controller and adds it to the manager
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	initMetrics()
 	var err error
 	r.Controller, err = ctrl.NewControllerManagedBy(mgr).
 		For(&installv1alpha1.Verrazzano{}).
@@ -361,6 +402,7 @@ func (r *Reconciler) createUninstallJob(log *zap.SugaredLogger, vz *installv1alp
 		if err != nil {
 			return err
 		}
+		r.Recorder.Eventf(vz, corev1.EventTypeNormal, uninstallJobCreatedReason, "Created uninstall job %s", job.Name)
 	} else if err != nil {
 		return err
 	}
@@ -373,6 +415,34 @@ func (r *Reconciler) createUninstallJob(log *zap.SugaredLogger, vz *installv1alp
 	return nil
 }
 
+// isUninstallFinished returns true if the uninstall completed, or if the uninstall failed and the uninstall policy
+// removes the finalizer on failure
+func isUninstallFinished(log *zap.SugaredLogger, vz *installv1alpha1.Verrazzano) bool {
+	for _, condition := range vz.Status.Conditions {
+		if condition.Type == installv1alpha1.UninstallComplete {
+			return true
+		}
+		if condition.Type == installv1alpha1.UninstallFailed {
+			if vz.Spec.UninstallPolicy.RemoveFinalizerOnFailure {
+				return true
+			}
+			log.Infof("Uninstall failed, keeping finalizer %s", finalizerName)
+		}
+	}
+	return false
+}
+
+// removeFinalizer removes our finalizer from the resource so that the resource can be deleted
+func (r *Reconciler) removeFinalizer(ctx context.Context, log *zap.SugaredLogger, vz *installv1alpha1.Verrazzano) error {
+	log.Infof("Removing finalizer %s", finalizerName)
+	vz.ObjectMeta.Finalizers = removeString(vz.ObjectMeta.Finalizers, finalizerName)
+	err := r.Update(ctx, vz)
+	if err != nil && !errors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
+
 // buildInstallJobName returns the name of an install job based on verrazzano resource name.
 func buildInstallJobName(name string) string {
 	return fmt.Sprintf("verrazzano-install-%s", name)
@@ -405,14 +475,11 @@ func buildInternalConfigMapName(name string) string {
 
 // updateStatus updates the status in the verrazzano CR
 func (r *Reconciler) updateStatus(log *zap.SugaredLogger, cr *installv1alpha1.Verrazzano, message string, conditionType installv1alpha1.ConditionType) error {
-	t := time.Now().UTC()
 	condition := installv1alpha1.Condition{
-		Type:    conditionType,
-		Status:  corev1.ConditionTrue,
-		Message: message,
-		LastTransitionTime: fmt.Sprintf("%d-%02d-%02dT%02d:%02d:%02dZ",
-			t.Year(), t.Month(), t.Day(),
-			t.Hour(), t.Minute(), t.Second()),
+		Type:               conditionType,
+		Status:             corev1.ConditionTrue,
+		Message:            message,
+		LastTransitionTime: getTransitionTime(),
 	}
 	cr.Status.Conditions = append(cr.Status.Conditions, condition)
 
@@ -425,23 +492,21 @@ func (r *Reconciler) updateStatus(log *zap.SugaredLogger, cr *installv1alpha1.Ve
 	case installv1alpha1.UpgradeStarted:
 		cr.Status.State = installv1alpha1.Upgrading
 	case installv1alpha1.InstallComplete:
-		domain, err := buildDomain(r.Client, cr)
-		if err != nil {
-			// An error building the instance info is non-fatal, log and continue
-			log.Errorf("Error obtaining DNS domain for installed instance, %v", err)
-		} else {
-			cr.Status.VerrazzanoInstance = vzinstance.GetInstanceInfo(domain)
-		}
+		r.setInstanceInfo(log, cr)
 		fallthrough
 	case installv1alpha1.UninstallComplete, installv1alpha1.UpgradeComplete:
 		cr.Status.State = installv1alpha1.Ready
-	case installv1alpha1.InstallFailed, installv1alpha1.UpgradeFailed, installv1alpha1.UninstallFailed:
+	case installv1alpha1.InstallFailed, installv1alpha1.UpgradeFailed, installv1alpha1.UninstallFailed, installv1alpha1.UpgradeRolledBack,
+		installv1alpha1.PreflightFailed:
 		cr.Status.State = installv1alpha1.Failed
 	}
 	log.Infof("Setting verrazzano resource condition and state: %v/%v", condition.Type, cr.Status.State)
+	r.Recorder.Event(cr, conditionEventType(conditionType), string(conditionType), message)
+	recordStateMetric(cr)
+	recordOperationMetric(cr)
 
 	// Update the status
-	err := r.Status().Update(context.TODO(), cr)
+	err := r.updateVerrazzanoStatus(context.TODO(), cr)
 	if err != nil && !errors.IsConflict(err) {
 		log.Errorf("Failed to update verrazzano resource status: %v", err)
 		return err
@@ -449,22 +514,89 @@ func (r *Reconciler) updateStatus(log *zap.SugaredLogger, cr *installv1alpha1.Ve
 	return nil
 }
 
-// setInstallCondition sets the verrazzano resource condition in status for install
-func (r *Reconciler) setInstallCondition(log *zap.SugaredLogger, job *batchv1.Job, vz *installv1alpha1.Verrazzano) (err error) {
-	// If the job has succeeded or failed add the appropriate condition
+// setInstanceInfo sets the URLs of the installed instance in the status
+func (r *Reconciler) setInstanceInfo(log *zap.SugaredLogger, cr *installv1alpha1.Verrazzano) {
+	domain, err := buildDomain(r.Client, cr)
+	if err != nil {
+		// An error building the instance info is non-fatal, log and continue
+		log.Errorf("Error obtaining DNS domain for installed instance, %v", err)
+		return
+	}
+	cr.Status.VerrazzanoInstance = vzinstance.GetInstanceInfo(domain, &cr.Spec.Components)
+}
+
+// applyProfile replaces the spec of the resource with the effective spec, which has the settings of the profile
+// layered under the spec.  The effective spec is only used by the reconciler and is never saved in the resource.
+// The writes of the resource must restore the effective spec, see updateVerrazzanoStatus and addFinalizer.
+func (r *Reconciler) applyProfile(ctx context.Context, log *zap.SugaredLogger, vz *installv1alpha1.Verrazzano) error {
+	profile, err := installv1alpha1.GetProfile(ctx, r, vz.Spec.Profile)
+	if err != nil {
+		log.Errorf("Failed to get the install profile: %v", err)
+		r.Recorder.Event(vz, corev1.EventTypeWarning, validationFailedReason, err.Error())
+		return err
+	}
+	effectiveSpec, err := installv1alpha1.GetEffectiveSpec(profile, &vz.Spec)
+	if err != nil {
+		log.Errorf("Failed to apply the install profile %s: %v", vz.Spec.Profile, err)
+		r.Recorder.Eventf(vz, corev1.EventTypeWarning, validationFailedReason, "Failed to apply the install profile %s: %v", vz.Spec.Profile, err)
+		return err
+	}
+	vz.Spec = *effectiveSpec
+	return nil
+}
+
+// updateVerrazzanoStatus updates the status of the resource.  The update decodes the stored resource into the
+// object, so the effective spec is restored after the update.
+func (r *Reconciler) updateVerrazzanoStatus(ctx context.Context, vz *installv1alpha1.Verrazzano) error {
+	effectiveSpec := vz.Spec
+	err := r.Status().Update(ctx, vz)
+	vz.Spec = effectiveSpec
+	return err
+}
+
+// addFinalizer adds our finalizer to the resource if not already added.  Only the finalizers are patched, so
+// that the effective spec is not saved in the resource.
+func (r *Reconciler) addFinalizer(ctx context.Context, log *zap.SugaredLogger, vz *installv1alpha1.Verrazzano) error {
+	if containsString(vz.ObjectMeta.Finalizers, finalizerName) {
+		return nil
+	}
+	log.Infof("Adding finalizer %s", finalizerName)
+	patch := client.MergeFrom(vz.DeepCopy())
+	vz.ObjectMeta.Finalizers = append(vz.ObjectMeta.Finalizers, finalizerName)
+	effectiveSpec := vz.Spec
+	if err := r.Patch(ctx, vz, patch); err != nil {
+		return err
+	}
+	// The patched resource has the spec of the resource, restore the effective spec
+	vz.Spec = effectiveSpec
+	return nil
+}
+
+// getTransitionTime returns the current time formatted for a status transition time
+func getTransitionTime() string {
+	t := time.Now().UTC()
+	return fmt.Sprintf("%d-%02d-%02dT%02d:%02d:%02dZ",
+		t.Year(), t.Month(), t.Day(),
+		t.Hour(), t.Minute(), t.Second())
+}
+
+// setInstallCondition sets the verrazzano resource condition in status for install.  The status is also updated
+// if the install progress changed.
+func (r *Reconciler) setInstallCondition(log *zap.SugaredLogger, job *batchv1.Job, vz *installv1alpha1.Verrazzano, progressChanged bool) (err error) {
+	// If the job has succeeded or failed add the appropriate condition, unless it was already added for the
+	// current install attempt
 	if job.Status.Succeeded != 0 || job.Status.Failed != 0 {
-		for _, condition := range vz.Status.Conditions {
-			if condition.Type == installv1alpha1.InstallComplete || condition.Type == installv1alpha1.InstallFailed {
-				return nil
-			}
+		if isLastCondition(vz.Status, installv1alpha1.InstallComplete) || isLastCondition(vz.Status, installv1alpha1.InstallFailed) {
+			return r.updateInstallProgressStatus(log, vz, progressChanged)
 		}
 		var message string
 		var conditionType installv1alpha1.ConditionType
 		if job.Status.Succeeded == 1 {
 			message = "Verrazzano install completed successfully"
 			conditionType = installv1alpha1.InstallComplete
+			setInstallProgressComplete(vz)
 		} else {
-			message = "Verrazzano install failed to complete"
+			message = fmt.Sprintf("Verrazzano install failed to complete - %s", fmtGeneration(vz.Generation))
 			conditionType = installv1alpha1.InstallFailed
 		}
 		return r.updateStatus(log, vz, message, conditionType)
@@ -473,21 +605,33 @@ func (r *Reconciler) setInstallCondition(log *zap.SugaredLogger, job *batchv1.Jo
 	// Add the install started condition if not already added
 	for _, condition := range vz.Status.Conditions {
 		if condition.Type == installv1alpha1.InstallStarted {
-			return nil
+			return r.updateInstallProgressStatus(log, vz, progressChanged)
 		}
 	}
 
 	return r.updateStatus(log, vz, "Verrazzano install in progress", installv1alpha1.InstallStarted)
 }
 
+// updateInstallProgressStatus updates the status in the verrazzano CR if the install progress changed
+func (r *Reconciler) updateInstallProgressStatus(log *zap.SugaredLogger, vz *installv1alpha1.Verrazzano, progressChanged bool) error {
+	if !progressChanged {
+		return nil
+	}
+	err := r.updateVerrazzanoStatus(context.TODO(), vz)
+	if err != nil && !errors.IsConflict(err) {
+		log.Errorf("Failed to update the install progress of the verrazzano resource: %v", err)
+		return err
+	}
+	return nil
+}
+
 // setUninstallCondition sets the verrazzano resource condition in status for uninstall
 func (r *Reconciler) setUninstallCondition(log *zap.SugaredLogger, job *batchv1.Job, vz *installv1alpha1.Verrazzano) (err error) {
 	// If the job has succeeded or failed add the appropriate condition
 	if job.Status.Succeeded != 0 || job.Status.Failed != 0 {
-		for _, condition := range vz.Status.Conditions {
-			if condition.Type == installv1alpha1.UninstallComplete || condition.Type == installv1alpha1.UninstallFailed {
-				return nil
-			}
+		// Only the result of the last uninstall job is checked, since a failed uninstall job is retried
+		if isLastCondition(vz.Status, installv1alpha1.UninstallComplete) || isLastCondition(vz.Status, installv1alpha1.UninstallFailed) {
+			return nil
 		}
 
 		// Remove the owner reference so that the install job is not deleted when the verrazzano resource is deleted
@@ -522,54 +666,79 @@ func (r *Reconciler) setUninstallCondition(log *zap.SugaredLogger, job *batchv1.
 	return r.updateStatus(log, vz, "Verrazzano uninstall in progress", installv1alpha1.UninstallStarted)
 }
 
-// saveInstallSpec Saves the install spec in a configmap to use with upgrade/updates later on
+// saveInstallSpec Saves the install spec in a configmap to use with upgrade/updates later on.  The hash and keys of
+// the overrides of the components are saved with the spec, so that a change to the contents of an override
+// ConfigMap or Secret is detected.
 func (r *Reconciler) saveVerrazzanoSpec(ctx context.Context, log *zap.SugaredLogger, vz *installv1alpha1.Verrazzano) (err error) {
 	installSpecBytes, err := yaml.Marshal(vz.Spec)
 	if err != nil {
 		return err
 	}
 	installSpec := base64.StdEncoding.EncodeToString(installSpecBytes)
+	overrides, err := getOverridesData(ctx, log, r, vz)
+	if err != nil {
+		// The overrides in the saved spec are compared instead
+		log.Infof("Unable to save the overrides of the components for %s: %v", vz.Name, err)
+	}
 	installConfig, err := r.getInternalConfigMap(ctx, vz)
 	if err != nil {
 		if !errors.IsNotFound(err) {
 			return err
 		}
-		configMapName := buildInternalConfigMapName(vz.Name)
 		configData := make(map[string]string)
 		configData[configDataKey] = installSpec
-		// Create the configmap and set the owner reference to the VZ installer resource for garbage collection
-		installConfig = &corev1.ConfigMap{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      configMapName,
-				Namespace: vz.Namespace,
-				OwnerReferences: []metav1.OwnerReference{{
-					APIVersion: vz.APIVersion,
-					Kind:       vz.Kind,
-					Name:       vz.Name,
-					UID:        vz.UID,
-				}},
-			},
-			Data: configData,
+		if len(overrides) > 0 {
+			configData[overridesDataKey] = overrides
 		}
+		installConfig = newInternalConfigMap(vz, configData)
 		err := r.Create(ctx, installConfig)
 		if err != nil {
-			log.Errorf("Unable to create installer config map %s: %v", configMapName, err)
+			log.Errorf("Unable to create installer config map %s: %v", installConfig.Name, err)
 			return err
 		}
 	} else {
 		// Update the configmap if the data has changed
-		currentConfigData := installConfig.Data[configDataKey]
-		if currentConfigData != installSpec {
+		if installConfig.Data[configDataKey] != installSpec || installConfig.Data[overridesDataKey] != overrides {
+			if installConfig.Data == nil {
+				installConfig.Data = make(map[string]string)
+			}
 			installConfig.Data[configDataKey] = installSpec
+			if len(overrides) > 0 {
+				installConfig.Data[overridesDataKey] = overrides
+			} else {
+				delete(installConfig.Data, overridesDataKey)
+			}
 			return r.Update(ctx, installConfig)
 		}
 	}
 	return nil
 }
 
+// newInternalConfigMap returns the internal configmap with the owner reference set to the VZ installer
+// resource for garbage collection
+func newInternalConfigMap(vz *installv1alpha1.Verrazzano, configData map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      buildInternalConfigMapName(vz.Name),
+			Namespace: vz.Namespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: vz.APIVersion,
+				Kind:       vz.Kind,
+				Name:       vz.Name,
+				UID:        vz.UID,
+			}},
+		},
+		Data: configData,
+	}
+}
+
 // getSavedInstallSpec Returns the saved Verrazzano resource Spec field from the internal ConfigMap, or an error if it can't be restored
 func (r *Reconciler) getSavedInstallSpec(ctx context.Context, log *zap.SugaredLogger, vz *installv1alpha1.Verrazzano) (*installv1alpha1.VerrazzanoSpec, error) {
 	configMap, err := r.getInternalConfigMap(ctx, vz)
+	if err == nil && len(configMap.Data[configDataKey]) == 0 {
+		// The configmap can exist without the spec, for example when it was created to save the upgrade progress
+		err = errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, configMap.Name)
+	}
 	if err != nil {
 		log.Warnf("No saved configuration found for install spec for %s", vz.Name)
 		return nil, err
@@ -651,11 +820,12 @@ func buildDomainSuffix(c client.Client, vz *installv1alpha1.Verrazzano) (string,
 	return ipAddress + ".xip.io", nil
 }
 
+// The name and namespace of the NGINX ingress controller service
+const nginxIngressController = "ingress-controller-ingress-nginx-controller"
+const nginxNamespace = "ingress-nginx"
+
 // getIngressIP get the Ingress IP, used for the xip.io case
 func getIngressIP(c client.Client) (string, error) {
-	const nginxIngressController = "ingress-controller-ingress-nginx-controller"
-	const nginxNamespace = "ingress-nginx"
-
 	nginxService := corev1.Service{}
 	err := c.Get(context.TODO(), types.NamespacedName{Name: nginxIngressController, Namespace: nginxNamespace}, &nginxService)
 	if err != nil {
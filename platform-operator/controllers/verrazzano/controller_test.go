@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
@@ -288,8 +289,8 @@ func TestCreateVerrazzano(t *testing.T) {
 			return nil
 		})
 
-	// Expect a call to update the Verrazzano resource
-	mock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	// Expect a call to patch the finalizers of the Verrazzano resource
+	mock.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 
 	// Expect a call to get a stale uninstall job resource
 	mock.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: namespace, Name: buildUninstallJobName(name)}, gomock.Any()).Return(nil)
@@ -439,8 +440,8 @@ func TestCreateVerrazzanoWithOCIDNS(t *testing.T) {
 			return nil
 		})
 
-	// Expect a call to update the Verrazzano resource
-	mock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	// Expect a call to patch the finalizers of the Verrazzano resource
+	mock.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 
 	// Expect a call to get a stale uninstall job resource
 	mock.EXPECT().Get(gomock.Any(), types.NamespacedName{Namespace: namespace, Name: buildUninstallJobName(name)}, gomock.Any()).Return(nil)
@@ -638,8 +639,21 @@ func TestUninstallStarted(t *testing.T) {
 // TestUninstallFailed tests the Reconcile method for the following use case
 // GIVEN an uninstall job has failed
 // WHEN a verrazzano resource has been deleted
-// THEN ensure the error is handled
+// THEN ensure the error is handled and the finalizer is kept
 func TestUninstallFailed(t *testing.T) {
+	runUninstallFailedTest(t, false)
+}
+
+// TestUninstallFailedRemoveFinalizer tests the Reconcile method for the following use case
+// GIVEN an uninstall job has failed
+// WHEN a verrazzano resource that removes the finalizer on failure has been deleted
+// THEN ensure the error is handled and the finalizer is removed
+func TestUninstallFailedRemoveFinalizer(t *testing.T) {
+	runUninstallFailedTest(t, true)
+}
+
+// runUninstallFailedTest runs the Reconcile method for a deleted resource whose uninstall job has failed
+func runUninstallFailedTest(t *testing.T, removeFinalizerOnFailure bool) {
 	namespace := "verrazzano"
 	name := "test"
 	labels := map[string]string{"label1": "test"}
@@ -666,6 +680,7 @@ func TestUninstallFailed(t *testing.T) {
 				Name:              name.Name,
 				DeletionTimestamp: &deleteTime,
 				Finalizers:        []string{finalizerName}}
+			verrazzano.Spec.UninstallPolicy.RemoveFinalizerOnFailure = removeFinalizerOnFailure
 			return nil
 		})
 
@@ -695,8 +710,14 @@ func TestUninstallFailed(t *testing.T) {
 	// Expect a status update on the job
 	mockStatus.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 
-	// Expect a call to update the finalizers - return success
-	mock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	// Expect a call to update the finalizers if they are removed on failure - return success
+	if removeFinalizerOnFailure {
+		mock.EXPECT().Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, verrazzano *vzapi.Verrazzano, opts ...client.UpdateOption) error {
+				asserts.NotContains(verrazzano.Finalizers, finalizerName, "The finalizer should be removed")
+				return nil
+			})
+	}
 
 	// Expect a call to get the status writer and return a mock.
 	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
@@ -1841,8 +1862,9 @@ func newRequest(namespace string, name string) ctrl.Request {
 func newVerrazzanoReconciler(c client.Client) Reconciler {
 	scheme := newScheme()
 	reconciler := Reconciler{
-		Client: c,
-		Scheme: scheme}
+		Client:   c,
+		Scheme:   scheme,
+		Recorder: &record.FakeRecorder{}}
 	return reconciler
 }
 
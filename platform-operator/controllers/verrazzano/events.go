@@ -0,0 +1,32 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The reasons of the events that are recorded for the Verrazzano resource.  The events of the install, upgrade
+// and uninstall conditions use the condition type as the reason.
+const (
+	installJobCreatedReason      = "InstallJobCreated"
+	uninstallJobCreatedReason    = "UninstallJobCreated"
+	installProgressReason        = "InstallProgress"
+	installStepFailedReason      = "InstallStepFailed"
+	componentUpgradedReason      = "ComponentUpgraded"
+	componentUpgradeFailedReason = "ComponentUpgradeFailed"
+	validationFailedReason       = "ValidationFailed"
+)
+
+// conditionEventType returns the type of the event that is recorded when a condition is added, a warning for
+// the conditions of a failure and normal for all other conditions
+func conditionEventType(conditionType installv1alpha1.ConditionType) string {
+	switch conditionType {
+	case installv1alpha1.InstallFailed, installv1alpha1.UpgradeFailed, installv1alpha1.UninstallFailed,
+		installv1alpha1.UpgradeRolledBack, installv1alpha1.PreflightFailed:
+		return corev1.EventTypeWarning
+	}
+	return corev1.EventTypeNormal
+}
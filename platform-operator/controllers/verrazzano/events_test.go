@@ -0,0 +1,108 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestInstallEvents tests the events that are recorded for an install
+// GIVEN a request to reconcile a new verrazzano resource
+// WHEN the install of a component fails
+// THEN ensure that a normal event is recorded when the install starts and a warning event when it fails
+func TestInstallEvents(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config", ComponentInstallEnabled: true})
+	runner := &installRunner{installed: map[string]bool{}, failRelease: "cert-manager"}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), newInstallVerrazzano())
+	reconciler, events := newProgressReconciler(c)
+	_, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+
+	recorded := drainEvents(events)
+	if asserts.Len(recorded, 2, "Incorrect number of events") {
+		asserts.Equal("Normal InstallStarted Verrazzano install in progress", recorded[0])
+		asserts.Contains(recorded[1], "Warning InstallFailed Error installing component cert-manager")
+	}
+}
+
+// TestUpgradeEvents tests the events that are recorded for an upgrade
+// GIVEN a request to reconcile a verrazzano resource that needs to be upgraded
+// WHEN the upgrade of a component fails
+// THEN ensure that an event is recorded for each component that is upgraded and for the component that failed
+func TestUpgradeEvents(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+	runner := &upgradeRunner{upgraded: map[string]bool{}, failRelease: "keycloak"}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+	component.UpgradePrehooksEnabled = false
+	defer func() { component.UpgradePrehooksEnabled = true }()
+
+	vz := newUpgradeVerrazzano()
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz)
+	reconciler, events := newProgressReconciler(c)
+	_, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+
+	recorded := drainEvents(events)
+	asserts.Contains(recorded, "Normal ComponentUpgraded Upgraded component istiod")
+	asserts.Contains(recorded, "Warning ComponentUpgradeFailed Failed to upgrade component keycloak: helm upgrade for release keycloak in namespace keycloak failed: Helm Error: failure")
+	asserts.Contains(recorded[len(recorded)-1], "Warning UpgradeFailed Error upgrading component keycloak")
+}
+
+// TestValidationFailedEvent tests the event that is recorded when the resource is not valid
+// GIVEN a request to reconcile a verrazzano resource
+// WHEN the profile of the resource doesn't exist
+// THEN ensure that a warning event is recorded
+func TestValidationFailedEvent(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+
+	vz := newInstallVerrazzano()
+	vz.Spec.Profile = "missing"
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz)
+	reconciler, events := newProgressReconciler(c)
+	_, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.Error(err)
+	asserts.Equal([]string{"Warning ValidationFailed Profile missing not found"}, drainEvents(events))
+}
+
+// TestConditionEventType tests the conditionEventType function
+// GIVEN the condition types
+// WHEN conditionEventType is called
+// THEN warning is returned for the conditions of a failure, and normal for all other conditions
+func TestConditionEventType(t *testing.T) {
+	asserts := assert.New(t)
+	asserts.Equal(corev1.EventTypeNormal, conditionEventType(vzapi.InstallComplete))
+	asserts.Equal(corev1.EventTypeNormal, conditionEventType(vzapi.UpgradeStarted))
+	asserts.Equal(corev1.EventTypeWarning, conditionEventType(vzapi.UninstallFailed))
+	asserts.Equal(corev1.EventTypeWarning, conditionEventType(vzapi.UpgradeRolledBack))
+}
+
+// drainEvents returns the events that have been recorded
+func drainEvents(events chan string) []string {
+	var recorded []string
+	for len(events) > 0 {
+		recorded = append(recorded, <-events)
+	}
+	return recorded
+}
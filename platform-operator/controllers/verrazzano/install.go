@@ -0,0 +1,126 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"go.uber.org/zap"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// The time to wait before checking again if a component that was just installed is ready
+const componentNotReadyRequeueDelay = 10 * time.Second
+
+// reconcileComponentInstall installs the Verrazzano components from the operator, one component at a time,
+// in the order they are returned by the component registry.  Components that are already ready are skipped,
+// so the install resumes where it left off each time the resource is reconciled.
+func (r *Reconciler) reconcileComponentInstall(ctx context.Context, log *zap.SugaredLogger, cr *installv1alpha1.Verrazzano) (ctrl.Result, error) {
+	// An install that failed is only retried as allowed by the install policy, see retryInstall
+	if isLastCondition(cr.Status, installv1alpha1.InstallFailed) {
+		log.Info("Install failed, install will not be attempted")
+		return ctrl.Result{}, nil
+	}
+
+	// Add our finalizer if not already added
+	if err := r.addFinalizer(ctx, log, cr); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Only write the install started message once
+	if !hasCondition(cr.Status, installv1alpha1.InstallStarted) {
+		// Set the version in the status.  This will be updated when the starting install condition is updated.
+		chartSemVer, err := installv1alpha1.GetCurrentChartVersion()
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		cr.Status.Version = chartSemVer.ToString()
+		initComponentStatus(cr)
+		err = r.updateStatus(log, cr, "Verrazzano install in progress", installv1alpha1.InstallStarted)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Loop through all of the Verrazzano components and install each one sequentially
+	for _, comp := range component.GetComponents() {
+		if r.DryRun {
+			log.Info("Dry run enabled, skipping install")
+			break
+		}
+		if !installv1alpha1.IsComponentEnabled(&cr.Spec.Components, comp.Name()) {
+			log.Infof("Skipping install of component %s since it is disabled", comp.Name())
+			setComponentState(cr, comp.Name(), installv1alpha1.CompStateDisabled, nil)
+			continue
+		}
+		if comp.IsReady(log, r, cr.Namespace) {
+			setComponentReleaseStatus(log, cr, comp)
+			continue
+		}
+		// A ConfigMap or Secret with overrides that is missing can be created later, so the install is retried
+		overrides, err := getComponentOverrides(ctx, log, r, cr, comp.Name())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		// The install values can depend on the components that were installed before, for example the DNS suffix
+		// of a xip.io install is only known once the ingress controller has an address
+		installValues, err := getComponentInstallValues(ctx, log, r, cr, comp.Name())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		overrides = append(overrides, installValues...)
+		setComponentState(cr, comp.Name(), installv1alpha1.CompStateInstalling, nil)
+		if err := r.updateComponentStatus(log, cr); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := installComponent(log, r, cr.Namespace, comp, overrides); err != nil {
+			log.Errorf("Error installing component %s: %v", comp.Name(), err)
+			setComponentState(cr, comp.Name(), installv1alpha1.CompStateFailed, err)
+			msg := fmt.Sprintf("Error installing component %s - %s\".  Error is %s", comp.Name(),
+				fmtGeneration(cr.Generation), err.Error())
+			err := r.updateStatus(log, cr, msg, installv1alpha1.InstallFailed)
+			return ctrl.Result{}, err
+		}
+		// Wait for the component to be ready before installing the components that follow it
+		if !comp.IsReady(log, r, cr.Namespace) {
+			log.Infof("Component %s is not ready, requeuing", comp.Name())
+			return ctrl.Result{Requeue: true, RequeueAfter: componentNotReadyRequeueDelay}, nil
+		}
+		setComponentReleaseStatus(log, cr, comp)
+	}
+
+	// Create/update a configmap from spec for future comparison on update/upgrade
+	if err := r.saveVerrazzanoSpec(ctx, log, cr); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err := r.updateStatus(log, cr, "Verrazzano install completed successfully", installv1alpha1.InstallComplete)
+	return ctrl.Result{}, err
+}
+
+// installComponent runs the pre-install, install and post-install steps for a component
+func installComponent(log *zap.SugaredLogger, r *Reconciler, namespace string, comp component.Component, overrides []string) error {
+	log.Infof("Installing component %s", comp.Name())
+	if err := comp.PreInstall(log, r, namespace); err != nil {
+		return err
+	}
+	if err := comp.Install(log, r, namespace, overrides); err != nil {
+		return err
+	}
+	return comp.PostInstall(log, r, namespace)
+}
+
+// Return true if the status contains a condition of the specified type
+func hasCondition(st installv1alpha1.VerrazzanoStatus, conditionType installv1alpha1.ConditionType) bool {
+	for _, cond := range st.Conditions {
+		if cond.Type == conditionType {
+			return true
+		}
+	}
+	return false
+}
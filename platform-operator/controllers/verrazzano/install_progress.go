@@ -0,0 +1,160 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/installjob"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// The keys of the install progress in the progress configmap that is written by the install scripts
+const (
+	progressComponentKey = "component"
+	progressStepKey      = "step"
+	progressIndexKey     = "index"
+	progressTotalKey     = "total"
+	progressErrorKey     = "error"
+	progressTimeKey      = "time"
+)
+
+// buildProgressConfigMapName returns the name of the configmap that the install job writes the install progress to
+func buildProgressConfigMapName(name string) string {
+	return fmt.Sprintf("verrazzano-install-%s-progress", name)
+}
+
+// InstallProgressRequests maps an install progress configmap to a request to reconcile the Verrazzano resource
+// that is being installed, so that the progress is surfaced as soon as the install job reports it
+func InstallProgressRequests(o handler.MapObject) []reconcile.Request {
+	name, ok := o.Meta.GetLabels()[installjob.ProgressLabel]
+	if !ok || len(name) == 0 {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: o.Meta.GetNamespace(), Name: name}}}
+}
+
+// InstallProgressPredicate filters the configmap events to the events of the install progress configmaps, so that
+// the changes to the other configmaps in the cluster don't cause a reconcile
+var InstallProgressPredicate = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return isInstallProgress(e.Meta)
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return isInstallProgress(e.MetaNew)
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return isInstallProgress(e.Meta)
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return isInstallProgress(e.Meta)
+	},
+}
+
+// isInstallProgress returns true if the object is an install progress configmap
+func isInstallProgress(meta metav1.Object) bool {
+	if meta == nil {
+		return false
+	}
+	_, ok := meta.GetLabels()[installjob.ProgressLabel]
+	return ok
+}
+
+// getInstallProgress returns the install progress that the install job wrote to the progress configmap.  Nil is
+// returned if the job has not reported any progress yet.  Progress that was reported before the job was created is
+// left over from a previous install and is ignored.
+func (r *Reconciler) getInstallProgress(ctx context.Context, vz *installv1alpha1.Verrazzano, job *batchv1.Job) (*installv1alpha1.InstallProgress, error) {
+	configMap := corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: vz.Namespace, Name: buildProgressConfigMapName(vz.Name)}, &configMap)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	reported, err := time.Parse(time.RFC3339, configMap.Data[progressTimeKey])
+	if err != nil || reported.Before(job.CreationTimestamp.Time.Truncate(time.Second)) {
+		return nil, nil
+	}
+
+	progress := &installv1alpha1.InstallProgress{
+		Step:           configMap.Data[progressStepKey],
+		Component:      configMap.Data[progressComponentKey],
+		LastError:      configMap.Data[progressErrorKey],
+		LastUpdateTime: configMap.Data[progressTimeKey],
+	}
+	progress.Index, _ = strconv.Atoi(configMap.Data[progressIndexKey])
+	progress.Total, _ = strconv.Atoi(configMap.Data[progressTotalKey])
+	if progress.Total > 0 && progress.Index > 0 {
+		progress.Percentage = (progress.Index - 1) * 100 / progress.Total
+		progress.Message = fmt.Sprintf("installing %s (%d/%d)", progress.Component, progress.Index, progress.Total)
+	} else {
+		progress.Message = progress.Step
+	}
+	if len(progress.LastError) > 0 {
+		progress.Message = "failed " + progress.Message
+	}
+	return progress, nil
+}
+
+// updateInstallProgress sets the install progress that was reported by the install job in the status, and records
+// an event when the job moves on to a new install step or reports an error.  True is returned if the install
+// progress in the status changed.
+func (r *Reconciler) updateInstallProgress(ctx context.Context, log *zap.SugaredLogger, vz *installv1alpha1.Verrazzano, job *batchv1.Job) (bool, error) {
+	progress, err := r.getInstallProgress(ctx, vz, job)
+	if err != nil {
+		log.Errorf("Failed to get the install progress: %v", err)
+		return false, err
+	}
+	previous := vz.Status.InstallProgress
+	if progress == nil || (previous != nil && *previous == *progress) {
+		return false, nil
+	}
+
+	if previous == nil || previous.Step != progress.Step || previous.Component != progress.Component {
+		r.Recorder.Event(vz, corev1.EventTypeNormal, installProgressReason, installStepMessage(progress))
+	}
+	if len(progress.LastError) > 0 && (previous == nil || previous.LastError != progress.LastError) {
+		r.Recorder.Event(vz, corev1.EventTypeWarning, installStepFailedReason, fmt.Sprintf("%s failed: %s", progress.Step, progress.LastError))
+	}
+	log.Infof("Install progress: %s", progress.Message)
+	vz.Status.InstallProgress = progress
+	return true, nil
+}
+
+// installStepMessage returns the message of the event that is recorded when the install job moves on to a new
+// install step, which is the step and the position of the component in the install order
+func installStepMessage(progress *installv1alpha1.InstallProgress) string {
+	if progress.Total > 0 && progress.Index > 0 {
+		return fmt.Sprintf("%s (%d/%d)", progress.Step, progress.Index, progress.Total)
+	}
+	return progress.Step
+}
+
+// setInstallProgressComplete sets the install progress in the status to complete
+func setInstallProgressComplete(vz *installv1alpha1.Verrazzano) {
+	progress := installv1alpha1.InstallProgress{}
+	if vz.Status.InstallProgress != nil {
+		progress = *vz.Status.InstallProgress
+	}
+	progress.Index = progress.Total
+	progress.Percentage = 100
+	progress.Message = "install complete"
+	progress.LastError = ""
+	progress.LastUpdateTime = getTransitionTime()
+	vz.Status.InstallProgress = &progress
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/installjob"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+// TestInstallProgress tests the Reconcile method for the following use case
+// GIVEN a request to reconcile a verrazzano resource that is being installed by the install job
+// WHEN the install job reports its progress
+// THEN ensure that the progress is set in the status and an event is recorded for each install step
+func TestInstallProgress(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+
+	progress := newProgressConfigMap()
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), newInstallingVerrazzano(), newRunningInstallJob(), progress)
+	reconciler, events := newProgressReconciler(c)
+	_, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+
+	vz := getInstallVerrazzano(t, c)
+	if asserts.NotNil(vz.Status.InstallProgress, "The install progress was not set") {
+		asserts.Equal("installing keycloak (13/13)", vz.Status.InstallProgress.Message)
+		asserts.Equal("Installing Keycloak", vz.Status.InstallProgress.Step)
+		asserts.Equal(92, vz.Status.InstallProgress.Percentage)
+	}
+	asserts.Equal("Normal InstallProgress Installing Keycloak (13/13)", <-events)
+
+	// The same progress doesn't record another event
+	_, err = reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.Len(events, 0, "No event should be recorded")
+
+	// A failed install step records a warning event
+	progress.Data[progressErrorKey] = "timed out waiting for the condition"
+	asserts.NoError(c.Update(context.TODO(), progress))
+	_, err = reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	vz = getInstallVerrazzano(t, c)
+	asserts.Equal("failed installing keycloak (13/13)", vz.Status.InstallProgress.Message)
+	asserts.Equal("timed out waiting for the condition", vz.Status.InstallProgress.LastError)
+	asserts.Equal("Warning InstallStepFailed Installing Keycloak failed: timed out waiting for the condition", <-events)
+}
+
+// TestInstallProgressStale tests the Reconcile method for the following use case
+// GIVEN a request to reconcile a verrazzano resource that is being installed by the install job
+// WHEN the progress configmap was written before the install job was created
+// THEN ensure that the progress is ignored
+func TestInstallProgressStale(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+
+	progress := newProgressConfigMap()
+	progress.Data[progressTimeKey] = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), newInstallingVerrazzano(), newRunningInstallJob(), progress)
+	reconciler, events := newProgressReconciler(c)
+	_, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+
+	vz := getInstallVerrazzano(t, c)
+	asserts.Nil(vz.Status.InstallProgress, "The stale install progress should be ignored")
+	asserts.Len(events, 0, "No event should be recorded")
+}
+
+// TestInstallProgressComplete tests the Reconcile method for the following use case
+// GIVEN a request to reconcile a verrazzano resource that is being installed by the install job
+// WHEN the install job completes
+// THEN ensure that the install progress is complete
+func TestInstallProgressComplete(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+
+	vz := newInstallingVerrazzano()
+	vz.Spec.Components.DNS.External.Suffix = "mydomain.com"
+	vz.Status.InstallProgress = &vzapi.InstallProgress{Message: "installing keycloak (13/13)", Component: "keycloak", Index: 13, Total: 13, Percentage: 92}
+	job := newRunningInstallJob()
+	job.Status.Succeeded = 1
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, job)
+	reconciler, _ := newProgressReconciler(c)
+	_, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+
+	vz = getInstallVerrazzano(t, c)
+	asserts.Equal(vzapi.InstallComplete, vz.Status.Conditions[len(vz.Status.Conditions)-1].Type, "Incorrect condition")
+	asserts.Equal("install complete", vz.Status.InstallProgress.Message)
+	asserts.Equal(100, vz.Status.InstallProgress.Percentage)
+}
+
+// TestInstallProgressRequests tests the mapping of the install progress configmaps to reconcile requests
+// GIVEN a configmap
+// WHEN InstallProgressRequests is called
+// THEN a request for the installed verrazzano resource is returned for a progress configmap, and no requests
+//      are returned for other configmaps
+func TestInstallProgressRequests(t *testing.T) {
+	progress := newProgressConfigMap()
+	requests := InstallProgressRequests(handler.MapObject{Meta: progress, Object: progress})
+	assert.Equal(t, []types.NamespacedName{{Namespace: "verrazzano", Name: "test"}}, []types.NamespacedName{requests[0].NamespacedName})
+
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "other"}}
+	assert.Empty(t, InstallProgressRequests(handler.MapObject{Meta: other, Object: other}))
+}
+
+// TestInstallProgressPredicate tests the filtering of the configmap events
+// GIVEN a configmap event
+// WHEN the event is filtered by InstallProgressPredicate
+// THEN the events of the progress configmaps are accepted, and the events of other configmaps are not
+func TestInstallProgressPredicate(t *testing.T) {
+	progress := newProgressConfigMap()
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "verrazzano", Name: "other"}}
+
+	assert.True(t, InstallProgressPredicate.Create(event.CreateEvent{Meta: progress, Object: progress}))
+	assert.True(t, InstallProgressPredicate.Update(event.UpdateEvent{MetaOld: progress, ObjectOld: progress, MetaNew: progress, ObjectNew: progress}))
+	assert.False(t, InstallProgressPredicate.Create(event.CreateEvent{Meta: other, Object: other}))
+	assert.False(t, InstallProgressPredicate.Update(event.UpdateEvent{MetaOld: other, ObjectOld: other, MetaNew: other, ObjectNew: other}))
+}
+
+// newProgressReconciler creates a reconciler that records the events in the returned channel
+func newProgressReconciler(c client.Client) (Reconciler, chan string) {
+	events := make(chan string, 100)
+	reconciler := newVerrazzanoReconciler(c)
+	reconciler.Recorder = &record.FakeRecorder{Events: events}
+	return reconciler, events
+}
+
+// newInstallingVerrazzano creates a Verrazzano resource that is being installed by the install job
+func newInstallingVerrazzano() *vzapi.Verrazzano {
+	vz := newInstallVerrazzano()
+	vz.Finalizers = []string{finalizerName}
+	vz.Status.Conditions = []vzapi.Condition{{Type: vzapi.InstallStarted, Status: corev1.ConditionTrue}}
+	vz.Status.State = vzapi.Installing
+	return vz
+}
+
+// newRunningInstallJob creates an install job that was created a minute ago and is running
+func newRunningInstallJob() *batchv1.Job {
+	job := installjob.NewJob(&installjob.JobConfig{})
+	job.Namespace = "verrazzano"
+	job.Name = buildInstallJobName("test")
+	job.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+	job.Status.Active = 1
+	return job
+}
+
+// newProgressConfigMap creates the configmap that the install job writes the install progress to, with the
+// progress of the last install step
+func newProgressConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "verrazzano",
+			Name:      buildProgressConfigMapName("test"),
+			Labels:    map[string]string{installjob.ProgressLabel: "test"},
+		},
+		Data: map[string]string{
+			progressComponentKey: "keycloak",
+			progressStepKey:      "Installing Keycloak",
+			progressIndexKey:     "13",
+			progressTotalKey:     "13",
+			progressErrorKey:     "",
+			progressTimeKey:      time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+}
+
+// getInstallVerrazzano gets the Verrazzano resource that is installed
+func getInstallVerrazzano(t *testing.T, c client.Client) *vzapi.Verrazzano {
+	vz := &vzapi.Verrazzano{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, vz))
+	return vz
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The time to wait before the first retry of a failed install when the install policy doesn't specify a backoff
+const defaultInstallRetryBackoff = 30 * time.Second
+
+// The longest time to wait before retrying a failed install
+const maxInstallRetryBackoff = time.Hour
+
+// The time to wait for a failed install job to be deleted before it is recreated
+const installJobDeleteRequeueDelay = 5 * time.Second
+
+// The time to wait before a failed uninstall job is retried
+const uninstallRetryDelay = 30 * time.Second
+
+// retryInstall starts a retry of a failed install when the install policy allows it and the backoff has elapsed.
+// A failed install job is deleted first, so that the install job is recreated.  True is returned if the install
+// should continue, otherwise the result of the reconcile is returned.
+func (r *Reconciler) retryInstall(ctx context.Context, log *zap.SugaredLogger, vz *installv1alpha1.Verrazzano) (ctrl.Result, bool, error) {
+	delay, allowed := installRetryDelay(vz)
+	if !allowed {
+		log.Info("Install failed, install will not be attempted")
+		return ctrl.Result{}, false, nil
+	}
+	if delay > 0 {
+		log.Infof("Install failed, install will be retried in %v", delay)
+		return ctrl.Result{Requeue: true, RequeueAfter: delay}, false, nil
+	}
+
+	if !config.Get().ComponentInstallEnabled {
+		job := &batchv1.Job{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: vz.Namespace, Name: buildInstallJobName(vz.Name)}, job)
+		if err == nil {
+			log.Infof("Deleting failed install job %s", job.Name)
+			propagationPolicy := metav1.DeletePropagationBackground
+			err = r.Delete(ctx, job, &client.DeleteOptions{PropagationPolicy: &propagationPolicy})
+			if err != nil && !errors.IsNotFound(err) {
+				return ctrl.Result{}, false, err
+			}
+			return ctrl.Result{Requeue: true, RequeueAfter: installJobDeleteRequeueDelay}, false, nil
+		}
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, false, err
+		}
+	}
+
+	// The progress of the failed install is replaced by the progress of the retry
+	vz.Status.InstallProgress = nil
+	attempt := installFailureCount(vz.Status, vz.Generation, false)
+	err := r.updateStatus(log, vz, fmt.Sprintf("Verrazzano install retry %d in progress", attempt), installv1alpha1.InstallStarted)
+	if err != nil {
+		return ctrl.Result{}, false, err
+	}
+	return ctrl.Result{}, true, nil
+}
+
+// retryUninstall starts a retry of a failed uninstall job once the retry delay has elapsed.  The failed uninstall
+// job is deleted first, so that the uninstall job is recreated.
+func (r *Reconciler) retryUninstall(ctx context.Context, log *zap.SugaredLogger, vz *installv1alpha1.Verrazzano) (ctrl.Result, error) {
+	failed, err := time.Parse(time.RFC3339, vz.Status.Conditions[len(vz.Status.Conditions)-1].LastTransitionTime)
+	if err == nil {
+		if delay := time.Until(failed.Add(uninstallRetryDelay)); delay > 0 {
+			log.Infof("Uninstall failed, uninstall will be retried in %v", delay)
+			return ctrl.Result{Requeue: true, RequeueAfter: delay}, nil
+		}
+	}
+
+	job := &batchv1.Job{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: vz.Namespace, Name: buildUninstallJobName(vz.Name)}, job)
+	if err == nil {
+		log.Infof("Deleting failed uninstall job %s", job.Name)
+		propagationPolicy := metav1.DeletePropagationBackground
+		err = r.Delete(ctx, job, &client.DeleteOptions{PropagationPolicy: &propagationPolicy})
+		if err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true, RequeueAfter: installJobDeleteRequeueDelay}, nil
+	}
+	if !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	err = r.updateStatus(log, vz, "Verrazzano uninstall retry in progress", installv1alpha1.UninstallStarted)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, r.createUninstallJob(log, vz)
+}
+
+// installRetryResult returns the result of a reconcile that requeues a failed install when the install policy
+// allows it to be retried, otherwise the result is returned unchanged
+func installRetryResult(log *zap.SugaredLogger, vz *installv1alpha1.Verrazzano, result ctrl.Result) ctrl.Result {
+	if !isLastCondition(vz.Status, installv1alpha1.InstallFailed) {
+		return result
+	}
+	delay, allowed := installRetryDelay(vz)
+	if !allowed {
+		log.Info("Install failure limit reached, install will not be retried")
+		return result
+	}
+	log.Infof("Install failed, install will be retried in %v", delay)
+	return ctrl.Result{Requeue: true, RequeueAfter: delay}
+}
+
+// installRetryDelay returns the time left to wait before a failed install is retried, and false if the install
+// policy doesn't allow the install to be retried.  The backoff doubles with each failure.
+func installRetryDelay(vz *installv1alpha1.Verrazzano) (time.Duration, bool) {
+	policy := vz.Spec.InstallPolicy
+	failures := installFailureCount(vz.Status, vz.Generation, policy.RetryOnGenerationChange)
+	if failures > policy.MaxRetries {
+		return 0, false
+	}
+	// The spec changed since the last failure
+	if failures == 0 {
+		return 0, true
+	}
+
+	backoff := defaultInstallRetryBackoff
+	if policy.Backoff != nil && policy.Backoff.Duration > 0 {
+		backoff = policy.Backoff.Duration
+	}
+	for i := 1; i < failures && backoff < maxInstallRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxInstallRetryBackoff {
+		backoff = maxInstallRetryBackoff
+	}
+
+	failed, err := time.Parse(time.RFC3339, vz.Status.Conditions[len(vz.Status.Conditions)-1].LastTransitionTime)
+	if err != nil {
+		return 0, true
+	}
+	delay := time.Until(failed.Add(backoff))
+	if delay < 0 {
+		return 0, true
+	}
+	return delay, true
+}
+
+// Get the number of times an install failed.  When currentGeneration is true, only the failures of the specified
+// generation are counted, meaning the last time the CR spec was modified by the user.
+func installFailureCount(st installv1alpha1.VerrazzanoStatus, generation int64, currentGeneration bool) int {
+	var c int
+	for _, cond := range st.Conditions {
+		if cond.Type != installv1alpha1.InstallFailed {
+			continue
+		}
+		if currentGeneration && !strings.Contains(cond.Message, fmtGeneration(generation)) {
+			continue
+		}
+		c++
+	}
+	return c
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestComponentInstallRetry tests the Reconcile method for the following use case
+// GIVEN a request to reconcile a new verrazzano resource with an install policy that allows one retry
+// WHEN the install of a component fails
+// THEN ensure that the install is retried from the failed component, and not retried after the second failure
+func TestComponentInstallRetry(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config", ComponentInstallEnabled: true})
+	runner := &installRunner{installed: map[string]bool{}, failRelease: "cert-manager"}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	vz := newInstallVerrazzano()
+	vz.Spec.InstallPolicy = vzapi.InstallPolicy{MaxRetries: 1, Backoff: &metav1.Duration{Duration: time.Millisecond}}
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz)
+	reconciler := newVerrazzanoReconciler(c)
+	result, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.True(result.Requeue, "The failed install should be requeued for a retry")
+	asserts.False(runner.installed["cert-manager"], "cert-manager should not be installed")
+
+	// The retry fails again, the install is not retried after that
+	result, err = reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.False(result.Requeue, "The failure limit is reached")
+	vz = getInstallVerrazzano(t, c)
+	asserts.Equal([]vzapi.ConditionType{vzapi.InstallStarted, vzapi.InstallFailed, vzapi.InstallStarted, vzapi.InstallFailed},
+		conditionTypes(vz), "Incorrect conditions")
+	asserts.Equal("Verrazzano install retry 1 in progress", vz.Status.Conditions[2].Message)
+
+	runner.failRelease = ""
+	result, err = reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+	asserts.False(runner.installed["cert-manager"], "The install should not be retried")
+}
+
+// TestComponentInstallRetryOnGenerationChange tests the Reconcile method for the following use case
+// GIVEN a verrazzano resource with a failed install that reached the failure limit
+// WHEN the spec is changed and the install policy retries on a generation change
+// THEN ensure that the install is retried and completes
+func TestComponentInstallRetryOnGenerationChange(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config", ComponentInstallEnabled: true})
+	runner := &installRunner{installed: map[string]bool{}}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	vz := newFailedInstallVerrazzano(1)
+	vz.Generation = 2
+	vz.Spec.InstallPolicy.RetryOnGenerationChange = true
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, newIngressService(), newMySQLSecret())
+	reconciler := newVerrazzanoReconciler(c)
+	_, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.True(runner.installed["keycloak"], "The install was not retried")
+	vz = getInstallVerrazzano(t, c)
+	asserts.Equal(vzapi.InstallComplete, vz.Status.Conditions[len(vz.Status.Conditions)-1].Type, "Incorrect condition")
+}
+
+// TestInstallJobRetry tests the Reconcile method for the following use case
+// GIVEN a verrazzano resource with a failed install job and an install policy that allows a retry
+// WHEN the resource is reconciled
+// THEN ensure that the failed install job is deleted and then recreated
+func TestInstallJobRetry(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+
+	vz := newFailedInstallVerrazzano(1)
+	vz.Finalizers = []string{finalizerName}
+	vz.Spec.InstallPolicy = vzapi.InstallPolicy{MaxRetries: 1, Backoff: &metav1.Duration{Duration: time.Millisecond}}
+	job := newRunningInstallJob()
+	job.Status.Active = 0
+	job.Status.Failed = 1
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, job)
+	reconciler := newVerrazzanoReconciler(c)
+
+	result, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.True(result.Requeue, "The retry should wait for the failed job to be deleted")
+	asserts.False(exists(c, newRunningInstallJob()), "The failed install job was not deleted")
+
+	result, err = reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+	asserts.True(exists(c, newRunningInstallJob()), "The install job was not recreated")
+	vz = getInstallVerrazzano(t, c)
+	asserts.Equal(vzapi.InstallStarted, vz.Status.Conditions[len(vz.Status.Conditions)-1].Type, "Incorrect condition")
+	asserts.Equal(vzapi.Installing, vz.Status.State, "Incorrect state")
+}
+
+// TestInstallRetryDelay tests the installRetryDelay function
+// GIVEN a verrazzano resource with a failed install
+// WHEN installRetryDelay is called
+// THEN the backoff doubles with each failure, and a retry is not allowed after the failure limit is reached
+func TestInstallRetryDelay(t *testing.T) {
+	asserts := assert.New(t)
+
+	vz := newFailedInstallVerrazzano(2)
+	vz.Spec.InstallPolicy = vzapi.InstallPolicy{MaxRetries: 2, Backoff: &metav1.Duration{Duration: time.Minute}}
+	delay, allowed := installRetryDelay(vz)
+	asserts.True(allowed, "The retry should be allowed")
+	asserts.True(delay > time.Minute && delay <= 2*time.Minute, "Incorrect delay %v", delay)
+
+	vz.Spec.InstallPolicy.MaxRetries = 1
+	_, allowed = installRetryDelay(vz)
+	asserts.False(allowed, "The retry should not be allowed")
+
+	vz.Generation = 2
+	vz.Spec.InstallPolicy.RetryOnGenerationChange = true
+	delay, allowed = installRetryDelay(vz)
+	asserts.True(allowed, "The retry should be allowed after a generation change")
+	asserts.Equal(time.Duration(0), delay)
+
+	vz = newFailedInstallVerrazzano(1)
+	vz.Spec.InstallPolicy.MaxRetries = 1
+	vz.Status.Conditions[1].LastTransitionTime = "2021-01-01T00:00:00Z"
+	delay, allowed = installRetryDelay(vz)
+	asserts.True(allowed, "The retry should be allowed")
+	asserts.Equal(time.Duration(0), delay, "The default backoff has elapsed")
+}
+
+// newFailedInstallVerrazzano creates a Verrazzano resource of generation 1 whose install failed the given number
+// of times
+func newFailedInstallVerrazzano(failures int) *vzapi.Verrazzano {
+	vz := newInstallVerrazzano()
+	vz.Generation = 1
+	for i := 0; i < failures; i++ {
+		vz.Status.Conditions = append(vz.Status.Conditions,
+			vzapi.Condition{Type: vzapi.InstallStarted, Status: corev1.ConditionTrue, LastTransitionTime: getTransitionTime()},
+			vzapi.Condition{Type: vzapi.InstallFailed, Status: corev1.ConditionTrue, LastTransitionTime: getTransitionTime(),
+				Message: "Verrazzano install failed to complete - " + fmtGeneration(1)})
+	}
+	vz.Status.State = vzapi.Failed
+	return vz
+}
+
+// conditionTypes returns the types of the conditions of the resource
+func conditionTypes(vz *vzapi.Verrazzano) []vzapi.ConditionType {
+	var types []vzapi.ConditionType
+	for _, condition := range vz.Status.Conditions {
+		types = append(types, condition.Type)
+	}
+	return types
+}
@@ -0,0 +1,334 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// installRunner is used to test the component install and uninstall without running the helm command.  The
+// runner keeps track of the releases that are installed so that the helm status command reflects the installs.
+type installRunner struct {
+	installed   map[string]bool
+	failRelease string
+}
+
+// statusClient is used to test the status updates of the Verrazzano resource.  The fake client saves the whole
+// object on a status update, while the API server only saves the status and returns the stored spec.
+type statusClient struct {
+	client.Client
+}
+
+// statusWriter saves the status of the Verrazzano resource and decodes the stored resource into the object
+type statusWriter struct {
+	client.Client
+}
+
+
+// TestComponentInstall tests the reconcileComponentInstall method for the following use case
+// GIVEN a request to reconcile a new verrazzano resource
+// WHEN the component install is enabled
+// THEN ensure that every component is installed and the InstallComplete condition is added
+func TestComponentInstall(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config", ComponentInstallEnabled: true})
+	runner := &installRunner{installed: map[string]bool{}}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), newInstallVerrazzano(), newIngressService(), newMySQLSecret())
+	reconciler := newVerrazzanoReconciler(c)
+	result, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+
+	asserts.Len(runner.installed, 18, "Incorrect number of components installed")
+	asserts.True(runner.installed["istio-base"], "istio-base was not installed")
+	asserts.True(runner.installed["verrazzano"], "verrazzano was not installed")
+	asserts.True(runner.installed["keycloak"], "keycloak was not installed")
+
+	vz := vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, &vz))
+	asserts.Contains(vz.Finalizers, finalizerName, "Finalizer was not added")
+	asserts.Len(vz.Status.Conditions, 2, "Incorrect number of conditions")
+	asserts.Equal(vzapi.InstallStarted, vz.Status.Conditions[0].Type, "Incorrect condition")
+	asserts.Equal(vzapi.InstallComplete, vz.Status.Conditions[1].Type, "Incorrect condition")
+	asserts.Equal(vzapi.Ready, vz.Status.State, "Incorrect state")
+	asserts.NotEmpty(vz.Status.Version, "Version was not set")
+	asserts.Len(vz.Status.Components, 18, "Incorrect number of component states")
+	asserts.Equal(vzapi.CompStateReady, vz.Status.Components["keycloak"].State, "Incorrect component state")
+	asserts.Equal(2, vz.Status.Components["keycloak"].HelmRevision, "Incorrect helm revision")
+	asserts.Equal("1.0.0", vz.Status.Components["keycloak"].ChartVersion, "Incorrect chart version")
+	asserts.NotEmpty(vz.Status.Components["keycloak"].LastTransitionTime, "Transition time was not set")
+
+	ns := corev1.Namespace{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Name: "istio-system"}, &ns), "Namespace was not created")
+	cm := corev1.ConfigMap{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: buildInternalConfigMapName("test")}, &cm),
+		"Internal ConfigMap was not created")
+}
+
+// TestComponentInstallFailed tests the reconcileComponentInstall method for the following use case
+// GIVEN a request to reconcile a new verrazzano resource
+// WHEN the install of a component fails
+// THEN ensure that the components after the failed component are not installed and the InstallFailed condition is added
+func TestComponentInstallFailed(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config", ComponentInstallEnabled: true})
+	runner := &installRunner{installed: map[string]bool{}, failRelease: "cert-manager"}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), newInstallVerrazzano())
+	reconciler := newVerrazzanoReconciler(c)
+	result, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+
+	asserts.True(runner.installed["ingress-controller"], "ingress-controller was not installed")
+	asserts.False(runner.installed["cert-manager"], "cert-manager should not be installed")
+	asserts.False(runner.installed["keycloak"], "keycloak should not be installed")
+
+	vz := vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, &vz))
+	asserts.Len(vz.Status.Conditions, 2, "Incorrect number of conditions")
+	asserts.Equal(vzapi.InstallFailed, vz.Status.Conditions[1].Type, "Incorrect condition")
+	asserts.Contains(vz.Status.Conditions[1].Message, "cert-manager", "Condition message should contain the component name")
+	asserts.Equal(vzapi.Failed, vz.Status.State, "Incorrect state")
+	asserts.Equal(vzapi.CompStateReady, vz.Status.Components["ingress-controller"].State, "Incorrect component state")
+	asserts.Equal(vzapi.CompStateFailed, vz.Status.Components["cert-manager"].State, "Incorrect component state")
+	asserts.Equal("helm install for release cert-manager in namespace cert-manager failed: Helm Error: failure", vz.Status.Components["cert-manager"].LastError, "Incorrect component error")
+	asserts.Equal(vzapi.CompStateNotInstalled, vz.Status.Components["keycloak"].State, "Incorrect component state")
+
+	// A failed install is not retried
+	result, err = reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+	asserts.False(runner.installed["cert-manager"], "cert-manager should not be installed")
+}
+
+// TestComponentInstallDisabled tests the reconcileComponentInstall method for the following use case
+// GIVEN a request to reconcile a new verrazzano resource
+// WHEN optional components are disabled
+// THEN ensure that the disabled components are skipped and the install completes
+func TestComponentInstallDisabled(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config", ComponentInstallEnabled: true})
+	runner := &installRunner{installed: map[string]bool{}}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	disabled := false
+	vz := newInstallVerrazzano()
+	vz.Spec.Components.Rancher.Enabled = &disabled
+	vz.Spec.Components.Istio.EgressGateway.Enabled = &disabled
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, newIngressService(), newMySQLSecret())
+	reconciler := newVerrazzanoReconciler(c)
+	result, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+
+	asserts.Len(runner.installed, 16, "Incorrect number of components installed")
+	asserts.False(runner.installed["rancher"], "rancher should not be installed")
+	asserts.False(runner.installed["istio-egress"], "istio-egress should not be installed")
+
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, vz))
+	asserts.Equal(vzapi.Ready, vz.Status.State, "Incorrect state")
+	asserts.Equal(vzapi.CompStateDisabled, vz.Status.Components["rancher"].State, "Incorrect component state")
+	asserts.Equal(vzapi.CompStateReady, vz.Status.Components["keycloak"].State, "Incorrect component state")
+}
+
+// TestComponentInstallProfile tests the reconcileComponentInstall method for the following use case
+// GIVEN a request to reconcile a new verrazzano resource
+// WHEN the resource uses a custom profile that disables components
+// THEN ensure that the components that the profile disables are skipped, unless the spec enables them
+func TestComponentInstallProfile(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config", ComponentInstallEnabled: true})
+	runner := &installRunner{installed: map[string]bool{}}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	enabled := true
+	vz := newInstallVerrazzano()
+	vz.Spec.Profile = "minimal"
+	vz.Spec.Components.Prometheus.Enabled = &enabled
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, newIngressService(), newMySQLSecret())
+	reconciler := newVerrazzanoReconciler(c)
+	result, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+
+	asserts.Len(runner.installed, 12, "Incorrect number of components installed")
+	asserts.False(runner.installed["rancher"], "rancher should not be installed")
+	asserts.False(runner.installed["keycloak"], "keycloak should not be installed")
+	asserts.False(runner.installed["grafana"], "grafana should not be installed")
+	asserts.True(runner.installed["prometheus"], "prometheus was not installed")
+
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, vz))
+	asserts.Equal(vzapi.Ready, vz.Status.State, "Incorrect state")
+	asserts.Contains(vz.Finalizers, finalizerName, "Finalizer was not added")
+	asserts.Equal(vzapi.CompStateDisabled, vz.Status.Components["keycloak"].State, "Incorrect component state")
+}
+
+// TestComponentInstallProfileStatusUpdate tests the reconcileComponentInstall method for the following use case
+// GIVEN a request to reconcile a new verrazzano resource that uses a profile that disables components
+// WHEN the status update returns the stored spec of the resource
+// THEN ensure that the components that the profile disables are still skipped and the effective spec is saved
+func TestComponentInstallProfileStatusUpdate(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config", ComponentInstallEnabled: true})
+	runner := &installRunner{installed: map[string]bool{}}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	vz := newInstallVerrazzano()
+	vz.Spec.Profile = "minimal"
+	c := statusClient{fake.NewFakeClientWithScheme(newInstallScheme(), vz, newIngressService(), newMySQLSecret())}
+	reconciler := newVerrazzanoReconciler(c)
+	result, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+
+	asserts.False(runner.installed["rancher"], "rancher should not be installed")
+	asserts.False(runner.installed["keycloak"], "keycloak should not be installed")
+	asserts.False(runner.installed["grafana"], "grafana should not be installed")
+	asserts.False(runner.installed["prometheus"], "prometheus should not be installed")
+
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, vz))
+	asserts.Equal(vzapi.Ready, vz.Status.State, "Incorrect state")
+	asserts.Nil(vz.Spec.Components.Keycloak.Enabled, "The effective spec should not be saved in the resource")
+	savedSpec, err := reconciler.getSavedInstallSpec(context.TODO(), zap.S(), vz)
+	asserts.NoError(err)
+	asserts.False(vzapi.IsEnabled(savedSpec.Components.Keycloak.Enabled), "The effective spec was not saved")
+}
+
+// TestReconcileProfileNotFound tests the Reconcile method for the following use case
+// GIVEN a request to reconcile a new verrazzano resource
+// WHEN the profile of the resource doesn't exist
+// THEN ensure that an error is returned and nothing is installed
+func TestReconcileProfileNotFound(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config", ComponentInstallEnabled: true})
+	runner := &installRunner{installed: map[string]bool{}}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	vz := newInstallVerrazzano()
+	vz.Spec.Profile = "missing"
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, newIngressService(), newMySQLSecret())
+	reconciler := newVerrazzanoReconciler(c)
+	_, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.EqualError(err, "Profile missing not found")
+	asserts.Empty(runner.installed, "No components should be installed")
+}
+
+// newInstallScheme creates a scheme with the Verrazzano and Kubernetes core types
+func newInstallScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = vzapi.AddToScheme(scheme)
+	return scheme
+}
+
+// newInstallVerrazzano creates a Verrazzano resource that has not been installed
+func newInstallVerrazzano() *vzapi.Verrazzano {
+	return &vzapi.Verrazzano{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "install.verrazzano.io/v1alpha1",
+			Kind:       "Verrazzano"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "verrazzano",
+			Name:      "test"},
+	}
+}
+
+// newMySQLSecret creates the secret with the MySQL password that is created by the install of MySQL
+func newMySQLSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: keycloakNamespace,
+			Name:      "mysql"},
+		Data: map[string][]byte{"mysql-password": []byte("password")},
+	}
+}
+
+// Run tracks the installed releases and returns the helm status of the installed releases
+func (r *installRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	release := cmd.Args[2]
+	switch cmd.Args[1] {
+	case "upgrade":
+		if release == r.failRelease {
+			return []byte(""), []byte("failure"), errors.New("Helm Error")
+		}
+		r.installed[release] = true
+	case "uninstall":
+		if release == r.failRelease {
+			return []byte(""), []byte("failure"), errors.New("Helm Error")
+		}
+		delete(r.installed, release)
+	case "status":
+		if !r.installed[release] {
+			return []byte(""), []byte("Error: release: not found"), errors.New("not found error")
+		}
+		return []byte(`{"version":2,"info":{"status":"deployed"},"chart":{"metadata":{"version":"1.0.0"}}}`), []byte(""), nil
+	}
+	return []byte("success"), []byte(""), nil
+}
+
+// Status returns a writer that only saves the status of the Verrazzano resource
+func (c statusClient) Status() client.StatusWriter {
+	return statusWriter{c.Client}
+}
+
+// Update saves the status of the Verrazzano resource and decodes the stored resource into the object
+func (w statusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	vz, ok := obj.(*vzapi.Verrazzano)
+	if !ok {
+		return w.Client.Status().Update(ctx, obj, opts...)
+	}
+	stored := vzapi.Verrazzano{}
+	if err := w.Get(ctx, types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, &stored); err != nil {
+		return err
+	}
+	stored.Status = vz.Status
+	if err := w.Client.Status().Update(ctx, &stored, opts...); err != nil {
+		return err
+	}
+	stored.DeepCopyInto(vz)
+	return nil
+}
+
+// Patch patches the status of the object
+func (w statusWriter) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return w.Client.Status().Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"fmt"
+
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/installjob"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The optional image pull secret in the default namespace that is used to pull the images of the components
+const globalImagePullSecret = "verrazzano-container-registry"
+
+// The names used by the install scripts for the Keycloak admin user and the MySQL user of Keycloak
+const (
+	keycloakAdminUser = "keycloakadmin"
+	mysqlUser         = "keycloak"
+	keycloakNamespace = "keycloak"
+)
+
+// createKeycloakDatabase is the MySQL initialization file that creates the Keycloak database
+const createKeycloakDatabase = "CREATE DATABASE IF NOT EXISTS keycloak DEFAULT CHARACTER SET utf8 DEFAULT COLLATE utf8_general_ci;\n" +
+	"USE keycloak;\n" +
+	"GRANT ALL ON keycloak.* TO '" + mysqlUser + "'@'%';\n" +
+	"FLUSH PRIVILEGES;\n"
+
+// installArgsFunc returns the helm set arguments of a component that are computed from the install configuration
+// of the Verrazzano resource and from the cluster
+type installArgsFunc func(ctx context.Context, c client.Client, cr *installv1alpha1.Verrazzano, cfg *installjob.InstallConfiguration) ([]helm.SetArg, error)
+
+// componentInstallArgs maps the name of a registered component to the function that returns the set arguments
+// that the install scripts pass to the helm install of the component
+var componentInstallArgs = map[string]installArgsFunc{
+	"istiod":                          imagePullSecretArgs("global.imagePullSecrets[0]"),
+	"istio-ingress":                   istioIngressArgs,
+	"istio-egress":                    imagePullSecretArgs("global.imagePullSecrets[0]"),
+	"istiocoredns":                    imagePullSecretArgs("global.imagePullSecrets[0]"),
+	"grafana":                         imagePullSecretArgs("global.imagePullSecrets[0]"),
+	"prometheus":                      imagePullSecretArgs("global.imagePullSecrets[0]"),
+	"ingress-controller":              nginxArgs,
+	"cert-manager":                    certManagerArgs,
+	"external-dns":                    externalDNSArgs,
+	"rancher":                         rancherArgs,
+	"verrazzano":                      verrazzanoArgs,
+	"verrazzano-application-operator": applicationOperatorArgs,
+	"weblogic-operator":               weblogicOperatorArgs,
+	"mysql":                           mysqlArgs,
+	"keycloak":                        keycloakArgs,
+}
+
+// getComponentInstallValues returns the YAML helm values that are computed from the Verrazzano resource for the
+// install of a component, the same as the --set arguments that the install scripts pass to helm.  The values
+// are applied after the overrides, the same as the --set arguments of the scripts.
+func getComponentInstallValues(ctx context.Context, log *zap.SugaredLogger, c client.Client, cr *installv1alpha1.Verrazzano, compName string) ([]string, error) {
+	argsFunc, ok := componentInstallArgs[compName]
+	if !ok {
+		return nil, nil
+	}
+	cfg, err := installjob.GetInstallConfig(cr, log)
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	args, err := argsFunc(ctx, c, cr, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting the install values of component %s: %v", compName, err)
+	}
+	if len(args) > 0 {
+		argsValues, err := helm.SetArgsValues(args)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, argsValues)
+	}
+	return values, nil
+}
+
+// imagePullSecretArgs returns a function that returns the set argument with the name of the global image pull
+// secret, if the secret exists
+func imagePullSecretArgs(name string) installArgsFunc {
+	return func(ctx context.Context, c client.Client, _ *installv1alpha1.Verrazzano, _ *installjob.InstallConfiguration) ([]helm.SetArg, error) {
+		exists, err := registrySecretExists(ctx, c)
+		if err != nil || !exists {
+			return nil, err
+		}
+		return []helm.SetArg{{Name: name, Value: globalImagePullSecret}}, nil
+	}
+}
+
+// istioIngressArgs returns the set arguments of the istio ingress gateway
+func istioIngressArgs(ctx context.Context, c client.Client, cr *installv1alpha1.Verrazzano, cfg *installjob.InstallConfiguration) ([]helm.SetArg, error) {
+	args := getConfigSetArgs(cfg.Ingress.Application.IstioInstallArgs)
+	args = append(args, helm.SetArg{Name: "gateways.istio-ingressgateway.type", Value: string(cfg.Ingress.Type)})
+	pullSecretArgs, err := imagePullSecretArgs("global.imagePullSecrets[0]")(ctx, c, cr, cfg)
+	return append(args, pullSecretArgs...), err
+}
+
+// nginxArgs returns the set arguments of the NGINX ingress controller.  The external DNS annotations are only
+// set for OCI DNS.
+func nginxArgs(_ context.Context, _ client.Client, _ *installv1alpha1.Verrazzano, cfg *installjob.InstallConfiguration) ([]helm.SetArg, error) {
+	args := getConfigSetArgs(cfg.Ingress.Verrazzano.NginxInstallArgs)
+	if cfg.DNS.Type == installjob.DNSTypeOci {
+		args = append(args,
+			helm.SetArg{Name: `controller.service.annotations.external-dns\.alpha\.kubernetes\.io/ttl`, Value: "60", SetString: true},
+			helm.SetArg{Name: `controller.service.annotations.external-dns\.alpha\.kubernetes\.io/hostname`,
+				Value: fmt.Sprintf("verrazzano-ingress.%s.%s", cfg.EnvironmentName, cfg.DNS.Oci.DNSZoneName)})
+	}
+	return append(args, helm.SetArg{Name: "controller.service.type", Value: string(cfg.Ingress.Type)}), nil
+}
+
+// certManagerArgs returns the set arguments of cert-manager, the cluster resource namespace of a CA issuer
+func certManagerArgs(_ context.Context, _ client.Client, _ *installv1alpha1.Verrazzano, cfg *installjob.InstallConfiguration) ([]helm.SetArg, error) {
+	if cfg.Certificates.IssuerType != installjob.CertIssuerTypeCA {
+		return nil, nil
+	}
+	return []helm.SetArg{{Name: "clusterResourceNamespace", Value: cfg.Certificates.CA.ClusterResourceNamespace}}, nil
+}
+
+// externalDNSArgs returns the set arguments of external DNS, which is configured for the OCI DNS zone
+func externalDNSArgs(_ context.Context, _ client.Client, _ *installv1alpha1.Verrazzano, cfg *installjob.InstallConfiguration) ([]helm.SetArg, error) {
+	if cfg.DNS.Type != installjob.DNSTypeOci {
+		return nil, nil
+	}
+	return []helm.SetArg{
+		{Name: "domainFilters[0]", Value: cfg.DNS.Oci.DNSZoneName},
+		{Name: "zoneIdFilters[0]", Value: cfg.DNS.Oci.DNSZoneOcid},
+		{Name: "txtOwnerId", Value: "v8o-local-" + cfg.EnvironmentName},
+		{Name: "txtPrefix", Value: "_v8o-local-" + cfg.EnvironmentName + "_"},
+		{Name: "extraVolumes[0].name", Value: "config"},
+		{Name: "extraVolumes[0].secret.secretName", Value: cfg.DNS.Oci.OCIConfigSecret},
+		{Name: "extraVolumeMounts[0].name", Value: "config"},
+		{Name: "extraVolumeMounts[0].mountPath", Value: "/etc/kubernetes/"},
+	}, nil
+}
+
+// rancherArgs returns the set arguments of Rancher, the host name and the source of the ingress certificate
+func rancherArgs(_ context.Context, c client.Client, cr *installv1alpha1.Verrazzano, cfg *installjob.InstallConfiguration) ([]helm.SetArg, error) {
+	dnsSuffix, err := buildDomainSuffix(c, cr)
+	if err != nil {
+		return nil, err
+	}
+	args := []helm.SetArg{{Name: "hostname", Value: fmt.Sprintf("rancher.%s.%s", cfg.EnvironmentName, dnsSuffix)}}
+	if cfg.Certificates.IssuerType == installjob.CertIssuerTypeAcme {
+		environment := cfg.Certificates.ACME.Environment
+		if len(environment) == 0 {
+			environment = "production"
+		}
+		return append(args,
+			helm.SetArg{Name: "ingress.tls.source", Value: "letsEncrypt"},
+			helm.SetArg{Name: "letsEncrypt.ingress.class", Value: "rancher"},
+			helm.SetArg{Name: "letsEncrypt.email", Value: cfg.Certificates.ACME.EmailAddress},
+			helm.SetArg{Name: "letsEncrypt.environment", Value: environment}), nil
+	}
+	return append(args, helm.SetArg{Name: "ingress.tls.source", Value: "rancher"}), nil
+}
+
+// verrazzanoArgs returns the set arguments of the verrazzano chart.  The Rancher credentials and the CA bundle of
+// the admission controller are created by the install scripts, and are not set.
+func verrazzanoArgs(ctx context.Context, c client.Client, cr *installv1alpha1.Verrazzano, cfg *installjob.InstallConfiguration) ([]helm.SetArg, error) {
+	dnsSuffix, err := buildDomainSuffix(c, cr)
+	if err != nil {
+		return nil, err
+	}
+	args := []helm.SetArg{
+		{Name: "image.pullPolicy", Value: "IfNotPresent"},
+		{Name: "config.envName", Value: cfg.EnvironmentName},
+		{Name: "config.dnsSuffix", Value: dnsSuffix},
+		{Name: "config.enableMonitoringStorage", Value: "true"},
+		{Name: "clusterOperator.rancherURL", Value: fmt.Sprintf("https://rancher.%s.%s", cfg.EnvironmentName, dnsSuffix)},
+	}
+	v8oArgs, err := applicationOperatorArgs(ctx, c, cr, cfg)
+	return append(args, v8oArgs...), err
+}
+
+// applicationOperatorArgs returns the set arguments of the application operator, which are the Verrazzano install
+// args from the spec
+func applicationOperatorArgs(ctx context.Context, c client.Client, cr *installv1alpha1.Verrazzano, cfg *installjob.InstallConfiguration) ([]helm.SetArg, error) {
+	args := getConfigSetArgs(cfg.VzInstallArgs)
+	pullSecretArgs, err := imagePullSecretArgs("global.imagePullSecrets[0]")(ctx, c, cr, cfg)
+	return append(args, pullSecretArgs...), err
+}
+
+// weblogicOperatorArgs returns the set arguments of the WebLogic operator, which manages the domains in the
+// namespaces with the verrazzano-managed label
+func weblogicOperatorArgs(_ context.Context, _ client.Client, _ *installv1alpha1.Verrazzano, _ *installjob.InstallConfiguration) ([]helm.SetArg, error) {
+	return []helm.SetArg{
+		{Name: "serviceAccount", Value: "weblogic-operator-sa"},
+		{Name: "domainNamespaceSelectionStrategy", Value: "LabelSelector"},
+		{Name: "domainNamespaceLabelSelector", Value: "verrazzano-managed"},
+		{Name: "enableClusterRoleBinding", Value: "true"},
+	}, nil
+}
+
+// mysqlArgs returns the set arguments of MySQL, which creates the Keycloak database
+func mysqlArgs(_ context.Context, _ client.Client, _ *installv1alpha1.Verrazzano, cfg *installjob.InstallConfiguration) ([]helm.SetArg, error) {
+	args := getConfigSetArgs(cfg.Keycloak.MySQL.MySQLInstallArgs)
+	return append(args,
+		helm.SetArg{Name: "mysqlUser", Value: mysqlUser},
+		helm.SetArg{Name: "initializationFiles.create-db", Value: createKeycloakDatabase, SetString: true}), nil
+}
+
+// keycloakArgs returns the set arguments of Keycloak, the ingress and the credentials of the MySQL database
+func keycloakArgs(ctx context.Context, c client.Client, cr *installv1alpha1.Verrazzano, cfg *installjob.InstallConfiguration) ([]helm.SetArg, error) {
+	dnsSuffix, err := buildDomainSuffix(c, cr)
+	if err != nil {
+		return nil, err
+	}
+	mysqlSecret := corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: keycloakNamespace, Name: "mysql"}, &mysqlSecret); err != nil {
+		return nil, err
+	}
+	host := fmt.Sprintf("keycloak.%s.%s", cfg.EnvironmentName, dnsSuffix)
+	args, err := imagePullSecretArgs("keycloak.image.pullSecrets[0]")(ctx, c, cr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args,
+		helm.SetArg{Name: "keycloak.username", Value: keycloakAdminUser},
+		helm.SetArg{Name: `keycloak.ingress.annotations.external-dns\.alpha\.kubernetes\.io/target`,
+			Value: fmt.Sprintf("verrazzano-ingress.%s.%s", cfg.EnvironmentName, dnsSuffix), SetString: true},
+		helm.SetArg{Name: "keycloak.ingress.hosts", Value: fmt.Sprintf("{%s}", host)},
+		helm.SetArg{Name: "keycloak.ingress.tls[0].hosts", Value: fmt.Sprintf("{%s}", host)},
+		helm.SetArg{Name: "keycloak.ingress.tls[0].secretName", Value: cfg.EnvironmentName + "-secret"},
+		helm.SetArg{Name: "keycloak.persistence.dbPassword", Value: string(mysqlSecret.Data["mysql-password"])},
+		helm.SetArg{Name: "keycloak.persistence.dbUser", Value: mysqlUser})
+	return append(args, getConfigSetArgs(cfg.Keycloak.KeycloakInstallArgs)...), nil
+}
+
+// registrySecretExists returns true if the global image pull secret exists in the default namespace
+func registrySecretExists(ctx context.Context, c client.Client) (bool, error) {
+	secret := corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: "default", Name: globalImagePullSecret}, &secret)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// getConfigSetArgs converts the install args of the install configuration to helm set arguments.  Args without
+// a name or a value are skipped, the same as the install scripts.
+func getConfigSetArgs(installArgs []installjob.InstallArg) []helm.SetArg {
+	var args []helm.SetArg
+	for _, arg := range installArgs {
+		if len(arg.Name) == 0 || len(arg.Value) == 0 {
+			continue
+		}
+		args = append(args, helm.SetArg{Name: arg.Name, Value: arg.Value, SetString: arg.SetString})
+	}
+	return args
+}
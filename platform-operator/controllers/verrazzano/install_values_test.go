@@ -0,0 +1,159 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/installjob"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestComponentInstallArgsOCI tests the install args of the components for the following use case
+// GIVEN a Verrazzano resource with OCI DNS, an ACME certificate issuer and install args
+// WHEN the install args of each component are computed
+// THEN the args are the same as the --set args of the install scripts
+func TestComponentInstallArgsOCI(t *testing.T) {
+	asserts := assert.New(t)
+
+	vz := newInstallVerrazzano()
+	vz.Spec.EnvironmentName = "myenv"
+	vz.Spec.Components.DNS.OCI = vzapi.OCI{
+		OCIConfigSecret: "oci-config",
+		DNSZoneOCID:     "zone-ocid",
+		DNSZoneName:     "example.com",
+	}
+	vz.Spec.Components.CertManager.Certificate.Acme = vzapi.Acme{Provider: vzapi.LetsEncrypt, EmailAddress: "admin@example.com"}
+	vz.Spec.Components.Ingress.NGINXInstallArgs = []vzapi.InstallArgs{{Name: "controller.replicaCount", Value: "2"}}
+	vz.Spec.Components.Istio.IstioInstallArgs = []vzapi.InstallArgs{{Name: "gateways.istio-ingressgateway.replicaCount", Value: "3"}}
+	registrySecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: globalImagePullSecret}}
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, registrySecret, newMySQLSecret())
+	pullSecret := helm.SetArg{Name: "global.imagePullSecrets[0]", Value: globalImagePullSecret}
+
+	// 1-install-istio.sh
+	asserts.Equal([]helm.SetArg{pullSecret}, getInstallArgs(t, c, vz, "istiod"))
+	asserts.Equal([]helm.SetArg{
+		{Name: "gateways.istio-ingressgateway.replicaCount", Value: "3"},
+		{Name: "gateways.istio-ingressgateway.type", Value: "LoadBalancer"},
+		pullSecret,
+	}, getInstallArgs(t, c, vz, "istio-ingress"))
+	asserts.Equal([]helm.SetArg{pullSecret}, getInstallArgs(t, c, vz, "prometheus"))
+	_, ok := componentInstallArgs["istio-base"]
+	asserts.False(ok, "The install scripts don't set any values for istio-base")
+
+	// 2-install-system-components.sh
+	asserts.Equal([]helm.SetArg{
+		{Name: "controller.replicaCount", Value: "2"},
+		{Name: `controller.service.annotations.external-dns\.alpha\.kubernetes\.io/ttl`, Value: "60", SetString: true},
+		{Name: `controller.service.annotations.external-dns\.alpha\.kubernetes\.io/hostname`, Value: "verrazzano-ingress.myenv.example.com"},
+		{Name: "controller.service.type", Value: "LoadBalancer"},
+	}, getInstallArgs(t, c, vz, "ingress-controller"))
+	asserts.Empty(getInstallArgs(t, c, vz, "cert-manager"))
+	asserts.Equal([]helm.SetArg{
+		{Name: "domainFilters[0]", Value: "example.com"},
+		{Name: "zoneIdFilters[0]", Value: "zone-ocid"},
+		{Name: "txtOwnerId", Value: "v8o-local-myenv"},
+		{Name: "txtPrefix", Value: "_v8o-local-myenv_"},
+		{Name: "extraVolumes[0].name", Value: "config"},
+		{Name: "extraVolumes[0].secret.secretName", Value: "oci-config"},
+		{Name: "extraVolumeMounts[0].name", Value: "config"},
+		{Name: "extraVolumeMounts[0].mountPath", Value: "/etc/kubernetes/"},
+	}, getInstallArgs(t, c, vz, "external-dns"))
+	asserts.Equal([]helm.SetArg{
+		{Name: "hostname", Value: "rancher.myenv.example.com"},
+		{Name: "ingress.tls.source", Value: "letsEncrypt"},
+		{Name: "letsEncrypt.ingress.class", Value: "rancher"},
+		{Name: "letsEncrypt.email", Value: "admin@example.com"},
+		{Name: "letsEncrypt.environment", Value: "production"},
+	}, getInstallArgs(t, c, vz, "rancher"))
+
+	// 3-install-verrazzano.sh
+	asserts.Equal([]helm.SetArg{
+		{Name: "image.pullPolicy", Value: "IfNotPresent"},
+		{Name: "config.envName", Value: "myenv"},
+		{Name: "config.dnsSuffix", Value: "example.com"},
+		{Name: "config.enableMonitoringStorage", Value: "true"},
+		{Name: "clusterOperator.rancherURL", Value: "https://rancher.myenv.example.com"},
+		pullSecret,
+	}, getInstallArgs(t, c, vz, "verrazzano"))
+	asserts.Equal([]helm.SetArg{pullSecret}, getInstallArgs(t, c, vz, "verrazzano-application-operator"))
+
+	// 4-install-keycloak.sh
+	keycloakArgs := getInstallArgs(t, c, vz, "keycloak")
+	asserts.Contains(keycloakArgs, helm.SetArg{Name: "keycloak.image.pullSecrets[0]", Value: globalImagePullSecret})
+	asserts.Contains(keycloakArgs, helm.SetArg{Name: "keycloak.ingress.hosts", Value: "{keycloak.myenv.example.com}"})
+	asserts.Contains(keycloakArgs, helm.SetArg{Name: "keycloak.ingress.tls[0].secretName", Value: "myenv-secret"})
+	asserts.Contains(keycloakArgs, helm.SetArg{Name: "keycloak.persistence.dbPassword", Value: "password"})
+	asserts.Contains(keycloakArgs, helm.SetArg{Name: `keycloak.ingress.annotations.external-dns\.alpha\.kubernetes\.io/target`,
+		Value: "verrazzano-ingress.myenv.example.com", SetString: true})
+}
+
+// TestComponentInstallArgsXipIo tests the install args of the components for the following use case
+// GIVEN a Verrazzano resource with the default xip.io DNS and CA certificate issuer
+// WHEN the install args of each component are computed
+// THEN the args are the same as the --set args of the install scripts, and the DNS suffix is the address of the
+//      ingress controller
+func TestComponentInstallArgsXipIo(t *testing.T) {
+	asserts := assert.New(t)
+
+	vz := newInstallVerrazzano()
+	vz.Spec.Components.Ingress.Type = vzapi.NodePort
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, newIngressService())
+
+	asserts.Empty(getInstallArgs(t, c, vz, "istiod"), "The image pull secret should not be set without the registry secret")
+	asserts.Equal([]helm.SetArg{{Name: "controller.service.type", Value: "NodePort"}}, getInstallArgs(t, c, vz, "ingress-controller"))
+	asserts.Equal([]helm.SetArg{{Name: "clusterResourceNamespace", Value: "cattle-system"}}, getInstallArgs(t, c, vz, "cert-manager"))
+	asserts.Empty(getInstallArgs(t, c, vz, "external-dns"))
+	asserts.Equal([]helm.SetArg{
+		{Name: "hostname", Value: "rancher.default.127.0.0.1.xip.io"},
+		{Name: "ingress.tls.source", Value: "rancher"},
+	}, getInstallArgs(t, c, vz, "rancher"))
+
+	// The DNS suffix of a xip.io install can't be computed before the ingress controller is installed
+	c = fake.NewFakeClientWithScheme(newInstallScheme(), vz)
+	_, err := getComponentInstallValues(context.TODO(), zap.S(), c, vz, "rancher")
+	asserts.Error(err)
+}
+
+// TestGetComponentInstallValues tests the getComponentInstallValues function
+// GIVEN a Verrazzano resource
+// WHEN the install values of the verrazzano component are computed
+// THEN the values of the set args are returned
+func TestGetComponentInstallValues(t *testing.T) {
+	asserts := assert.New(t)
+
+	vz := newInstallVerrazzano()
+	vz.Spec.Components.DNS.External.Suffix = "example.com"
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz)
+
+	values, err := getComponentInstallValues(context.TODO(), zap.S(), c, vz, "verrazzano")
+	asserts.NoError(err)
+	asserts.Len(values, 1)
+	argsValues := map[string]interface{}{}
+	asserts.NoError(json.Unmarshal([]byte(values[0]), &argsValues))
+	asserts.Equal(map[string]interface{}{"envName": "default", "dnsSuffix": "example.com", "enableMonitoringStorage": true},
+		argsValues["config"])
+
+	values, err = getComponentInstallValues(context.TODO(), zap.S(), c, vz, "coherence-operator")
+	asserts.NoError(err)
+	asserts.Empty(values)
+}
+
+// getInstallArgs returns the install args of a component
+func getInstallArgs(t *testing.T, c client.Client, vz *vzapi.Verrazzano, compName string) []helm.SetArg {
+	cfg, err := installjob.GetInstallConfig(vz, zap.S())
+	assert.NoError(t, err)
+	args, err := componentInstallArgs[compName](context.TODO(), c, vz, cfg)
+	assert.NoError(t, err)
+	return args
+}
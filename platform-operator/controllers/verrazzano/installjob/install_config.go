@@ -174,11 +174,23 @@ type InstallConfiguration struct {
 	Certificates    Certificate    `json:"certificates"`
 	Keycloak        Keycloak       `json:"keycloak"`
 	VzInstallArgs   []InstallArg   `json:"verrazzanoInstallArgs,omitempty"`
+	// DisabledComponents are the components that are not installed by the scripts
+	DisabledComponents []string `json:"disabledComponents,omitempty"`
 }
 
 // GetInstallConfig returns an install configuration in the json format required by the
 // bash installer scripts.
 func GetInstallConfig(vz *installv1alpha1.Verrazzano, log *zap.SugaredLogger) (*InstallConfiguration, error) {
+	config, err := newInstallConfig(vz, log)
+	if err != nil {
+		return nil, err
+	}
+	config.DisabledComponents = installv1alpha1.GetDisabledComponents(&vz.Spec.Components)
+	return config, nil
+}
+
+// newInstallConfig returns the install configuration for the DNS type of the Verrazzano resource
+func newInstallConfig(vz *installv1alpha1.Verrazzano, log *zap.SugaredLogger) (*InstallConfiguration, error) {
 	if vz.Spec.Components.DNS.External != (installv1alpha1.External{}) {
 		return newExternalDNSInstallConfig(vz, log)
 	}
@@ -33,6 +33,27 @@ func TestXipIoInstallDefaults(t *testing.T) {
 	assert.Equalf(t, "tls-rancher", config.Certificates.CA.SecretName, "Expected CA secret name did not match")
 	assert.Equalf(t, 0, len(config.Keycloak.KeycloakInstallArgs), "Expected keycloakInstallArgs length did not match")
 	assert.Equalf(t, 0, len(config.Keycloak.MySQL.MySQLInstallArgs), "Expected mySqlInstallArgs length did not match")
+	assert.Emptyf(t, config.DisabledComponents, "Expected no disabled components")
+}
+
+// TestInstallDisabledComponents tests the disabled components of an install configuration
+// GIVEN a verrazzano.install.verrazzano.io custom resource that disables components
+//  WHEN I call GetInstallConfig
+//  THEN the install configuration has the disabled components, so that the scripts skip them
+func TestInstallDisabledComponents(t *testing.T) {
+	disabled := false
+	vz := installv1alpha1.Verrazzano{
+		Spec: installv1alpha1.VerrazzanoSpec{
+			Components: installv1alpha1.ComponentSpec{
+				Console:  installv1alpha1.ConsoleComponent{Enabled: &disabled},
+				Keycloak: installv1alpha1.KeycloakComponent{Enabled: &disabled},
+				Rancher:  installv1alpha1.RancherComponent{Enabled: &disabled},
+			},
+		},
+	}
+	config, err := GetInstallConfig(&vz, zap.S())
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"rancher", "mysql", "keycloak", "console"}, config.DisabledComponents)
 }
 
 // TestXipIoInstallNonDefaults tests the creation of an xip.io install non-default configuration
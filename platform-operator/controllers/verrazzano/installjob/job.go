@@ -15,12 +15,18 @@ import (
 type JobConfig struct {
 	k8s.JobConfigCommon // Extending the base job config
 
-	ConfigMapName string // Name of the install configmap used by the scripts
+	ConfigMapName         string // Name of the install configmap used by the scripts
+	ProgressConfigMapName string // Name of the configmap the scripts write the install progress to
+	VerrazzanoName        string // Name of the Verrazzano resource that is installed
 }
 
 // installMode value for MODE variable for install jobs
 const installMode = "INSTALL"
 
+// ProgressLabel is the label of the install progress configmap that is written by the install scripts.
+// The value of the label is the name of the Verrazzano resource that is installed.
+const ProgressLabel = "install.verrazzano.io/install-progress"
+
 // NewJob returns a job resource for installing Verrazzano
 func NewJob(jobConfig *JobConfig) *batchv1.Job {
 	var backOffLimit int32 = 0
@@ -98,5 +104,23 @@ func NewJob(jobConfig *JobConfig) *batchv1.Job {
 		},
 	}
 
+	// The install scripts only report the install progress when the progress configmap is set
+	if len(jobConfig.ProgressConfigMapName) > 0 {
+		container := &job.Spec.Template.Spec.Containers[0]
+		container.Env = append(container.Env,
+			corev1.EnvVar{
+				Name:  "INSTALL_PROGRESS_CONFIGMAP",
+				Value: jobConfig.ProgressConfigMapName,
+			},
+			corev1.EnvVar{
+				Name:  "INSTALL_PROGRESS_NAMESPACE",
+				Value: jobConfig.Namespace,
+			},
+			corev1.EnvVar{
+				Name:  "INSTALL_PROGRESS_OWNER",
+				Value: jobConfig.VerrazzanoName,
+			})
+	}
+
 	return job
 }
@@ -78,3 +78,27 @@ func TestNewJobDryRun(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "true", dryRun)
 }
+
+// TestNewJobProgress tests the creation of a job that reports the install progress
+// GIVEN a request to create a job
+// WHEN a progress configmap is specified
+// THEN a job is created with the env vars that the install scripts use to report the install progress
+func TestNewJobProgress(t *testing.T) {
+	job := NewJob(&JobConfig{
+		JobConfigCommon: k8s.JobConfigCommon{
+			JobName:   "test-job",
+			Namespace: "verrazzano",
+		},
+		ConfigMapName:         "test-config",
+		ProgressConfigMapName: "test-progress",
+		VerrazzanoName:        "test",
+	})
+
+	env := map[string]string{}
+	for _, envVar := range job.Spec.Template.Spec.Containers[0].Env {
+		env[envVar.Name] = envVar.Value
+	}
+	assert.Equal(t, "test-progress", env["INSTALL_PROGRESS_CONFIGMAP"])
+	assert.Equal(t, "verrazzano", env["INSTALL_PROGRESS_NAMESPACE"])
+	assert.Equal(t, "test", env["INSTALL_PROGRESS_OWNER"])
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"time"
+
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/metrics"
+)
+
+// The states of a Verrazzano resource that are reported by the state metric
+var metricStates = []string{
+	string(installv1alpha1.Installing),
+	string(installv1alpha1.Uninstalling),
+	string(installv1alpha1.Upgrading),
+	string(installv1alpha1.Ready),
+	string(installv1alpha1.Failed),
+}
+
+// The lifecycle operations whose duration is recorded, keyed by the condition type that ends the operation.  The
+// duration of an operation is the time from the start condition to the condition that ends the operation.
+var metricOperations = map[installv1alpha1.ConditionType]struct {
+	name   string
+	start  installv1alpha1.ConditionType
+	result string
+}{
+	installv1alpha1.InstallComplete:   {name: "install", start: installv1alpha1.InstallStarted, result: metrics.ResultSuccess},
+	installv1alpha1.InstallFailed:     {name: "install", start: installv1alpha1.InstallStarted, result: metrics.ResultFailure},
+	installv1alpha1.UpgradeComplete:   {name: "upgrade", start: installv1alpha1.UpgradeStarted, result: metrics.ResultSuccess},
+	installv1alpha1.UpgradeFailed:     {name: "upgrade", start: installv1alpha1.UpgradeStarted, result: metrics.ResultFailure},
+	installv1alpha1.UpgradeRolledBack: {name: "upgrade", start: installv1alpha1.UpgradeStarted, result: metrics.ResultFailure},
+	installv1alpha1.UninstallComplete: {name: "uninstall", start: installv1alpha1.UninstallStarted, result: metrics.ResultSuccess},
+	installv1alpha1.UninstallFailed:   {name: "uninstall", start: installv1alpha1.UninstallStarted, result: metrics.ResultFailure},
+}
+
+// initMetrics creates the metrics of the components that are managed by the operator
+func initMetrics() {
+	var names []string
+	for _, comp := range component.GetComponents() {
+		names = append(names, comp.Name())
+	}
+	metrics.InitComponents(names)
+}
+
+// recordStateMetric sets the state metric of the Verrazzano resource to its current state
+func recordStateMetric(cr *installv1alpha1.Verrazzano) {
+	if len(cr.Status.State) == 0 {
+		return
+	}
+	metrics.SetState(cr.Namespace, cr.Name, string(cr.Status.State), metricStates)
+}
+
+// deleteStateMetric deletes the state metric of a Verrazzano resource that was deleted
+func deleteStateMetric(namespace string, name string) {
+	metrics.DeleteState(namespace, name, metricStates)
+}
+
+// recordOperationMetric records the duration of the operation that is ended by the last condition of the
+// Verrazzano resource.  Nothing is recorded if the condition doesn't end an operation, or if the start of the
+// operation is not known.
+func recordOperationMetric(cr *installv1alpha1.Verrazzano) {
+	l := len(cr.Status.Conditions)
+	if l == 0 {
+		return
+	}
+	last := cr.Status.Conditions[l-1]
+	op, ok := metricOperations[last.Type]
+	if !ok {
+		return
+	}
+	for i := l - 2; i >= 0; i-- {
+		if cr.Status.Conditions[i].Type != op.start {
+			continue
+		}
+		started, err := time.Parse(time.RFC3339, cr.Status.Conditions[i].LastTransitionTime)
+		ended, err2 := time.Parse(time.RFC3339, last.LastTransitionTime)
+		if err != nil || err2 != nil {
+			return
+		}
+		metrics.ObserveOperation(op.name, op.result, ended.Sub(started))
+		return
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// TestUpgradeMetrics tests the metrics that are recorded for an upgrade
+// GIVEN a request to reconcile a verrazzano resource that needs to be upgraded
+// WHEN the upgrade of a component fails
+// THEN ensure that the component failure and the duration of the failed upgrade are recorded, and the state
+//      metric of the resource is failed
+func TestUpgradeMetrics(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+	runner := &upgradeRunner{upgraded: map[string]bool{}, failRelease: "keycloak"}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+	component.UpgradePrehooksEnabled = false
+	defer func() { component.UpgradePrehooksEnabled = true }()
+
+	initMetrics()
+	failures := gatherMetric(t, "verrazzano_platform_operator_component_upgrade_failures_total", map[string]string{"component": "keycloak"})
+	if !asserts.NotNil(failures, "The component failure counter was not created") {
+		return
+	}
+	upgrades := gatherMetric(t, "verrazzano_platform_operator_operation_duration_seconds", map[string]string{"operation": "upgrade", "result": "failure"})
+
+	vz := newUpgradeVerrazzano()
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz)
+	reconciler := newVerrazzanoReconciler(c)
+	_, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+
+	metric := gatherMetric(t, "verrazzano_platform_operator_component_upgrade_failures_total", map[string]string{"component": "keycloak"})
+	asserts.Equal(failures.GetCounter().GetValue()+1, metric.GetCounter().GetValue(), "The component failure was not counted")
+	metric = gatherMetric(t, "verrazzano_platform_operator_component_upgrade_duration_seconds", map[string]string{"component": "istiod"})
+	asserts.NotNil(metric, "The component upgrade duration was not recorded")
+	metric = gatherMetric(t, "verrazzano_platform_operator_operation_duration_seconds", map[string]string{"operation": "upgrade", "result": "failure"})
+	asserts.Equal(upgrades.GetHistogram().GetSampleCount()+1, metric.GetHistogram().GetSampleCount(), "The upgrade duration was not recorded")
+
+	labels := map[string]string{"namespace": vz.Namespace, "name": vz.Name, "state": string(vzapi.Failed)}
+	asserts.Equal(float64(1), gatherMetric(t, "verrazzano_platform_operator_state", labels).GetGauge().GetValue())
+	labels["state"] = string(vzapi.Upgrading)
+	asserts.Equal(float64(0), gatherMetric(t, "verrazzano_platform_operator_state", labels).GetGauge().GetValue())
+
+	// The state metric is deleted with the resource
+	asserts.NoError(c.Delete(context.TODO(), vz))
+	_, err = reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.Nil(gatherMetric(t, "verrazzano_platform_operator_state", labels), "The state metric was not deleted")
+}
+
+// gatherMetric returns the metric with the name and labels from the controller-runtime metrics registry, or nil
+// if the metric doesn't exist
+func gatherMetric(t *testing.T, name string, labels map[string]string) *dto.Metric {
+	families, err := crmetrics.Registry.Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			matched := 0
+			for _, label := range metric.GetLabel() {
+				if value, ok := labels[label.GetName()]; ok && value == label.GetValue() {
+					matched++
+				}
+			}
+			if matched == len(labels) {
+				return metric
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,356 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+)
+
+// The key in the internal configmap that holds the overrides that were applied to the components
+const overridesDataKey = "overrides"
+
+// savedOverrides records the overrides of a component that were applied by the last install, upgrade or update.
+// The hash of the values detects a change to the overrides, including a change to the contents of an override
+// ConfigMap or Secret.  Only the keys of the values are saved, so that the contents of Secrets are not copied
+// into the internal configmap.  The keys are enough to remove the previous values from the release.
+type savedOverrides struct {
+	Hash string `json:"hash"`
+	Keys string `json:"keys,omitempty"`
+}
+
+// consoleDisabledValues are the verrazzano chart values that disable the console
+const consoleDisabledValues = "console:\n  enabled: false\n"
+
+// istioOverrides returns the overrides of the istio component, which are applied to each of the istio releases
+func istioOverrides(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides {
+	return comps.Istio.Overrides
+}
+
+// componentOverrides maps the name of each registered component to the overrides of the component in the spec
+var componentOverrides = map[string]func(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides{
+	"istio-base":    istioOverrides,
+	"istiod":        istioOverrides,
+	"istio-ingress": istioOverrides,
+	"istio-egress":  istioOverrides,
+	"istiocoredns":  istioOverrides,
+	"grafana": func(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides {
+		return comps.Grafana.Overrides
+	},
+	"prometheus": func(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides {
+		return comps.Prometheus.Overrides
+	},
+	"ingress-controller": func(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides {
+		return comps.Ingress.Overrides
+	},
+	"cert-manager": func(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides {
+		return comps.CertManager.Overrides
+	},
+	"external-dns": func(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides {
+		return comps.DNS.Overrides
+	},
+	"rancher": func(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides {
+		return comps.Rancher.Overrides
+	},
+	"verrazzano": func(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides {
+		return comps.Verrazzano.Overrides
+	},
+	"coherence-operator": func(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides {
+		return comps.CoherenceOperator.Overrides
+	},
+	"weblogic-operator": func(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides {
+		return comps.WebLogicOperator.Overrides
+	},
+	"oam-kubernetes-runtime": func(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides {
+		return comps.OAM.Overrides
+	},
+	"verrazzano-application-operator": func(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides {
+		return comps.ApplicationOperator.Overrides
+	},
+	"mysql": func(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides {
+		return comps.Keycloak.MySQL.Overrides
+	},
+	"keycloak": func(comps *installv1alpha1.ComponentSpec) []installv1alpha1.Overrides {
+		return comps.Keycloak.Overrides
+	},
+}
+
+// getComponentOverrides returns the YAML helm values of the overrides of a component, in the order they are
+// specified.  The ConfigMaps and Secrets are read from the namespace of the Verrazzano resource.  A missing
+// ConfigMap, Secret or key is an error unless the reference is optional.
+func getComponentOverrides(ctx context.Context, log *zap.SugaredLogger, c client.Client, cr *installv1alpha1.Verrazzano, compName string) ([]string, error) {
+	overridesFunc, ok := componentOverrides[compName]
+	if !ok {
+		return nil, nil
+	}
+	var values []string
+	if compName == "verrazzano" {
+		// The values file of the base profile comes first, so that the overrides of the profile and the spec
+		// are applied on top of it
+		profileValues, err := getProfileValues(cr)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, profileValues)
+		// The console is installed by the verrazzano chart, so it is disabled using a chart value
+		if !installv1alpha1.IsEnabled(cr.Spec.Components.Console.Enabled) {
+			values = append(values, consoleDisabledValues)
+		}
+	}
+	for _, override := range overridesFunc(&cr.Spec.Components) {
+		var value string
+		var found bool
+		var err error
+		switch {
+		case override.ConfigMapRef != nil:
+			value, found, err = getConfigMapOverride(ctx, c, cr.Namespace, override.ConfigMapRef)
+		case override.SecretRef != nil:
+			value, found, err = getSecretOverride(ctx, c, cr.Namespace, override.SecretRef)
+		default:
+			value, found = override.Values, true
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting the overrides of component %s: %v", compName, err)
+		}
+		if !found {
+			log.Infof("Skipping optional overrides of component %s that were not found", compName)
+			continue
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// getComponentsOverrides returns the YAML helm values of the overrides of each of the components, keyed by
+// component name
+func getComponentsOverrides(ctx context.Context, log *zap.SugaredLogger, c client.Client, cr *installv1alpha1.Verrazzano, comps []component.Component) (map[string][]string, error) {
+	overrides := map[string][]string{}
+	for _, comp := range comps {
+		values, err := getComponentOverrides(ctx, log, c, cr, comp.Name())
+		if err != nil {
+			return nil, err
+		}
+		overrides[comp.Name()] = values
+	}
+	return overrides, nil
+}
+
+// getConfigMapOverride returns the value of the ConfigMap key.  False is returned if an optional ConfigMap
+// or key doesn't exist.
+func getConfigMapOverride(ctx context.Context, c client.Client, namespace string, ref *corev1.ConfigMapKeySelector) (string, bool, error) {
+	cm := corev1.ConfigMap{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &cm)
+	if errors.IsNotFound(err) && isOptional(ref.Optional) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := cm.Data[ref.Key]
+	if !ok {
+		if isOptional(ref.Optional) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("key %s not found in ConfigMap %s/%s", ref.Key, namespace, ref.Name)
+	}
+	return value, true, nil
+}
+
+// getSecretOverride returns the value of the Secret key.  False is returned if an optional Secret or key
+// doesn't exist.
+func getSecretOverride(ctx context.Context, c client.Client, namespace string, ref *corev1.SecretKeySelector) (string, bool, error) {
+	secret := corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &secret)
+	if errors.IsNotFound(err) && isOptional(ref.Optional) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		if isOptional(ref.Optional) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("key %s not found in Secret %s/%s", ref.Key, namespace, ref.Name)
+	}
+	return string(value), true, nil
+}
+
+// isOptional returns true if an optional reference is set to true
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
+
+// getProfileValues returns the values file of the verrazzano chart for the base profile of the spec, the same
+// as the values file that the install scripts use
+func getProfileValues(cr *installv1alpha1.Verrazzano) (string, error) {
+	profile := cr.Spec.Profile
+	if len(profile) == 0 {
+		profile = installv1alpha1.Prod
+	}
+	data, err := ioutil.ReadFile(filepath.Join(component.VzChartDir(), fmt.Sprintf("values.%s.yaml", profile)))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// getEnabledComponents returns the registered components that are enabled in the spec
+func getEnabledComponents(spec *installv1alpha1.VerrazzanoSpec) []component.Component {
+	var enabled []component.Component
+	for _, comp := range component.GetComponents() {
+		if installv1alpha1.IsComponentEnabled(&spec.Components, comp.Name()) {
+			enabled = append(enabled, comp)
+		}
+	}
+	return enabled
+}
+
+// hashOverrides returns the hash of the YAML helm values of the overrides of a component
+func hashOverrides(values []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(values, "\n---\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// getValuesKeys returns YAML with the keys of the merged values, with a null value for each of the leaves
+func getValuesKeys(values []string) (string, error) {
+	keys := map[string]interface{}{}
+	for _, value := range values {
+		vals := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(value), &vals); err != nil {
+			return "", err
+		}
+		mergeKeys(keys, vals)
+	}
+	if len(keys) == 0 {
+		return "", nil
+	}
+	keysBytes, err := yaml.Marshal(keys)
+	if err != nil {
+		return "", err
+	}
+	return string(keysBytes), nil
+}
+
+// mergeKeys merges the keys of the values into the keys.  A nested map is merged recursively, any other value
+// replaces the key with a null leaf.
+func mergeKeys(keys map[string]interface{}, vals map[string]interface{}) {
+	for k, v := range vals {
+		if valsMap, ok := v.(map[string]interface{}); ok {
+			keysMap, ok := keys[k].(map[string]interface{})
+			if !ok {
+				keysMap = map[string]interface{}{}
+				keys[k] = keysMap
+			}
+			mergeKeys(keysMap, valsMap)
+			continue
+		}
+		keys[k] = nil
+	}
+}
+
+// getOverridesData returns the JSON of the saved overrides of the enabled components, to be saved in the
+// internal configmap
+func getOverridesData(ctx context.Context, log *zap.SugaredLogger, c client.Client, cr *installv1alpha1.Verrazzano) (string, error) {
+	overrides, err := getComponentsOverrides(ctx, log, c, cr, getEnabledComponents(&cr.Spec))
+	if err != nil {
+		return "", err
+	}
+	saved := map[string]savedOverrides{}
+	for name, values := range overrides {
+		keys, err := getValuesKeys(values)
+		if err != nil {
+			return "", fmt.Errorf("Failed reading the overrides of component %s: %v", name, err)
+		}
+		saved[name] = savedOverrides{Hash: hashOverrides(values), Keys: keys}
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// getSavedOverrides returns the overrides of the components saved in the internal configmap.  Nil is returned if
+// the overrides were not saved, in which case the overrides in the saved spec are used.
+func (r *Reconciler) getSavedOverrides(ctx context.Context, log *zap.SugaredLogger, vz *installv1alpha1.Verrazzano) (map[string]savedOverrides, error) {
+	configMap, err := r.getInternalConfigMap(ctx, vz)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	data, ok := configMap.Data[overridesDataKey]
+	if !ok {
+		return nil, nil
+	}
+	saved := map[string]savedOverrides{}
+	if err := json.Unmarshal([]byte(data), &saved); err != nil {
+		// Invalid saved overrides are ignored, the overrides in the saved spec are used
+		log.Errorf("Error unmarshalling saved overrides for %s: %v", vz.Name, err)
+		return nil, nil
+	}
+	return saved, nil
+}
+
+// OverridesRequests maps a ConfigMap or Secret to requests to reconcile the Verrazzano resources in the same
+// namespace that reference it in the overrides of a component, so that a change to the contents of the overrides
+// is applied to the components
+func (r *Reconciler) OverridesRequests(o handler.MapObject) []reconcile.Request {
+	_, isSecret := o.Object.(*corev1.Secret)
+	vzList := installv1alpha1.VerrazzanoList{}
+	if err := r.List(context.TODO(), &vzList, client.InNamespace(o.Meta.GetNamespace())); err != nil {
+		zap.S().Errorf("Failed listing the Verrazzano resources in namespace %s: %v", o.Meta.GetNamespace(), err)
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range vzList.Items {
+		vz := &vzList.Items[i]
+		// The overrides can be set by the profile, the spec is used if the profile can't be applied
+		spec := &vz.Spec
+		if profile, err := installv1alpha1.GetProfile(context.TODO(), r, vz.Spec.Profile); err == nil {
+			if effectiveSpec, err := installv1alpha1.GetEffectiveSpec(profile, &vz.Spec); err == nil {
+				spec = effectiveSpec
+			}
+		}
+		if isOverridesReference(&spec.Components, o.Meta.GetName(), isSecret) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}})
+		}
+	}
+	return requests
+}
+
+// isOverridesReference returns true if the overrides of a component reference the ConfigMap or Secret
+func isOverridesReference(comps *installv1alpha1.ComponentSpec, name string, isSecret bool) bool {
+	for _, overridesFunc := range componentOverrides {
+		for _, override := range overridesFunc(comps) {
+			if isSecret && override.SecretRef != nil && override.SecretRef.Name == name {
+				return true
+			}
+			if !isSecret && override.ConfigMapRef != nil && override.ConfigMapRef.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// TestComponentOverridesRegistered tests that the overrides of every registered component can be specified
+// GIVEN the component registry
+//  WHEN the overrides of each component are looked up
+//  THEN each component has a part of the spec that contains its overrides
+func TestComponentOverridesRegistered(t *testing.T) {
+	asserts := assert.New(t)
+	for _, comp := range component.GetComponents() {
+		_, ok := componentOverrides[comp.Name()]
+		asserts.True(ok, "No overrides for component %s", comp.Name())
+	}
+}
+
+// TestGetComponentOverrides tests getting the overrides of a component
+// GIVEN a component with inline, ConfigMap and Secret overrides, and an optional ConfigMap that doesn't exist
+//  WHEN getComponentOverrides is called
+//  THEN the values of the overrides are returned in order, and the missing optional ConfigMap is skipped
+func TestGetComponentOverrides(t *testing.T) {
+	asserts := assert.New(t)
+	optional := true
+	vz := newInstallVerrazzano()
+	vz.Spec.Components.Prometheus.Overrides = []vzapi.Overrides{
+		{Values: "retention: 10d"},
+		{ConfigMapRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "prometheus-overrides"},
+			Key:                  "values.yaml"}},
+		{ConfigMapRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+			Key:                  "values.yaml",
+			Optional:             &optional}},
+		{SecretRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "prometheus-secret"},
+			Key:                  "values.yaml"}},
+	}
+	c := fake.NewFakeClientWithScheme(newInstallScheme(),
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: vz.Namespace, Name: "prometheus-overrides"},
+			Data:       map[string]string{"values.yaml": "retention: 20d"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: vz.Namespace, Name: "prometheus-secret"},
+			Data:       map[string][]byte{"values.yaml": []byte("password: secret")},
+		})
+
+	values, err := getComponentOverrides(context.TODO(), zap.S(), c, vz, "prometheus")
+	asserts.NoError(err, "getComponentOverrides returned an error")
+	asserts.Equal([]string{"retention: 10d", "retention: 20d", "password: secret"}, values)
+
+	values, err = getComponentOverrides(context.TODO(), zap.S(), c, vz, "grafana")
+	asserts.NoError(err, "getComponentOverrides returned an error")
+	asserts.Empty(values, "Grafana should not have overrides")
+}
+
+// TestGetComponentOverridesProfileValues tests the overrides of the verrazzano component
+// GIVEN a Verrazzano resource with the dev profile, verrazzano overrides and the console disabled
+//  WHEN getComponentOverrides is called for the verrazzano component
+//  THEN the values file of the profile is returned first, followed by the console values and the overrides
+func TestGetComponentOverridesProfileValues(t *testing.T) {
+	asserts := assert.New(t)
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+	disabled := false
+	vz := newInstallVerrazzano()
+	vz.Spec.Profile = vzapi.Dev
+	vz.Spec.Components.Console.Enabled = &disabled
+	vz.Spec.Components.Verrazzano.Overrides = []vzapi.Overrides{{Values: "elasticSearch:\n  nodes:\n    master:\n      replicas: 2"}}
+	c := fake.NewFakeClientWithScheme(newInstallScheme())
+
+	values, err := getComponentOverrides(context.TODO(), zap.S(), c, vz, "verrazzano")
+	asserts.NoError(err, "getComponentOverrides returned an error")
+	profileValues, err := ioutil.ReadFile("../../helm_config/charts/verrazzano/values.dev.yaml")
+	asserts.NoError(err)
+	asserts.Equal([]string{string(profileValues), consoleDisabledValues, "elasticSearch:\n  nodes:\n    master:\n      replicas: 2"}, values)
+
+	config.Set(config.OperatorConfig{HelmConfigDir: "/no-such-dir"})
+	_, err = getComponentOverrides(context.TODO(), zap.S(), c, vz, "verrazzano")
+	asserts.Error(err, "A missing profile values file should be an error")
+}
+
+// TestGetComponentOverridesIstio tests that the istio overrides are applied to all of the istio releases
+// GIVEN istio overrides
+//  WHEN getComponentsOverrides is called for the istio components
+//  THEN the istio overrides are returned for each of the components
+func TestGetComponentOverridesIstio(t *testing.T) {
+	asserts := assert.New(t)
+	vz := newInstallVerrazzano()
+	vz.Spec.Components.Istio.Overrides = []vzapi.Overrides{{Values: "global:\n  proxy:\n    cpu: 100m"}}
+	var istioComps []component.Component
+	for _, comp := range component.GetComponents() {
+		if comp.Name() == "istiod" || comp.Name() == "istio-ingress" {
+			istioComps = append(istioComps, comp)
+		}
+	}
+
+	overrides, err := getComponentsOverrides(context.TODO(), zap.S(), fake.NewFakeClientWithScheme(newInstallScheme()), vz, istioComps)
+	asserts.NoError(err, "getComponentsOverrides returned an error")
+	asserts.Len(overrides, 2)
+	asserts.Equal([]string{vz.Spec.Components.Istio.Overrides[0].Values}, overrides["istiod"])
+	asserts.Equal([]string{vz.Spec.Components.Istio.Overrides[0].Values}, overrides["istio-ingress"])
+}
+
+// TestGetComponentOverridesNotFound tests getting overrides that don't exist
+// GIVEN overrides that reference a ConfigMap that doesn't exist, or a Secret key that doesn't exist
+//  WHEN getComponentOverrides is called
+//  THEN an error is returned
+func TestGetComponentOverridesNotFound(t *testing.T) {
+	asserts := assert.New(t)
+	vz := newInstallVerrazzano()
+	c := fake.NewFakeClientWithScheme(newInstallScheme(),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: vz.Namespace, Name: "keycloak-secret"},
+			Data:       map[string][]byte{"other.yaml": []byte("a: b")},
+		})
+
+	vz.Spec.Components.Keycloak.Overrides = []vzapi.Overrides{{ConfigMapRef: &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+		Key:                  "values.yaml"}}}
+	_, err := getComponentOverrides(context.TODO(), zap.S(), c, vz, "keycloak")
+	asserts.Error(err, "getComponentOverrides should fail for a missing ConfigMap")
+
+	vz.Spec.Components.Keycloak.Overrides = []vzapi.Overrides{{SecretRef: &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "keycloak-secret"},
+		Key:                  "values.yaml"}}}
+	_, err = getComponentOverrides(context.TODO(), zap.S(), c, vz, "keycloak")
+	asserts.EqualError(err, "Failed getting the overrides of component keycloak: key values.yaml not found in Secret verrazzano/keycloak-secret")
+}
+
+// TestGetValuesKeys tests the getValuesKeys function
+// GIVEN YAML values of overrides
+//  WHEN the keys of the values are returned
+//  THEN the keys of the merged values are returned with null leaves
+func TestGetValuesKeys(t *testing.T) {
+	asserts := assert.New(t)
+
+	keys, err := getValuesKeys([]string{"a:\n  b: 1\n  c: secret\nd: 2", "a:\n  e: 3\nd:\n  f: 4"})
+	asserts.NoError(err)
+	asserts.Equal("a:\n  b: null\n  c: null\n  e: null\nd:\n  f: null\n", keys)
+
+	keys, err = getValuesKeys(nil)
+	asserts.NoError(err)
+	asserts.Empty(keys)
+
+	_, err = getValuesKeys([]string{"- not a map"})
+	asserts.Error(err)
+}
+
+// TestOverridesRequests tests the OverridesRequests function
+// GIVEN Verrazzano resources with overrides that reference ConfigMaps and Secrets
+//  WHEN OverridesRequests is called for a ConfigMap or Secret
+//  THEN requests are returned for the resources that reference it in the same namespace
+func TestOverridesRequests(t *testing.T) {
+	asserts := assert.New(t)
+
+	vz := newInstallVerrazzano()
+	vz.Spec.Components.Grafana.Overrides = []vzapi.Overrides{{ConfigMapRef: &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "grafana-overrides"}, Key: "values.yaml"}}}
+	vz.Spec.Components.Keycloak.Overrides = []vzapi.Overrides{{SecretRef: &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "keycloak-overrides"}, Key: "values.yaml"}}}
+	reconciler := newVerrazzanoReconciler(fake.NewFakeClientWithScheme(newInstallScheme(), vz))
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: vz.Namespace, Name: "grafana-overrides"}}
+	requests := reconciler.OverridesRequests(handler.MapObject{Meta: cm, Object: cm})
+	asserts.Equal([]reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}}}, requests)
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: vz.Namespace, Name: "keycloak-overrides"}}
+	requests = reconciler.OverridesRequests(handler.MapObject{Meta: secret, Object: secret})
+	asserts.Len(requests, 1)
+
+	// A ConfigMap with the name of a referenced Secret is not a reference
+	cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: vz.Namespace, Name: "keycloak-overrides"}}
+	asserts.Empty(reconciler.OverridesRequests(handler.MapObject{Meta: cm, Object: cm}))
+
+	// A ConfigMap in another namespace is not a reference
+	cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "grafana-overrides"}}
+	asserts.Empty(reconciler.OverridesRequests(handler.MapObject{Meta: cm, Object: cm}))
+}
@@ -0,0 +1,416 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/semver"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// preflightRetryDelay is the delay before failed preflight checks are run again
+const preflightRetryDelay = 1 * time.Minute
+
+// defaultStorageClassAnnotation is the annotation that marks the default storage class of the cluster
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// preflightCheck is a named check that is run before any component is upgraded
+type preflightCheck struct {
+	name      string
+	checkFunc func(log *zap.SugaredLogger, r *Reconciler, cr *installv1alpha1.Verrazzano) error
+}
+
+// preflightChecks are the checks that are run, in order, before an upgrade
+var preflightChecks = []preflightCheck{
+	{name: "ReleasesDeployed", checkFunc: checkReleasesDeployed},
+	{name: "ChartsAvailable", checkFunc: checkChartsAvailable},
+	{name: "CRDsCompatible", checkFunc: checkCRDsCompatible},
+	{name: "NodeCapacity", checkFunc: checkNodeCapacity},
+	{name: "StorageClasses", checkFunc: checkStorageClasses},
+}
+
+// clusterRequirements are the minimum cluster resources needed by an install profile.  Each node must have at least
+// the node CPU and memory, so that the largest pods of the profile can be scheduled.  The nodes that meet the node
+// minimum must have at least the CPU and memory in total.
+type clusterRequirements struct {
+	nodes      int
+	cpu        resource.Quantity
+	memory     resource.Quantity
+	nodeCPU    resource.Quantity
+	nodeMemory resource.Quantity
+}
+
+// profileRequirements are the minimum allocatable resources of the cluster nodes for each install profile.  The
+// largest pod is the Elasticsearch data node, which requests 1Gi of memory for dev and 4.8Gi for prod.
+var profileRequirements = map[installv1alpha1.ProfileType]clusterRequirements{
+	installv1alpha1.Dev: {nodes: 1, cpu: resource.MustParse("2"), memory: resource.MustParse("8Gi"),
+		nodeCPU: resource.MustParse("1"), nodeMemory: resource.MustParse("2Gi")},
+	installv1alpha1.Prod: {nodes: 1, cpu: resource.MustParse("4"), memory: resource.MustParse("16Gi"),
+		nodeCPU: resource.MustParse("2"), nodeMemory: resource.MustParse("6Gi")},
+}
+
+// crdGVK is the group version kind of the custom resource definitions in the cluster
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+// reconcilePreflight runs the preflight checks and records the result in a PreflightPassed or PreflightFailed
+// condition, with the result of each check in the condition message.  True is returned if all of the checks
+// passed.  Failed checks are run again after a delay, unless the checks are the only thing being done.
+func (r *Reconciler) reconcilePreflight(log *zap.SugaredLogger, cr *installv1alpha1.Verrazzano, preflightOnly bool) (ctrl.Result, bool, error) {
+	var results []string
+	failed := false
+	for _, check := range preflightChecks {
+		if err := check.checkFunc(log, r, cr); err != nil {
+			log.Errorf("Preflight check %s failed: %v", check.name, err)
+			results = append(results, fmt.Sprintf("%s: failed, %v", check.name, err))
+			failed = true
+			continue
+		}
+		results = append(results, fmt.Sprintf("%s: passed", check.name))
+	}
+
+	if !failed {
+		msg := fmt.Sprintf("Preflight checks for the upgrade to version %s passed - %s. %s", cr.Spec.Version,
+			fmtGeneration(cr.Generation), strings.Join(results, "; "))
+		err := r.updateStatus(log, cr, msg, installv1alpha1.PreflightPassed)
+		return ctrl.Result{}, err == nil, err
+	}
+
+	// Only write the failed condition when the result changes, since failed checks are run periodically
+	msg := fmt.Sprintf("Preflight checks for the upgrade to version %s failed - %s. %s", cr.Spec.Version,
+		fmtGeneration(cr.Generation), strings.Join(results, "; "))
+	if !isLastConditionMessage(cr.Status, installv1alpha1.PreflightFailed, msg) {
+		if err := r.updateStatus(log, cr, msg, installv1alpha1.PreflightFailed); err != nil {
+			return ctrl.Result{}, false, err
+		}
+	}
+	if preflightOnly {
+		return ctrl.Result{}, false, nil
+	}
+	return ctrl.Result{Requeue: true, RequeueAfter: preflightRetryDelay}, false, nil
+}
+
+// isPreflightDone returns true if the last condition is the result of the preflight checks for the current generation
+func isPreflightDone(cr *installv1alpha1.Verrazzano) bool {
+	l := len(cr.Status.Conditions)
+	if l == 0 {
+		return false
+	}
+	cond := cr.Status.Conditions[l-1]
+	return (cond.Type == installv1alpha1.PreflightPassed || cond.Type == installv1alpha1.PreflightFailed) &&
+		strings.Contains(cond.Message, fmtGeneration(cr.Generation))
+}
+
+// isLastConditionMessage returns true if the last condition matches the condition type and message
+func isLastConditionMessage(st installv1alpha1.VerrazzanoStatus, conditionType installv1alpha1.ConditionType, msg string) bool {
+	return isLastCondition(st, conditionType) && st.Conditions[len(st.Conditions)-1].Message == msg
+}
+
+// checkReleasesDeployed checks that the helm release of every installed component is in the deployed status.
+// Releases that are pending or failed can't be upgraded.
+func checkReleasesDeployed(_ *zap.SugaredLogger, _ *Reconciler, cr *installv1alpha1.Verrazzano) error {
+	var notDeployed []string
+	for _, comp := range component.GetComponents() {
+		info, err := comp.GetReleaseInfo(cr.Namespace)
+		if err != nil {
+			return fmt.Errorf("error getting the release status of component %s: %v", comp.Name(), err)
+		}
+		if info != nil && info.Status != helm.ReleaseStatusDeployed {
+			notDeployed = append(notDeployed, fmt.Sprintf("%s is %s", comp.Name(), info.Status))
+		}
+	}
+	if len(notDeployed) > 0 {
+		return fmt.Errorf("releases not deployed: %s", strings.Join(notDeployed, ", "))
+	}
+	return nil
+}
+
+// checkChartsAvailable checks that the chart of every component exists on disk, and that the verrazzano
+// chart is the target version of the upgrade
+func checkChartsAvailable(_ *zap.SugaredLogger, _ *Reconciler, cr *installv1alpha1.Verrazzano) error {
+	for _, comp := range component.GetComponents() {
+		if _, err := helm.GetChartInfo(comp.GetChartDir()); err != nil {
+			return fmt.Errorf("chart for component %s not found in %s", comp.Name(), comp.GetChartDir())
+		}
+	}
+	if len(cr.Spec.Version) == 0 {
+		return nil
+	}
+	chartVersion, err := installv1alpha1.GetCurrentChartVersion()
+	if err != nil {
+		return err
+	}
+	targetVersion, err := semver.NewSemVersion(cr.Spec.Version)
+	if err != nil {
+		return err
+	}
+	if !chartVersion.IsEqualTo(targetVersion) {
+		return fmt.Errorf("chart version %s does not match the target version %s", chartVersion.ToString(), cr.Spec.Version)
+	}
+	return nil
+}
+
+// checkCRDsCompatible checks that the CRDs in the crds directory of each chart match the CRDs in the cluster.
+// Helm doesn't update the CRDs in the crds directory of a chart when a release is upgraded, so the schema of each
+// version of the chart CRD must be the same as the schema of the installed CRD.  Every version that has objects
+// stored in etcd must also still be defined by the chart CRD, otherwise the stored objects can no longer be read.
+func checkCRDsCompatible(_ *zap.SugaredLogger, r *Reconciler, _ *installv1alpha1.Verrazzano) error {
+	for _, comp := range component.GetComponents() {
+		crds, err := readChartCRDs(filepath.Join(comp.GetChartDir(), "crds"))
+		if err != nil {
+			return fmt.Errorf("error reading the CRDs of component %s: %v", comp.Name(), err)
+		}
+		for _, crd := range crds {
+			existing := unstructured.Unstructured{}
+			existing.SetGroupVersionKind(crdGVK)
+			err := r.Get(context.TODO(), types.NamespacedName{Name: crd.GetName()}, &existing)
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			newSchemas := getCRDSchemas(crd)
+			stored, _, _ := unstructured.NestedStringSlice(existing.Object, "status", "storedVersions")
+			for _, version := range stored {
+				if _, ok := newSchemas[version]; !ok {
+					return fmt.Errorf("CRD %s of component %s removes stored version %s", crd.GetName(), comp.Name(), version)
+				}
+			}
+			installedSchemas := getCRDSchemas(existing)
+			for _, version := range sortedKeys(newSchemas) {
+				installed, ok := installedSchemas[version]
+				if !ok {
+					return fmt.Errorf("CRD %s of component %s adds version %s, which is not installed by the upgrade", crd.GetName(), comp.Name(), version)
+				}
+				if installed != newSchemas[version] {
+					return fmt.Errorf("CRD %s of component %s changes the schema of version %s, which is not updated by the upgrade", crd.GetName(), comp.Name(), version)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// readChartCRDs reads the CRDs from the YAML files in the directory.  Other resources in the files are ignored.
+func readChartCRDs(dir string) ([]unstructured.Unstructured, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	var crds []unstructured.Unstructured
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		decoder := yaml.NewYAMLOrJSONDecoder(f, 4096)
+		for {
+			obj := unstructured.Unstructured{}
+			err = decoder.Decode(&obj.Object)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				f.Close()
+				return nil, fmt.Errorf("%s: %v", file, err)
+			}
+			if obj.GetKind() == crdGVK.Kind {
+				crds = append(crds, obj)
+			}
+		}
+		f.Close()
+	}
+	return crds, nil
+}
+
+// getCRDSchemas returns the JSON of the OpenAPI schema of each version of a v1 or v1beta1 CRD.  The schema of a
+// v1beta1 CRD can be shared by all of the versions.  The JSON of the schema is compared, so that the numbers
+// decoded from YAML and from the API server compare equal.
+func getCRDSchemas(crd unstructured.Unstructured) map[string]string {
+	shared, _, _ := unstructured.NestedFieldNoCopy(crd.Object, "spec", "validation", "openAPIV3Schema")
+	schemas := make(map[string]string)
+	if version, found, _ := unstructured.NestedString(crd.Object, "spec", "version"); found {
+		schemas[version] = marshalSchema(shared)
+	}
+	list, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range list {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, ok := m["name"].(string)
+		if !ok {
+			continue
+		}
+		schema, found, _ := unstructured.NestedFieldNoCopy(m, "schema", "openAPIV3Schema")
+		if !found {
+			schema = shared
+		}
+		schemas[name] = marshalSchema(schema)
+	}
+	return schemas
+}
+
+// marshalSchema returns the JSON of a CRD schema, or an empty string if there is no schema
+func marshalSchema(schema interface{}) string {
+	if schema == nil {
+		return ""
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// sortedKeys returns the keys of the map in order, so that the checks report the same version each time
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkNodeCapacity checks that the nodes of the cluster meet the requirements of the install profile.  The nodes
+// that are smaller than the node minimum can't run the largest pods, so their resources are not counted.  The
+// largest node is reported when the check fails.
+func checkNodeCapacity(_ *zap.SugaredLogger, r *Reconciler, cr *installv1alpha1.Verrazzano) error {
+	profile := getProfile(cr)
+	req, ok := profileRequirements[profile]
+	if !ok {
+		return nil
+	}
+	nodes := corev1.NodeList{}
+	if err := r.List(context.TODO(), &nodes); err != nil {
+		return err
+	}
+	cpu := resource.Quantity{}
+	memory := resource.Quantity{}
+	count := 0
+	var largest *corev1.Node
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if largest == nil || node.Status.Allocatable.Memory().Cmp(*largest.Status.Allocatable.Memory()) > 0 {
+			largest = node
+		}
+		if node.Status.Allocatable.Cpu().Cmp(req.nodeCPU) < 0 || node.Status.Allocatable.Memory().Cmp(req.nodeMemory) < 0 {
+			continue
+		}
+		count++
+		cpu.Add(*node.Status.Allocatable.Cpu())
+		memory.Add(*node.Status.Allocatable.Memory())
+	}
+	if count < req.nodes {
+		return fmt.Errorf("%d of %d nodes have %s CPU and %s memory allocatable, %s profile needs %d%s", count,
+			len(nodes.Items), req.nodeCPU.String(), req.nodeMemory.String(), profile, req.nodes, fmtLargestNode(largest))
+	}
+	if cpu.Cmp(req.cpu) < 0 {
+		return fmt.Errorf("%s CPU allocatable, %s profile needs %s%s", cpu.String(), profile, req.cpu.String(), fmtLargestNode(largest))
+	}
+	if memory.Cmp(req.memory) < 0 {
+		return fmt.Errorf("%s memory allocatable, %s profile needs %s%s", memory.String(), profile, req.memory.String(), fmtLargestNode(largest))
+	}
+	return nil
+}
+
+// fmtLargestNode returns a description of the allocatable resources of the largest node
+func fmtLargestNode(node *corev1.Node) string {
+	if node == nil {
+		return ""
+	}
+	return fmt.Sprintf(", the largest node %s has %s CPU and %s memory allocatable", node.Name,
+		node.Status.Allocatable.Cpu().String(), node.Status.Allocatable.Memory().String())
+}
+
+// checkStorageClasses checks that the storage classes used by the persistent volumes of the install exist.
+// Volumes that don't name a storage class need a default storage class.  When no volume source is specified,
+// the prod profile uses persistent volumes with the default storage class.
+func checkStorageClasses(_ *zap.SugaredLogger, r *Reconciler, cr *installv1alpha1.Verrazzano) error {
+	needDefault := false
+	var names []string
+	for _, source := range []*corev1.VolumeSource{cr.Spec.DefaultVolumeSource, cr.Spec.Components.Keycloak.MySQL.VolumeSource} {
+		if source == nil {
+			needDefault = needDefault || getProfile(cr) == installv1alpha1.Prod
+			continue
+		}
+		if source.PersistentVolumeClaim == nil {
+			continue
+		}
+		template, found := findVolumeClaimSpecTemplate(cr, source.PersistentVolumeClaim.ClaimName)
+		if !found {
+			return fmt.Errorf("no VolumeClaimTemplate found for %s", source.PersistentVolumeClaim.ClaimName)
+		}
+		if template.StorageClassName == nil || len(*template.StorageClassName) == 0 {
+			needDefault = true
+			continue
+		}
+		names = append(names, *template.StorageClassName)
+	}
+	if !needDefault && len(names) == 0 {
+		return nil
+	}
+
+	classes := storagev1.StorageClassList{}
+	if err := r.List(context.TODO(), &classes); err != nil {
+		return err
+	}
+	found := make(map[string]bool)
+	hasDefault := false
+	for _, class := range classes.Items {
+		found[class.Name] = true
+		if class.Annotations[defaultStorageClassAnnotation] == "true" {
+			hasDefault = true
+		}
+	}
+	if needDefault && !hasDefault {
+		return fmt.Errorf("no default storage class found")
+	}
+	for _, name := range names {
+		if !found[name] {
+			return fmt.Errorf("storage class %s not found", name)
+		}
+	}
+	return nil
+}
+
+// findVolumeClaimSpecTemplate returns the PVC spec of the named volume claim spec template
+func findVolumeClaimSpecTemplate(cr *installv1alpha1.Verrazzano, name string) (*corev1.PersistentVolumeClaimSpec, bool) {
+	for i, template := range cr.Spec.VolumeClaimSpecTemplates {
+		if template.Name == name {
+			return &cr.Spec.VolumeClaimSpecTemplates[i].Spec, true
+		}
+	}
+	return nil, false
+}
+
+// getProfile returns the install profile, which defaults to prod
+func getProfile(cr *installv1alpha1.Verrazzano) installv1alpha1.ProfileType {
+	if len(cr.Spec.Profile) == 0 {
+		return installv1alpha1.Prod
+	}
+	return cr.Spec.Profile
+}
@@ -0,0 +1,318 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// preflightRunner is used to test the preflight checks without running the helm command.  The status of
+// the pending release is reported as pending-upgrade and the status of the failed release as failed, all
+// other releases are deployed.
+type preflightRunner struct {
+	upgradeRunner
+	pendingRelease string
+	failedRelease  string
+}
+
+// TestPreflightPassed tests the reconcileUpgrade method for the following use case
+// GIVEN a request to reconcile a verrazzano resource that needs to be upgraded
+// WHEN all of the preflight checks pass
+// THEN ensure that the PreflightPassed condition is added with the result of each check and the upgrade is done
+func TestPreflightPassed(t *testing.T) {
+	asserts := assert.New(t)
+	runner, cleanup := setupPreflight("")
+	defer cleanup()
+
+	vz := newPreflightVerrazzano()
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, newPreflightNode("8", "32Gi"), newPreflightStorageClass("standard", true))
+	reconciler := newVerrazzanoReconciler(c)
+	result, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+
+	asserts.True(runner.upgraded["keycloak"], "keycloak was not upgraded")
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, vz))
+	asserts.Len(vz.Status.Conditions, 4, "Incorrect number of conditions")
+	asserts.Equal(vzapi.PreflightPassed, vz.Status.Conditions[1].Type, "Incorrect condition")
+	for _, check := range preflightChecks {
+		asserts.Contains(vz.Status.Conditions[1].Message, check.name+": passed", "Check result missing from the condition")
+	}
+	asserts.Equal(vzapi.UpgradeStarted, vz.Status.Conditions[2].Type, "Incorrect condition")
+	asserts.Equal(vzapi.UpgradeComplete, vz.Status.Conditions[3].Type, "Incorrect condition")
+}
+
+// TestPreflightFailed tests the reconcileUpgrade method for the following use case
+// GIVEN a request to reconcile a verrazzano resource that needs to be upgraded
+// WHEN a release is not deployed and the nodes don't have enough capacity
+// THEN ensure that the PreflightFailed condition is added, no component is upgraded and the checks are requeued
+func TestPreflightFailed(t *testing.T) {
+	asserts := assert.New(t)
+	runner, cleanup := setupPreflight("keycloak")
+	defer cleanup()
+
+	vz := newPreflightVerrazzano()
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, newPreflightNode("1", "4Gi"), newPreflightStorageClass("standard", true))
+	reconciler := newVerrazzanoReconciler(c)
+	result, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.True(result.Requeue)
+	asserts.Equal(preflightRetryDelay, result.RequeueAfter)
+	asserts.Empty(runner.upgraded, "No component should be upgraded")
+
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, vz))
+	asserts.Len(vz.Status.Conditions, 2, "Incorrect number of conditions")
+	cond := vz.Status.Conditions[1]
+	asserts.Equal(vzapi.PreflightFailed, cond.Type, "Incorrect condition")
+	asserts.Contains(cond.Message, "ReleasesDeployed: failed, releases not deployed: keycloak is pending-upgrade")
+	asserts.Contains(cond.Message, "ChartsAvailable: passed")
+	asserts.Contains(cond.Message, "NodeCapacity: failed")
+	asserts.Contains(cond.Message, "StorageClasses: passed")
+	asserts.Equal(vzapi.Failed, vz.Status.State, "Incorrect state")
+
+	// The same failure is not added again when the checks are run again
+	_, err = reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, vz))
+	asserts.Len(vz.Status.Conditions, 2, "Incorrect number of conditions")
+}
+
+// TestPreflightOnly tests the reconcileUpgrade method for the following use case
+// GIVEN a request to reconcile a verrazzano resource that needs to be upgraded
+// WHEN the upgrade policy is preflight only
+// THEN ensure that the checks are run once and no component is upgraded
+func TestPreflightOnly(t *testing.T) {
+	asserts := assert.New(t)
+	runner, cleanup := setupPreflight("")
+	defer cleanup()
+
+	vz := newPreflightVerrazzano()
+	vz.Spec.UpgradePolicy.PreflightOnly = true
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, newPreflightNode("8", "32Gi"), newPreflightStorageClass("standard", true))
+	reconciler := newVerrazzanoReconciler(c)
+	for i := 0; i < 2; i++ {
+		result, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+		asserts.NoError(err)
+		asserts.False(result.Requeue)
+	}
+	asserts.Empty(runner.upgraded, "No component should be upgraded")
+
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, vz))
+	asserts.Len(vz.Status.Conditions, 2, "Incorrect number of conditions")
+	asserts.Equal(vzapi.PreflightPassed, vz.Status.Conditions[1].Type, "Incorrect condition")
+}
+
+// TestPreflightUpgradeRetry tests the reconcileUpgrade method for the following use case
+// GIVEN a request to reconcile a verrazzano resource that needs to be upgraded
+// WHEN the upgrade of a component fails and leaves its release in the failed status, and the upgrade is retried
+// THEN ensure that the preflight checks are not run again and the upgrade resumes from the component that failed
+func TestPreflightUpgradeRetry(t *testing.T) {
+	asserts := assert.New(t)
+	runner, cleanup := setupPreflight("")
+	defer cleanup()
+
+	runner.failRelease = "keycloak"
+	vz := newPreflightVerrazzano()
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, newPreflightNode("8", "32Gi"), newPreflightStorageClass("standard", true))
+	reconciler := newVerrazzanoReconciler(c)
+	_, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, vz))
+	asserts.True(isLastCondition(vz.Status, vzapi.UpgradeFailed), "Upgrade should have failed")
+
+	runner.failRelease = ""
+	runner.failedRelease = "keycloak"
+	runner.upgraded = map[string]bool{}
+	result, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+	asserts.True(runner.upgraded["keycloak"], "keycloak was not upgraded")
+	asserts.False(runner.upgraded["istio-base"], "istio-base should not be upgraded again")
+
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, vz))
+	asserts.True(isLastCondition(vz.Status, vzapi.UpgradeComplete), "Upgrade should be complete")
+	for _, cond := range vz.Status.Conditions {
+		asserts.NotEqual(vzapi.PreflightFailed, cond.Type, "The preflight checks should not fail")
+	}
+}
+
+// TestCheckStorageClasses tests the checkStorageClasses function for the following use cases
+// GIVEN a verrazzano resource that uses persistent volumes
+// WHEN the storage classes are checked
+// THEN ensure that the named storage class and a default storage class are required
+func TestCheckStorageClasses(t *testing.T) {
+	asserts := assert.New(t)
+	log := zap.S()
+	storageClass := "fast"
+
+	vz := newPreflightVerrazzano()
+	vz.Spec.Profile = vzapi.Dev
+	reconciler := newVerrazzanoReconciler(fake.NewFakeClientWithScheme(newInstallScheme()))
+	asserts.NoError(checkStorageClasses(log, &reconciler, vz), "Dev profile doesn't need a storage class")
+
+	vz.Spec.Profile = vzapi.Prod
+	asserts.EqualError(checkStorageClasses(log, &reconciler, vz), "no default storage class found")
+
+	vz.Spec.DefaultVolumeSource = &corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "vmi"}}
+	vz.Spec.Components.Keycloak.MySQL.VolumeSource = &corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+	asserts.EqualError(checkStorageClasses(log, &reconciler, vz), "no VolumeClaimTemplate found for vmi")
+
+	vz.Spec.VolumeClaimSpecTemplates = []vzapi.VolumeClaimSpecTemplate{
+		{ObjectMeta: metav1.ObjectMeta{Name: "vmi"}, Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass}},
+	}
+	reconciler = newVerrazzanoReconciler(fake.NewFakeClientWithScheme(newInstallScheme(), newPreflightStorageClass("standard", true)))
+	asserts.EqualError(checkStorageClasses(log, &reconciler, vz), "storage class fast not found")
+
+	reconciler = newVerrazzanoReconciler(fake.NewFakeClientWithScheme(newInstallScheme(), newPreflightStorageClass("fast", false)))
+	asserts.NoError(checkStorageClasses(log, &reconciler, vz))
+}
+
+// TestCheckCRDsCompatible tests the checkCRDsCompatible function for the following use cases
+// GIVEN a cluster with a CRD that is included in a chart
+// WHEN the installed CRD is checked against the chart CRD
+// THEN ensure that an error is returned if a stored version is removed or the schema of a version is changed by
+//      the chart CRD, since helm doesn't update the CRDs of a chart
+func TestCheckCRDsCompatible(t *testing.T) {
+	asserts := assert.New(t)
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config", ThirdpartyChartsDir: "../../thirdparty/charts"})
+
+	reconciler := newVerrazzanoReconciler(fake.NewFakeClientWithScheme(newInstallScheme(),
+		newPreflightCRD(t, "verrazzanomanagedclusters.verrazzano.io", "v1beta1")))
+	asserts.NoError(checkCRDsCompatible(zap.S(), &reconciler, newPreflightVerrazzano()))
+
+	reconciler = newVerrazzanoReconciler(fake.NewFakeClientWithScheme(newInstallScheme(),
+		newPreflightCRD(t, "verrazzanomanagedclusters.verrazzano.io", "v1alpha1", "v1beta1")))
+	err := checkCRDsCompatible(zap.S(), &reconciler, newPreflightVerrazzano())
+	asserts.EqualError(err, "CRD verrazzanomanagedclusters.verrazzano.io of component verrazzano removes stored version v1alpha1")
+
+	crd := newPreflightCRD(t, "verrazzanomanagedclusters.verrazzano.io", "v1beta1")
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	_ = unstructured.SetNestedField(versions[0].(map[string]interface{}), "removed", "schema", "openAPIV3Schema", "description")
+	_ = unstructured.SetNestedSlice(crd.Object, versions, "spec", "versions")
+	reconciler = newVerrazzanoReconciler(fake.NewFakeClientWithScheme(newInstallScheme(), crd))
+	err = checkCRDsCompatible(zap.S(), &reconciler, newPreflightVerrazzano())
+	asserts.EqualError(err, "CRD verrazzanomanagedclusters.verrazzano.io of component verrazzano changes the schema of version v1beta1, which is not updated by the upgrade")
+}
+
+// TestCheckNodeCapacity tests the checkNodeCapacity function for the following use cases
+// GIVEN a cluster with nodes
+// WHEN the nodes are checked against the requirements of the prod profile
+// THEN ensure that the nodes that are smaller than the node minimum are not counted, and that the largest node is
+//      reported when the check fails
+func TestCheckNodeCapacity(t *testing.T) {
+	asserts := assert.New(t)
+	vz := newPreflightVerrazzano()
+
+	reconciler := newVerrazzanoReconciler(fake.NewFakeClientWithScheme(newInstallScheme(), newPreflightNode("8", "32Gi")))
+	asserts.NoError(checkNodeCapacity(zap.S(), &reconciler, vz))
+
+	// The total is enough, but none of the nodes can run the largest pods
+	var nodes []runtime.Object
+	for i := 0; i < 4; i++ {
+		node := newPreflightNode("2", "4Gi").(*corev1.Node)
+		node.Name = fmt.Sprintf("node%d", i+1)
+		nodes = append(nodes, node)
+	}
+	reconciler = newVerrazzanoReconciler(fake.NewFakeClientWithScheme(newInstallScheme(), nodes...))
+	err := checkNodeCapacity(zap.S(), &reconciler, vz)
+	asserts.EqualError(err, "0 of 4 nodes have 2 CPU and 6Gi memory allocatable, prod profile needs 1, the largest node node1 has 2 CPU and 4Gi memory allocatable")
+
+	// The small nodes are not counted in the total
+	large := newPreflightNode("4", "8Gi").(*corev1.Node)
+	large.Name = "large"
+	reconciler = newVerrazzanoReconciler(fake.NewFakeClientWithScheme(newInstallScheme(), append(nodes, large)...))
+	err = checkNodeCapacity(zap.S(), &reconciler, vz)
+	asserts.EqualError(err, "8Gi memory allocatable, prod profile needs 16Gi, the largest node large has 4 CPU and 8Gi memory allocatable")
+}
+
+// setupPreflight enables the preflight checks and sets the helm command runner for a test.  The returned
+// function restores the previous settings.
+func setupPreflight(pendingRelease string) (*preflightRunner, func()) {
+	prevConfig := config.Get()
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config", ThirdpartyChartsDir: "../../thirdparty/charts",
+		UpgradePreflightEnabled: true})
+	runner := &preflightRunner{upgradeRunner: upgradeRunner{upgraded: map[string]bool{}}, pendingRelease: pendingRelease}
+	helm.SetCmdRunner(runner)
+	component.UpgradePrehooksEnabled = false
+	return runner, func() {
+		config.Set(prevConfig)
+		helm.SetDefaultRunner()
+		component.UpgradePrehooksEnabled = true
+	}
+}
+
+// newPreflightVerrazzano creates a Verrazzano resource that needs to be upgraded to the version of the chart
+func newPreflightVerrazzano() *vzapi.Verrazzano {
+	vz := newUpgradeVerrazzano()
+	vz.Spec.Version = "v0.10.0"
+	return vz
+}
+
+// newPreflightNode creates a node with the allocatable resources
+func newPreflightNode(cpu string, memory string) runtime.Object {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+// newPreflightStorageClass creates a storage class, which is optionally the default storage class
+func newPreflightStorageClass(name string, isDefault bool) runtime.Object {
+	class := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if isDefault {
+		class.Annotations = map[string]string{defaultStorageClassAnnotation: "true"}
+	}
+	return class
+}
+
+// newPreflightCRD creates an installed CRD from the CRD of the verrazzano chart, with the stored versions
+func newPreflightCRD(t *testing.T, name string, storedVersions ...string) *unstructured.Unstructured {
+	crds, err := readChartCRDs("../../helm_config/charts/verrazzano/crds")
+	assert.NoError(t, err)
+	for i := range crds {
+		if crds[i].GetName() == name {
+			crd := crds[i].DeepCopy()
+			_ = unstructured.SetNestedStringSlice(crd.Object, storedVersions, "status", "storedVersions")
+			return crd
+		}
+	}
+	assert.Fail(t, "CRD not found in the verrazzano chart", name)
+	return nil
+}
+
+// Run reports the pending release as pending-upgrade and the failed release as failed, otherwise the upgrade
+// runner is used
+func (r *preflightRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	if cmd.Args[1] == "status" && cmd.Args[2] == r.pendingRelease {
+		return []byte(`{"version":4,"info":{"status":"pending-upgrade"},"chart":{"metadata":{"version":"0.0.0"}}}`), []byte(""), nil
+	}
+	if cmd.Args[1] == "status" && cmd.Args[2] == r.failedRelease {
+		return []byte(`{"version":4,"info":{"status":"failed"},"chart":{"metadata":{"version":"0.0.0"}}}`), []byte(""), nil
+	}
+	return r.upgradeRunner.Run(cmd)
+}
@@ -0,0 +1,329 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"go.uber.org/zap"
+	adminv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// applicationResourcesRequeueDelay is the delay before checking again that the application resources were deleted
+const applicationResourcesRequeueDelay = 10 * time.Second
+
+// applicationFinalizerTimeout is how long the finalizers of an application resource that is being deleted are
+// left to be processed before they are removed
+const applicationFinalizerTimeout = 5 * time.Minute
+
+// applicationResourceGVKs are the application resources that are deleted before the components are uninstalled,
+// while the operators that process their finalizers are still running
+var applicationResourceGVKs = []schema.GroupVersionKind{
+	{Group: "core.oam.dev", Version: "v1alpha2", Kind: "ApplicationConfiguration"},
+	{Group: "core.oam.dev", Version: "v1alpha2", Kind: "Component"},
+}
+
+// uninstallWebhooks are the webhook configurations that are created by the components at runtime, so they are
+// not deleted when the helm releases are uninstalled
+var uninstallWebhooks = []runtime.Object{
+	&adminv1beta1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector"}},
+	&adminv1beta1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "istiod-istio-system"}},
+}
+
+// uninstallCRDGroups are the API groups of the custom resource definitions that are deleted after the components
+// are uninstalled.  The API groups of sub-domains are also deleted.
+var uninstallCRDGroups = []string{
+	"istio.io",
+	"cert-manager.io",
+	"cattle.io",
+	"monitoring.coreos.com",
+	"oam.dev",
+	"verrazzano.io",
+	"coherence.oracle.com",
+	"weblogic.oracle",
+}
+
+// operatorCRDGroups are the API groups of the custom resource definitions that are installed with the operator,
+// which are not deleted
+var operatorCRDGroups = []string{
+	installv1alpha1.SchemeGroupVersion.Group,
+	"clusters.verrazzano.io",
+}
+
+// uninstallNamespaces are the namespaces of the components that are deleted after the components are uninstalled.
+// The namespaces that have the verrazzano namespace label are also deleted.
+var uninstallNamespaces = []string{
+	"istio-system",
+	"ingress-nginx",
+	"cert-manager",
+	"cattle-system",
+	"keycloak",
+	"verrazzano-system",
+}
+
+// verrazzanoNamespaceLabels are the labels of the namespaces that are created by the Verrazzano components
+var verrazzanoNamespaceLabels = client.MatchingLabels{"k8s-app": "verrazzano.io"}
+
+// Needed for unit testing
+var finalizeNamespaceFunc = finalizeNamespace
+
+// reconcileComponentUninstall uninstalls the Verrazzano components from the operator.  The application resources
+// are deleted first, then the components are uninstalled in the reverse order they are installed, and finally the
+// webhooks, custom resource definitions and namespaces that are left behind are deleted.  The finalizer is removed
+// when the uninstall completes.  A failed uninstall is retried unless the uninstall policy removes the finalizer.
+func (r *Reconciler) reconcileComponentUninstall(ctx context.Context, log *zap.SugaredLogger, cr *installv1alpha1.Verrazzano) (ctrl.Result, error) {
+	if !containsString(cr.ObjectMeta.Finalizers, finalizerName) {
+		return ctrl.Result{}, nil
+	}
+	if isUninstallFinished(log, cr) {
+		return ctrl.Result{}, r.removeFinalizer(ctx, log, cr)
+	}
+
+	// Only write the uninstall started message once
+	if !hasCondition(cr.Status, installv1alpha1.UninstallStarted) {
+		if err := r.updateStatus(log, cr, "Verrazzano uninstall in progress", installv1alpha1.UninstallStarted); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if r.DryRun {
+		log.Info("Dry run enabled, skipping uninstall")
+		return ctrl.Result{}, nil
+	}
+
+	deleted, err := r.deleteApplicationResources(ctx, log)
+	if err != nil {
+		return ctrl.Result{}, r.uninstallFailed(ctx, log, cr, "", err)
+	}
+	if !deleted {
+		log.Info("Waiting for the application resources to be deleted, requeuing")
+		return ctrl.Result{Requeue: true, RequeueAfter: applicationResourcesRequeueDelay}, nil
+	}
+
+	comps := component.GetComponents()
+	for i := len(comps) - 1; i >= 0; i-- {
+		comp := comps[i]
+		info, err := comp.GetReleaseInfo(cr.Namespace)
+		if err != nil {
+			return ctrl.Result{}, r.uninstallFailed(ctx, log, cr, comp.Name(), err)
+		}
+		if info == nil {
+			setComponentState(cr, comp.Name(), installv1alpha1.CompStateNotInstalled, nil)
+			continue
+		}
+		setComponentState(cr, comp.Name(), installv1alpha1.CompStateUninstalling, nil)
+		if err := r.updateComponentStatus(log, cr); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Infof("Uninstalling component %s", comp.Name())
+		if err := comp.Uninstall(log, r, cr.Namespace); err != nil {
+			return ctrl.Result{}, r.uninstallFailed(ctx, log, cr, comp.Name(), err)
+		}
+		setComponentState(cr, comp.Name(), installv1alpha1.CompStateNotInstalled, nil)
+	}
+
+	if err := r.deleteClusterResources(ctx, log); err != nil {
+		return ctrl.Result{}, r.uninstallFailed(ctx, log, cr, "", err)
+	}
+
+	if err := r.updateStatus(log, cr, "Verrazzano uninstall completed successfully", installv1alpha1.UninstallComplete); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, r.removeFinalizer(ctx, log, cr)
+}
+
+// uninstallFailed records a failed uninstall in the status.  If the uninstall policy removes the finalizer on
+// failure, the finalizer is removed.  Otherwise the error is returned so that the uninstall is retried.
+func (r *Reconciler) uninstallFailed(ctx context.Context, log *zap.SugaredLogger, cr *installv1alpha1.Verrazzano, compName string, err error) error {
+	msg := fmt.Sprintf("Error uninstalling Verrazzano - %s.  Error is %s", fmtGeneration(cr.Generation), err.Error())
+	if len(compName) > 0 {
+		setComponentState(cr, compName, installv1alpha1.CompStateFailed, err)
+		msg = fmt.Sprintf("Error uninstalling component %s - %s.  Error is %s", compName, fmtGeneration(cr.Generation), err.Error())
+	}
+	log.Error(msg)
+
+	// Only add the failed condition once, so that the retries don't add a condition each time
+	if isLastCondition(cr.Status, installv1alpha1.UninstallFailed) {
+		if err := r.updateComponentStatus(log, cr); err != nil {
+			return err
+		}
+	} else if err := r.updateStatus(log, cr, msg, installv1alpha1.UninstallFailed); err != nil {
+		return err
+	}
+	if cr.Spec.UninstallPolicy.RemoveFinalizerOnFailure {
+		return r.removeFinalizer(ctx, log, cr)
+	}
+	return err
+}
+
+// deleteApplicationResources deletes the application resources in all namespaces.  True is returned if all of
+// the resources are gone.  The finalizers of the resources that have been deleting for longer than the timeout
+// are removed, so that the uninstall is not blocked by finalizers that can't be processed.
+func (r *Reconciler) deleteApplicationResources(ctx context.Context, log *zap.SugaredLogger) (bool, error) {
+	deleted := true
+	for _, gvk := range applicationResourceGVKs {
+		list := unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(schema.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind + "List"})
+		if err := r.List(ctx, &list); err != nil {
+			// The resource type doesn't exist if the component that defines it is not installed
+			if meta.IsNoMatchError(err) || errors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		for i := range list.Items {
+			item := &list.Items[i]
+			deleted = false
+			if item.GetDeletionTimestamp() == nil {
+				log.Infof("Deleting %s %s/%s", gvk.Kind, item.GetNamespace(), item.GetName())
+				if err := r.Delete(ctx, item); err != nil && !errors.IsNotFound(err) {
+					return false, err
+				}
+				continue
+			}
+			if len(item.GetFinalizers()) > 0 && time.Since(item.GetDeletionTimestamp().Time) > applicationFinalizerTimeout {
+				log.Infof("Removing the finalizers of %s %s/%s", gvk.Kind, item.GetNamespace(), item.GetName())
+				item.SetFinalizers(nil)
+				if err := r.Update(ctx, item); err != nil && !errors.IsNotFound(err) {
+					return false, err
+				}
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// deleteClusterResources deletes the webhook configurations, custom resource definitions and namespaces that are
+// left behind after the components are uninstalled
+func (r *Reconciler) deleteClusterResources(ctx context.Context, log *zap.SugaredLogger) error {
+	for _, webhook := range uninstallWebhooks {
+		webhook = webhook.DeepCopyObject()
+		if accessor, err := meta.Accessor(webhook); err == nil {
+			log.Infof("Deleting webhook configuration %s", accessor.GetName())
+		}
+		if err := r.Delete(ctx, webhook); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	if err := r.deleteCRDs(ctx, log); err != nil {
+		return err
+	}
+	return r.deleteNamespaces(ctx, log)
+}
+
+// deleteCRDs deletes the custom resource definitions of the uninstall API groups, except for the custom resource
+// definitions of the operator
+func (r *Reconciler) deleteCRDs(ctx context.Context, log *zap.SugaredLogger) error {
+	crds := unstructured.UnstructuredList{}
+	crds.SetGroupVersionKind(schema.GroupVersionKind{Group: crdGVK.Group, Version: crdGVK.Version, Kind: crdGVK.Kind + "List"})
+	if err := r.List(ctx, &crds); err != nil {
+		return err
+	}
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		if !isGroupOf(group, uninstallCRDGroups) || isGroupOf(group, operatorCRDGroups) {
+			continue
+		}
+		log.Infof("Deleting custom resource definition %s", crd.GetName())
+		if err := r.Delete(ctx, crd); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteNamespaces deletes the namespaces of the components.  The finalizers of the namespaces are removed first,
+// since the controllers that process them have been uninstalled.  The kubernetes finalizer of the namespace spec
+// is kept, so that the content of the namespace is deleted by the namespace controller.
+func (r *Reconciler) deleteNamespaces(ctx context.Context, log *zap.SugaredLogger) error {
+	names := append([]string{}, uninstallNamespaces...)
+	nsList := corev1.NamespaceList{}
+	if err := r.List(ctx, &nsList, verrazzanoNamespaceLabels); err != nil {
+		return err
+	}
+	for _, ns := range nsList.Items {
+		names = append(names, ns.Name)
+	}
+
+	for _, name := range names {
+		ns := corev1.Namespace{}
+		err := r.Get(ctx, client.ObjectKey{Name: name}, &ns)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if len(ns.Finalizers) > 0 {
+			ns.Finalizers = nil
+			if err := r.Update(ctx, &ns); err != nil {
+				return err
+			}
+		}
+		if finalizers := kubernetesFinalizers(ns.Spec.Finalizers); len(finalizers) < len(ns.Spec.Finalizers) {
+			// The finalizers of the namespace spec are not changed by an update, they are changed using the
+			// finalize subresource
+			ns.Spec.Finalizers = finalizers
+			if err := finalizeNamespaceFunc(ctx, &ns); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		log.Infof("Deleting namespace %s", name)
+		if err := r.Delete(ctx, &ns); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// kubernetesFinalizers returns the kubernetes finalizer of the finalizers of a namespace spec, if there is one.
+// The kubernetes finalizer is removed by the namespace controller once the content of the namespace is deleted.
+func kubernetesFinalizers(finalizers []corev1.FinalizerName) []corev1.FinalizerName {
+	var result []corev1.FinalizerName
+	for _, finalizer := range finalizers {
+		if finalizer == corev1.FinalizerKubernetes {
+			result = append(result, finalizer)
+		}
+	}
+	return result
+}
+
+// finalizeNamespace updates the finalizers of a namespace spec using the finalize subresource
+func finalizeNamespace(ctx context.Context, ns *corev1.Namespace) error {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = clientset.CoreV1().Namespaces().Finalize(ctx, ns, metav1.UpdateOptions{})
+	return err
+}
+
+// isGroupOf returns true if the API group is one of the groups, or a sub-domain of one of the groups
+func isGroupOf(group string, groups []string) bool {
+	for _, g := range groups {
+		if group == g || strings.HasSuffix(group, "."+g) {
+			return true
+		}
+	}
+	return false
+}
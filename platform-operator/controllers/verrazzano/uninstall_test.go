@@ -0,0 +1,268 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	adminv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestComponentUninstall tests the reconcileComponentUninstall method for the following use case
+// GIVEN a request to reconcile a deleted verrazzano resource
+// WHEN the component install is enabled
+// THEN ensure that the application resources are deleted first, then the components are uninstalled and the
+//      resources they leave behind are deleted, and the finalizer is removed
+func TestComponentUninstall(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config", ComponentInstallEnabled: true})
+	runner := &installRunner{installed: newInstalledReleases()}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+	finalized := map[string][]corev1.FinalizerName{}
+	defer func(f func(ctx context.Context, ns *corev1.Namespace) error) { finalizeNamespaceFunc = f }(finalizeNamespaceFunc)
+	finalizeNamespaceFunc = func(ctx context.Context, ns *corev1.Namespace) error {
+		finalized[ns.Name] = ns.Spec.Finalizers
+		return nil
+	}
+
+	c := fake.NewFakeClientWithScheme(newUninstallScheme(), newDeletedVerrazzano(),
+		newApplicationConfiguration(),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system", Finalizers: []string{"controller.cattle.io/namespace-auth"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cattle-system"},
+			Spec: corev1.NamespaceSpec{Finalizers: []corev1.FinalizerName{corev1.FinalizerKubernetes, "controller.cattle.io/namespace-auth"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "keycloak"},
+			Spec: corev1.NamespaceSpec{Finalizers: []corev1.FinalizerName{corev1.FinalizerKubernetes}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app-ns"}},
+		&adminv1beta1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector"}},
+		newCRD("gateways.networking.istio.io", "networking.istio.io"),
+		newCRD("verrazzanos.install.verrazzano.io", "install.verrazzano.io"),
+		newCRD("deployments.apps.example.com", "apps.example.com"))
+	reconciler := newVerrazzanoReconciler(c)
+
+	// The application resources are deleted before the components are uninstalled
+	result, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.True(result.Requeue, "The uninstall should wait for the application resources to be deleted")
+	asserts.Len(runner.installed, len(component.GetComponents()), "No components should be uninstalled")
+	asserts.False(exists(c, newApplicationConfiguration()), "The application configuration was not deleted")
+
+	result, err = reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+	asserts.Empty(runner.installed, "All of the components should be uninstalled")
+
+	vz := vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, &vz))
+	asserts.NotContains(vz.Finalizers, finalizerName, "The finalizer was not removed")
+	asserts.Equal(vzapi.UninstallComplete, vz.Status.Conditions[len(vz.Status.Conditions)-1].Type, "Incorrect condition")
+	asserts.Equal(vzapi.CompStateNotInstalled, vz.Status.Components["keycloak"].State, "Incorrect component state")
+
+	asserts.False(exists(c, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}}), "istio-system was not deleted")
+	asserts.True(exists(c, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app-ns"}}), "app-ns should not be deleted")
+	asserts.Equal(map[string][]corev1.FinalizerName{"cattle-system": {corev1.FinalizerKubernetes}}, finalized,
+		"Only the finalizers of the cattle-system namespace spec should be removed")
+	asserts.False(exists(c, &adminv1beta1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector"}}),
+		"The webhook configuration was not deleted")
+	asserts.False(exists(c, newCRD("gateways.networking.istio.io", "")), "The istio CRD was not deleted")
+	asserts.True(exists(c, newCRD("verrazzanos.install.verrazzano.io", "")), "The operator CRD should not be deleted")
+	asserts.True(exists(c, newCRD("deployments.apps.example.com", "")), "Other CRDs should not be deleted")
+}
+
+// TestComponentUninstallFailed tests the reconcileComponentUninstall method for the following use case
+// GIVEN a request to reconcile a deleted verrazzano resource
+// WHEN the uninstall of a component fails
+// THEN ensure that the failure is recorded, the finalizer is kept and the uninstall is retried
+func TestComponentUninstallFailed(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config", ComponentInstallEnabled: true})
+	runner := &installRunner{installed: newInstalledReleases(), failRelease: "mysql"}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	c := fake.NewFakeClientWithScheme(newUninstallScheme(), newDeletedVerrazzano())
+	reconciler := newVerrazzanoReconciler(c)
+	_, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.Error(err, "The uninstall should be retried")
+	asserts.False(runner.installed["keycloak"], "keycloak should be uninstalled before mysql")
+	asserts.True(runner.installed["istiod"], "istiod should not be uninstalled")
+
+	vz := vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, &vz))
+	asserts.Contains(vz.Finalizers, finalizerName, "The finalizer should be kept")
+	asserts.Equal(vzapi.UninstallFailed, vz.Status.Conditions[len(vz.Status.Conditions)-1].Type, "Incorrect condition")
+	asserts.Equal(vzapi.Failed, vz.Status.State, "Incorrect state")
+	asserts.Equal(vzapi.CompStateFailed, vz.Status.Components["mysql"].State, "Incorrect component state")
+	asserts.Equal("helm uninstall for release mysql in namespace keycloak failed: Helm Error: failure", vz.Status.Components["mysql"].LastError, "Incorrect component error")
+
+	// The retry completes the uninstall without adding another failed condition
+	conditions := len(vz.Status.Conditions)
+	runner.failRelease = ""
+	_, err = reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.Empty(runner.installed, "All of the components should be uninstalled")
+	vz = vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, &vz))
+	asserts.NotContains(vz.Finalizers, finalizerName, "The finalizer was not removed")
+	asserts.Len(vz.Status.Conditions, conditions+1, "Incorrect number of conditions")
+}
+
+// TestComponentUninstallFailedRemoveFinalizer tests the reconcileComponentUninstall method for the following use case
+// GIVEN a request to reconcile a deleted verrazzano resource that removes the finalizer on failure
+// WHEN the uninstall of a component fails
+// THEN ensure that the failure is recorded and the finalizer is removed
+func TestComponentUninstallFailedRemoveFinalizer(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config", ComponentInstallEnabled: true})
+	runner := &installRunner{installed: newInstalledReleases(), failRelease: "mysql"}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	deleted := newDeletedVerrazzano()
+	deleted.Spec.UninstallPolicy.RemoveFinalizerOnFailure = true
+	c := fake.NewFakeClientWithScheme(newUninstallScheme(), deleted)
+	reconciler := newVerrazzanoReconciler(c)
+	_, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+
+	vz := vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, &vz))
+	asserts.NotContains(vz.Finalizers, finalizerName, "The finalizer was not removed")
+	asserts.Equal(vzapi.UninstallFailed, vz.Status.Conditions[len(vz.Status.Conditions)-1].Type, "Incorrect condition")
+}
+
+// TestUninstallJobRetry tests the Reconcile method for the following use case
+// GIVEN a request to reconcile a deleted verrazzano resource whose uninstall job failed
+// WHEN the uninstall policy keeps the finalizer on failure
+// THEN ensure that the failed uninstall job is deleted and recreated once the retry delay has elapsed
+func TestUninstallJobRetry(t *testing.T) {
+	asserts := assert.New(t)
+
+	deleted := newDeletedVerrazzano()
+	deleted.Status.Conditions = append(deleted.Status.Conditions,
+		vzapi.Condition{Type: vzapi.UninstallStarted},
+		vzapi.Condition{Type: vzapi.UninstallFailed, LastTransitionTime: time.Now().UTC().Format(time.RFC3339)})
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: deleted.Namespace, Name: buildUninstallJobName(deleted.Name)},
+		Status:     batchv1.JobStatus{Failed: 1},
+	}
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), deleted, job)
+	reconciler := newVerrazzanoReconciler(c)
+
+	// The uninstall is not retried before the retry delay has elapsed
+	result, err := reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.True(result.Requeue, "The uninstall should be retried")
+	asserts.True(result.RequeueAfter > installJobDeleteRequeueDelay, "The uninstall should wait for the retry delay")
+	asserts.True(exists(c, job), "The failed uninstall job should not be deleted yet")
+
+	vz := vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, &vz))
+	vz.Status.Conditions[len(vz.Status.Conditions)-1].LastTransitionTime = "2021-01-01T00:00:00Z"
+	asserts.NoError(c.Status().Update(context.TODO(), &vz))
+
+	// The failed uninstall job is deleted, then the uninstall job is recreated
+	result, err = reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.True(result.Requeue, "The uninstall should wait for the job to be deleted")
+	asserts.False(exists(c, &batchv1.Job{ObjectMeta: job.ObjectMeta}), "The failed uninstall job was not deleted")
+
+	_, err = reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	asserts.True(exists(c, &batchv1.Job{ObjectMeta: job.ObjectMeta}), "The uninstall job was not recreated")
+	vz = vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, &vz))
+	asserts.Contains(vz.Finalizers, finalizerName, "The finalizer should be kept")
+	asserts.Equal(vzapi.UninstallStarted, vz.Status.Conditions[len(vz.Status.Conditions)-1].Type, "Incorrect condition")
+
+	// The failure of the retry is recorded
+	retryJob := &batchv1.Job{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: job.Namespace, Name: job.Name}, retryJob))
+	retryJob.Status.Failed = 1
+	asserts.NoError(c.Status().Update(context.TODO(), retryJob))
+	_, err = reconciler.Reconcile(newRequest("verrazzano", "test"))
+	asserts.NoError(err)
+	vz = vzapi.Verrazzano{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: "verrazzano", Name: "test"}, &vz))
+	asserts.Equal(vzapi.UninstallFailed, vz.Status.Conditions[len(vz.Status.Conditions)-1].Type, "The failed retry was not recorded")
+	asserts.Contains(vz.Finalizers, finalizerName, "The finalizer should be kept")
+}
+
+// newUninstallScheme creates a scheme with the install types, and the application resource and custom resource
+// definition types as unstructured types
+func newUninstallScheme() *runtime.Scheme {
+	scheme := newInstallScheme()
+	for _, gvk := range append(applicationResourceGVKs, crdGVK) {
+		scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+	}
+	return scheme
+}
+
+// newDeletedVerrazzano creates a Verrazzano resource that is being deleted
+func newDeletedVerrazzano() *vzapi.Verrazzano {
+	vz := newInstalledVerrazzano()
+	vz.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	return vz
+}
+
+// newInstalledReleases returns the helm releases of all of the components
+func newInstalledReleases() map[string]bool {
+	installed := map[string]bool{}
+	for _, comp := range component.GetComponents() {
+		installed[comp.Name()] = true
+	}
+	return installed
+}
+
+// newApplicationConfiguration creates an OAM application configuration
+func newApplicationConfiguration() *unstructured.Unstructured {
+	appConfig := &unstructured.Unstructured{}
+	appConfig.SetGroupVersionKind(schema.GroupVersionKind{Group: "core.oam.dev", Version: "v1alpha2", Kind: "ApplicationConfiguration"})
+	appConfig.SetNamespace("app-ns")
+	appConfig.SetName("app")
+	return appConfig
+}
+
+// newCRD creates a custom resource definition for the API group
+func newCRD(name string, group string) *unstructured.Unstructured {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(crdGVK)
+	crd.SetName(name)
+	if len(group) > 0 {
+		_ = unstructured.SetNestedField(crd.Object, group, "spec", "group")
+	}
+	return crd
+}
+
+// exists returns true if the object exists
+func exists(c client.Client, obj runtime.Object) bool {
+	key, err := client.ObjectKeyFromObject(obj)
+	if err != nil {
+		return false
+	}
+	return c.Get(context.TODO(), key, obj) == nil
+}
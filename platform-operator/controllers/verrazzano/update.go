@@ -0,0 +1,376 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The name of the cluster issuer created by the install
+const clusterIssuerName = "verrazzano-cluster-issuer"
+
+// componentConfig describes how the configuration of a component in the Verrazzano spec is applied to
+// an installed component.
+type componentConfig struct {
+	// name is the name of the component
+	name string
+
+	// getConfig returns the part of the spec used to configure the component, used to detect changes.  Only the
+	// fields that are used by getArgs and applyFunc are returned, so that other changes don't re-apply the component.
+	getConfig func(spec *installv1alpha1.VerrazzanoSpec) interface{}
+
+	// getArgs returns the helm arguments used to re-apply the component
+	getArgs func(spec *installv1alpha1.VerrazzanoSpec) []helm.SetArg
+
+	// applyFunc is an optional function that applies configuration that is not part of the helm release
+	applyFunc func(ctx context.Context, c client.Client, spec *installv1alpha1.VerrazzanoSpec) error
+}
+
+// componentConfigs lists the components that can be reconfigured after they are installed
+var componentConfigs = []componentConfig{
+	{
+		name: "ingress-controller",
+		getConfig: func(spec *installv1alpha1.VerrazzanoSpec) interface{} {
+			ingress := spec.Components.Ingress
+			return []interface{}{ingress.Type, ingress.NGINXInstallArgs, ingress.Ports}
+		},
+		getArgs: func(spec *installv1alpha1.VerrazzanoSpec) []helm.SetArg {
+			args := getSetArgs(spec.Components.Ingress.NGINXInstallArgs)
+			if len(spec.Components.Ingress.Type) > 0 {
+				args = append(args, helm.SetArg{Name: "controller.service.type", Value: string(spec.Components.Ingress.Type)})
+			}
+			return args
+		},
+		applyFunc: patchIngressPorts,
+	},
+	{
+		name: "istio-ingress",
+		getConfig: func(spec *installv1alpha1.VerrazzanoSpec) interface{} {
+			return spec.Components.Istio.IstioInstallArgs
+		},
+		getArgs: func(spec *installv1alpha1.VerrazzanoSpec) []helm.SetArg {
+			return getSetArgs(spec.Components.Istio.IstioInstallArgs)
+		},
+	},
+	{
+		name: "mysql",
+		getConfig: func(spec *installv1alpha1.VerrazzanoSpec) interface{} {
+			return spec.Components.Keycloak.MySQL.MySQLInstallArgs
+		},
+		getArgs: func(spec *installv1alpha1.VerrazzanoSpec) []helm.SetArg {
+			return getSetArgs(spec.Components.Keycloak.MySQL.MySQLInstallArgs)
+		},
+	},
+	{
+		name: "keycloak",
+		getConfig: func(spec *installv1alpha1.VerrazzanoSpec) interface{} {
+			return spec.Components.Keycloak.KeycloakInstallArgs
+		},
+		getArgs: func(spec *installv1alpha1.VerrazzanoSpec) []helm.SetArg {
+			return getSetArgs(spec.Components.Keycloak.KeycloakInstallArgs)
+		},
+	},
+	{
+		name: "cert-manager",
+		getConfig: func(spec *installv1alpha1.VerrazzanoSpec) interface{} {
+			return spec.Components.CertManager.Certificate
+		},
+		getArgs: func(spec *installv1alpha1.VerrazzanoSpec) []helm.SetArg {
+			ca := spec.Components.CertManager.Certificate.CA
+			if len(ca.ClusterResourceNamespace) == 0 {
+				return nil
+			}
+			return []helm.SetArg{{Name: "clusterResourceNamespace", Value: ca.ClusterResourceNamespace}}
+		},
+		applyFunc: updateClusterIssuer,
+	},
+	{
+		name: "verrazzano",
+		getConfig: func(spec *installv1alpha1.VerrazzanoSpec) interface{} {
+			return installv1alpha1.IsEnabled(spec.Components.Console.Enabled)
+		},
+		getArgs: func(spec *installv1alpha1.VerrazzanoSpec) []helm.SetArg {
+			enabled := installv1alpha1.IsEnabled(spec.Components.Console.Enabled)
+			return []helm.SetArg{{Name: "console.enabled", Value: strconv.FormatBool(enabled)}}
+		},
+	},
+}
+
+// reconcileUpdate compares the spec with the spec that was saved by the last install, upgrade or update,
+// and re-applies the components whose configuration or overrides changed.  The overrides are compared using the
+// saved hash of their values, so a change to the contents of an override ConfigMap or Secret is applied.  Only the
+// affected helm releases are upgraded, using the existing values of the release without the saved overrides and
+// the arguments from the saved spec, and the new overrides and arguments from the spec.  Components that were enabled are installed, and components
+// that were disabled are uninstalled.
+func (r *Reconciler) reconcileUpdate(ctx context.Context, log *zap.SugaredLogger, cr *installv1alpha1.Verrazzano) (ctrl.Result, error) {
+	savedSpec, err := r.getSavedInstallSpec(ctx, log, cr)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Nothing to compare against, save the spec for future updates
+			return ctrl.Result{}, r.saveVerrazzanoSpec(ctx, log, cr)
+		}
+		return ctrl.Result{}, err
+	}
+	return r.updateComponents(ctx, log, cr, savedSpec)
+}
+
+// updateComponents re-applies the components whose configuration or overrides changed since the saved spec,
+// installs the components that were enabled and uninstalls the components that were disabled.  The spec is
+// saved once all of the components are updated.
+func (r *Reconciler) updateComponents(ctx context.Context, log *zap.SugaredLogger, cr *installv1alpha1.Verrazzano, savedSpec *installv1alpha1.VerrazzanoSpec) (ctrl.Result, error) {
+	// A ConfigMap or Secret with overrides that is missing can be created later, so the update is retried
+	overrides, err := getComponentsOverrides(ctx, log, r, cr, getEnabledComponents(&cr.Spec))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	savedOverrides, err := r.getSavedOverrides(ctx, log, cr)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	comps := getChangedComponents(savedSpec, &cr.Spec, savedOverrides, overrides)
+	enabledComps, disabledComps := getToggledComponents(savedSpec, &cr.Spec)
+	if len(comps) == 0 && len(enabledComps) == 0 && len(disabledComps) == 0 {
+		return ctrl.Result{}, nil
+	}
+	if r.DryRun {
+		log.Infof("Dry run enabled, skipping update of components %v", comps)
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.updateEnabledComponents(ctx, log, cr, enabledComps, disabledComps); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	registered := make(map[string]component.Component)
+	for _, comp := range component.GetComponents() {
+		registered[comp.Name()] = comp
+	}
+	for _, cfg := range comps {
+		if !installv1alpha1.IsComponentEnabled(&cr.Spec.Components, cfg.name) {
+			continue
+		}
+		comp := registered[cfg.name]
+		log.Infof("Configuration of component %s changed, re-applying the component", cfg.name)
+		values := helm.ReconfigureValues{
+			PreviousOverrides: r.getPreviousOverrides(ctx, log, cr, savedSpec, savedOverrides, cfg.name),
+			Overrides:         overrides[cfg.name],
+			PreviousSetArgs:   cfg.getArgs(savedSpec),
+			SetArgs:           cfg.getArgs(&cr.Spec),
+		}
+		err = comp.Reconfigure(log, r, cr.Namespace, values)
+		if err == nil && cfg.applyFunc != nil {
+			err = cfg.applyFunc(ctx, r, &cr.Spec)
+		}
+		if err != nil {
+			return ctrl.Result{}, r.updateComponentFailed(log, cr, cfg.name, err)
+		}
+		setComponentReleaseStatus(log, cr, comp)
+	}
+	if err := r.updateComponentStatus(log, cr); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Save the spec so that the components are not re-applied again
+	return ctrl.Result{}, r.saveVerrazzanoSpec(ctx, log, cr)
+}
+
+// getChangedComponents returns the configuration of the components whose configuration or overrides changed
+// between the specs, in the order of the registered components
+func getChangedComponents(oldSpec *installv1alpha1.VerrazzanoSpec, newSpec *installv1alpha1.VerrazzanoSpec, savedOverrides map[string]savedOverrides, overrides map[string][]string) []componentConfig {
+	var changed []componentConfig
+	for _, comp := range component.GetComponents() {
+		cfg, found := getComponentConfig(comp.Name())
+		configChanged := found && !reflect.DeepEqual(cfg.getConfig(oldSpec), cfg.getConfig(newSpec))
+		if configChanged || isOverridesChanged(comp.Name(), oldSpec, newSpec, savedOverrides, overrides) {
+			changed = append(changed, cfg)
+		}
+	}
+	return changed
+}
+
+// getComponentConfig returns the configuration of a component.  False is returned for the components that can
+// only be reconfigured using overrides, along with a configuration that has no arguments.
+func getComponentConfig(name string) (componentConfig, bool) {
+	for _, cfg := range componentConfigs {
+		if cfg.name == name {
+			return cfg, true
+		}
+	}
+	return componentConfig{
+		name: name,
+		getArgs: func(spec *installv1alpha1.VerrazzanoSpec) []helm.SetArg {
+			return nil
+		},
+	}, false
+}
+
+// isOverridesChanged returns true if the overrides of a component changed since they were saved.  The hashes of
+// the values are compared, so a change to the contents of an override ConfigMap or Secret is detected.  The
+// overrides in the specs are compared if the overrides were saved by an older version of the operator.
+func isOverridesChanged(name string, oldSpec *installv1alpha1.VerrazzanoSpec, newSpec *installv1alpha1.VerrazzanoSpec, savedOverrides map[string]savedOverrides, overrides map[string][]string) bool {
+	overridesFunc, ok := componentOverrides[name]
+	if !ok {
+		return false
+	}
+	if saved, ok := savedOverrides[name]; ok {
+		return saved.Hash != hashOverrides(overrides[name])
+	}
+	return !reflect.DeepEqual(overridesFunc(&oldSpec.Components), overridesFunc(&newSpec.Components))
+}
+
+// getPreviousOverrides returns the YAML helm values of the overrides of a component that were applied by the last
+// install, upgrade or update.  The values are only used to remove them from the values of the release, so the
+// saved keys of the values are returned.  The overrides of the saved spec are read if the keys were not saved,
+// and overrides that can no longer be read are skipped.
+func (r *Reconciler) getPreviousOverrides(ctx context.Context, log *zap.SugaredLogger, cr *installv1alpha1.Verrazzano, savedSpec *installv1alpha1.VerrazzanoSpec, savedOverrides map[string]savedOverrides, name string) []string {
+	if saved, ok := savedOverrides[name]; ok {
+		return []string{saved.Keys}
+	}
+	saved := cr.DeepCopy()
+	saved.Spec = *savedSpec
+	overrides, err := getComponentOverrides(ctx, log, r, saved, name)
+	if err != nil {
+		log.Infof("Skipping the previous overrides of component %s: %v", name, err)
+		return nil
+	}
+	return overrides
+}
+
+// getToggledComponents returns the registered components that were enabled and the components that were disabled
+// between the specs.  The disabled components are returned in reverse order, so that they can be uninstalled
+// before the components they depend on.
+func getToggledComponents(oldSpec *installv1alpha1.VerrazzanoSpec, newSpec *installv1alpha1.VerrazzanoSpec) (enabled []component.Component, disabled []component.Component) {
+	for _, comp := range component.GetComponents() {
+		wasEnabled := installv1alpha1.IsComponentEnabled(&oldSpec.Components, comp.Name())
+		isEnabled := installv1alpha1.IsComponentEnabled(&newSpec.Components, comp.Name())
+		if isEnabled && !wasEnabled {
+			enabled = append(enabled, comp)
+		}
+		if wasEnabled && !isEnabled {
+			disabled = append([]component.Component{comp}, disabled...)
+		}
+	}
+	return enabled, disabled
+}
+
+// updateEnabledComponents uninstalls the components that were disabled, then installs the components that were
+// enabled.  The URLs of the instance are updated when a component is enabled or disabled.
+func (r *Reconciler) updateEnabledComponents(ctx context.Context, log *zap.SugaredLogger, cr *installv1alpha1.Verrazzano, enabled []component.Component, disabled []component.Component) error {
+	for _, comp := range disabled {
+		log.Infof("Component %s was disabled, uninstalling the component", comp.Name())
+		if err := comp.Uninstall(log, r, cr.Namespace); err != nil {
+			return r.updateComponentFailed(log, cr, comp.Name(), err)
+		}
+		setComponentState(cr, comp.Name(), installv1alpha1.CompStateDisabled, nil)
+	}
+	for _, comp := range enabled {
+		log.Infof("Component %s was enabled, installing the component", comp.Name())
+		overrides, err := getComponentOverrides(ctx, log, r, cr, comp.Name())
+		if err != nil {
+			return err
+		}
+		installValues, err := getComponentInstallValues(ctx, log, r, cr, comp.Name())
+		if err != nil {
+			return err
+		}
+		overrides = append(overrides, installValues...)
+		if err := installComponent(log, r, cr.Namespace, comp, overrides); err != nil {
+			return r.updateComponentFailed(log, cr, comp.Name(), err)
+		}
+		setComponentReleaseStatus(log, cr, comp)
+	}
+	if len(enabled) > 0 || len(disabled) > 0 {
+		r.setInstanceInfo(log, cr)
+	}
+	return nil
+}
+
+// updateComponentFailed records the error of a component that failed to update, and returns an error so that
+// the update is retried
+func (r *Reconciler) updateComponentFailed(log *zap.SugaredLogger, cr *installv1alpha1.Verrazzano, name string, err error) error {
+	log.Errorf("Error updating component %s: %v", name, err)
+	setComponentState(cr, name, installv1alpha1.CompStateFailed, err)
+	if err := r.updateComponentStatus(log, cr); err != nil {
+		return err
+	}
+	return fmt.Errorf("Error updating component %s: %v", name, err)
+}
+
+// String returns the component name so that lists of component configurations can be logged
+func (c componentConfig) String() string {
+	return c.name
+}
+
+// getSetArgs converts the install args to helm set arguments.  Args without a value are skipped, the same
+// as the install scripts.  A value list is passed using the helm list syntax.
+func getSetArgs(installArgs []installv1alpha1.InstallArgs) []helm.SetArg {
+	var args []helm.SetArg
+	for _, arg := range installArgs {
+		value := arg.Value
+		if len(arg.ValueList) > 0 {
+			value = fmt.Sprintf("{%s}", strings.Join(arg.ValueList, ","))
+		}
+		if len(arg.Name) == 0 || len(value) == 0 {
+			continue
+		}
+		args = append(args, helm.SetArg{Name: arg.Name, Value: value, SetString: arg.SetString})
+	}
+	return args
+}
+
+// patchIngressPorts patches the ports of the NGINX ingress controller service with the ports in the spec.
+// The ports are patched after the helm chart is applied, the same as the install.
+func patchIngressPorts(ctx context.Context, c client.Client, spec *installv1alpha1.VerrazzanoSpec) error {
+	ports := spec.Components.Ingress.Ports
+	if len(ports) == 0 {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"ports": ports}})
+	if err != nil {
+		return err
+	}
+	svc := corev1.Service{}
+	if err := c.Get(ctx, types.NamespacedName{Name: nginxIngressController, Namespace: nginxNamespace}, &svc); err != nil {
+		return err
+	}
+	return c.Patch(ctx, &svc, client.RawPatch(types.StrategicMergePatchType, patch))
+}
+
+// updateClusterIssuer updates the cluster issuer with the certificate settings in the spec.  The secret
+// name is updated for a CA issuer and the email address is updated for an ACME issuer.
+func updateClusterIssuer(ctx context.Context, c client.Client, spec *installv1alpha1.VerrazzanoSpec) error {
+	issuer := unstructured.Unstructured{}
+	issuer.SetGroupVersionKind(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1alpha2", Kind: "ClusterIssuer"})
+	if err := c.Get(ctx, types.NamespacedName{Name: clusterIssuerName}, &issuer); err != nil {
+		return err
+	}
+	cert := spec.Components.CertManager.Certificate
+	var err error
+	if len(cert.CA.SecretName) > 0 {
+		err = unstructured.SetNestedField(issuer.Object, cert.CA.SecretName, "spec", "ca", "secretName")
+	} else if len(cert.Acme.EmailAddress) > 0 {
+		err = unstructured.SetNestedField(issuer.Object, cert.Acme.EmailAddress, "spec", "acme", "email")
+	}
+	if err != nil {
+		return err
+	}
+	return c.Update(ctx, &issuer)
+}
@@ -0,0 +1,399 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// reconfigureRunner is used to test the component updates without running the helm command.  The runner
+// keeps track of the arguments of each helm upgrade command by release name, and the uninstalled releases.
+type reconfigureRunner struct {
+	upgrades   map[string][]string
+	uninstalls []string
+}
+
+// TestUpdateIngressArgs tests the reconcileUpdate method for the following use case
+// GIVEN a request to reconcile an installed verrazzano resource
+// WHEN the NGINX install args and ports were changed since the spec was saved
+// THEN ensure that only the ingress controller is re-applied with the new args and the ports are patched
+func TestUpdateIngressArgs(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+	runner := &reconfigureRunner{upgrades: map[string][]string{}}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	vz := newInstalledVerrazzano()
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, newIngressService())
+	reconciler := newVerrazzanoReconciler(c)
+	saveEffectiveSpec(t, reconciler, vz)
+
+	vz.Spec.Components.Ingress.NGINXInstallArgs = []vzapi.InstallArgs{{Name: "controller.replicaCount", Value: "2"}}
+	vz.Spec.Components.Ingress.Ports = []corev1.ServicePort{{Name: "https", Protocol: "TCP", Port: 443, NodePort: 30443}}
+	asserts.NoError(c.Update(context.TODO(), vz))
+	result, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+
+	asserts.Len(runner.upgrades, 1, "Only the ingress controller should be re-applied")
+	asserts.Contains(runner.upgrades["ingress-controller"], "--reset-values")
+	asserts.Contains(runner.upgrades["ingress-controller"], "controller.replicaCount=2")
+
+	svc := corev1.Service{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: nginxNamespace, Name: nginxIngressController}, &svc))
+	asserts.Len(svc.Spec.Ports, 1, "Incorrect number of ports")
+	asserts.Equal(int32(30443), svc.Spec.Ports[0].NodePort, "Incorrect node port")
+
+	// The spec is saved so the component is not re-applied again
+	savedSpec, err := reconciler.getSavedInstallSpec(context.TODO(), zap.S(), vz)
+	asserts.NoError(err)
+	asserts.Equal(vz.Spec.Components.Ingress, savedSpec.Components.Ingress, "The spec was not saved")
+	runner.upgrades = map[string][]string{}
+	_, err = reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.Empty(runner.upgrades, "No components should be re-applied")
+}
+
+// TestUpdateCertificate tests the reconcileUpdate method for the following use case
+// GIVEN a request to reconcile an installed verrazzano resource
+// WHEN the CA certificate settings were changed since the spec was saved
+// THEN ensure that cert-manager is re-applied and the cluster issuer is updated
+func TestUpdateCertificate(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+	runner := &reconfigureRunner{upgrades: map[string][]string{}}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	vz := newInstalledVerrazzano()
+	vz.Spec.Components.CertManager.Certificate.CA = vzapi.CA{SecretName: "old-secret", ClusterResourceNamespace: "cert-manager"}
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, newClusterIssuer("old-secret"))
+	reconciler := newVerrazzanoReconciler(c)
+	saveEffectiveSpec(t, reconciler, vz)
+
+	vz.Spec.Components.CertManager.Certificate.CA = vzapi.CA{SecretName: "new-secret", ClusterResourceNamespace: "my-ns"}
+	asserts.NoError(c.Update(context.TODO(), vz))
+	_, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+
+	asserts.Len(runner.upgrades, 1, "Only cert-manager should be re-applied")
+	asserts.Contains(runner.upgrades["cert-manager"], "clusterResourceNamespace=my-ns")
+
+	issuer := newClusterIssuer("")
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Name: clusterIssuerName}, issuer))
+	secretName, _, _ := unstructured.NestedString(issuer.Object, "spec", "ca", "secretName")
+	asserts.Equal("new-secret", secretName, "The cluster issuer was not updated")
+}
+
+// TestUpdateNoSavedSpec tests the reconcileUpdate method for the following use case
+// GIVEN a request to reconcile an installed verrazzano resource
+// WHEN there is no saved spec
+// THEN ensure that no components are re-applied and the spec is saved
+func TestUpdateNoSavedSpec(t *testing.T) {
+	asserts := assert.New(t)
+
+	runner := &reconfigureRunner{upgrades: map[string][]string{}}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	vz := newInstalledVerrazzano()
+	vz.Spec.Components.Keycloak.KeycloakInstallArgs = []vzapi.InstallArgs{{Name: "replicas", Value: "2"}}
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz)
+	reconciler := newVerrazzanoReconciler(c)
+	_, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.Empty(runner.upgrades, "No components should be re-applied")
+
+	savedSpec, err := reconciler.getSavedInstallSpec(context.TODO(), zap.S(), vz)
+	asserts.NoError(err)
+	asserts.Equal(vz.Spec.Components.Keycloak.KeycloakInstallArgs, savedSpec.Components.Keycloak.KeycloakInstallArgs, "The spec was not saved")
+}
+
+// TestUpdateOverrides tests the reconcileUpdate method for the following use case
+// GIVEN a request to reconcile an installed verrazzano resource
+// WHEN the overrides of a component were changed since the spec was saved
+// THEN ensure that only that component is re-applied with its values reset
+func TestUpdateOverrides(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+	runner := &reconfigureRunner{upgrades: map[string][]string{}}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	vz := newInstalledVerrazzano()
+	vz.Spec.Components.Grafana.Overrides = []vzapi.Overrides{{Values: "replicas: 2"}}
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz)
+	reconciler := newVerrazzanoReconciler(c)
+	saveEffectiveSpec(t, reconciler, vz)
+
+	vz.Spec.Components.Grafana.Overrides = []vzapi.Overrides{{Values: "replicas: 3"}}
+	asserts.NoError(c.Update(context.TODO(), vz))
+	_, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+
+	asserts.Len(runner.upgrades, 1, "Only grafana should be re-applied")
+	asserts.Contains(runner.upgrades["grafana"], "--reset-values")
+	asserts.Contains(runner.upgrades["grafana"], "-f", "The overrides were not passed as a values file")
+
+	savedSpec, err := reconciler.getSavedInstallSpec(context.TODO(), zap.S(), vz)
+	asserts.NoError(err)
+	asserts.Equal(vz.Spec.Components.Grafana.Overrides, savedSpec.Components.Grafana.Overrides, "The spec was not saved")
+}
+
+// TestUpdateOverridesContents tests the reconcileUpdate method for the following use case
+// GIVEN a request to reconcile an installed verrazzano resource
+// WHEN the contents of an override ConfigMap were changed since the spec was saved, and the spec is unchanged
+// THEN ensure that only that component is re-applied, and that the keys of the values are saved without the values
+func TestUpdateOverridesContents(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+	runner := &reconfigureRunner{upgrades: map[string][]string{}}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	vz := newInstalledVerrazzano()
+	vz.Spec.Components.Grafana.Overrides = []vzapi.Overrides{{ConfigMapRef: &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "grafana-overrides"}, Key: "values.yaml"}}}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: vz.Namespace, Name: "grafana-overrides"},
+		Data:       map[string]string{"values.yaml": "replicas: 2"},
+	}
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, cm)
+	reconciler := newVerrazzanoReconciler(c)
+	saveEffectiveSpec(t, reconciler, vz)
+
+	cm.Data["values.yaml"] = "replicas: 3"
+	asserts.NoError(c.Update(context.TODO(), cm))
+	_, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.Len(runner.upgrades, 1, "Only grafana should be re-applied")
+	asserts.Contains(runner.upgrades["grafana"], "--reset-values")
+
+	saved, err := reconciler.getSavedOverrides(context.TODO(), zap.S(), vz)
+	asserts.NoError(err)
+	asserts.Equal(hashOverrides([]string{"replicas: 3"}), saved["grafana"].Hash, "The overrides were not saved")
+	asserts.Equal("replicas: null\n", saved["grafana"].Keys)
+
+	// The component is not re-applied once the overrides are saved
+	runner.upgrades = map[string][]string{}
+	_, err = reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.Empty(runner.upgrades)
+}
+
+// TestUpdateToggleComponents tests the reconcileUpdate method for the following use case
+// GIVEN a request to reconcile an installed verrazzano resource
+// WHEN components were disabled and then enabled again since the spec was saved
+// THEN ensure that the disabled components are uninstalled, and installed again once they are enabled
+func TestUpdateToggleComponents(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+	runner := &reconfigureRunner{upgrades: map[string][]string{}}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	vz := newInstalledVerrazzano()
+	vz.Spec.Components.DNS.External = vzapi.External{Suffix: "example.com"}
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz)
+	reconciler := newVerrazzanoReconciler(c)
+	saveEffectiveSpec(t, reconciler, vz)
+
+	disabled := false
+	vz.Spec.Components.Grafana.Enabled = &disabled
+	vz.Spec.Components.Console.Enabled = &disabled
+	asserts.NoError(c.Update(context.TODO(), vz))
+	_, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.Equal([]string{"grafana"}, runner.uninstalls, "Only grafana should be uninstalled")
+	asserts.Len(runner.upgrades, 1, "Only the verrazzano chart should be re-applied")
+	asserts.Contains(runner.upgrades["verrazzano"], "console.enabled=false")
+
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, vz))
+	asserts.Equal(vzapi.CompStateDisabled, vz.Status.Components["grafana"].State, "Incorrect state of grafana")
+	asserts.NotNil(vz.Status.VerrazzanoInstance, "The instance info should be set")
+	asserts.Nil(vz.Status.VerrazzanoInstance.Console, "The console URL should not be set")
+
+	runner.upgrades = map[string][]string{}
+	runner.uninstalls = nil
+	vz.Spec.Components.Grafana.Enabled = nil
+	asserts.NoError(c.Update(context.TODO(), vz))
+	_, err = reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.Empty(runner.uninstalls, "No components should be uninstalled")
+	asserts.Len(runner.upgrades, 1, "Only grafana should be installed")
+	asserts.Contains(runner.upgrades["grafana"], "--install")
+}
+
+// TestUpdateIstio tests the reconcileUpdate method for the following use case
+// GIVEN a request to reconcile an installed verrazzano resource that has Istio install args
+// WHEN Istio CoreDNS is disabled, and then the install args are removed
+// THEN ensure that the Istio ingress is only re-applied when the install args change, and the values are reset
+func TestUpdateIstio(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+	runner := &reconfigureRunner{upgrades: map[string][]string{}}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+
+	vz := newInstalledVerrazzano()
+	vz.Spec.Components.Istio.IstioInstallArgs = []vzapi.InstallArgs{{Name: "gateways.istio-ingressgateway.replicaCount", Value: "2"}}
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz)
+	reconciler := newVerrazzanoReconciler(c)
+	saveEffectiveSpec(t, reconciler, vz)
+
+	disabled := false
+	vz.Spec.Components.Istio.CoreDNS.Enabled = &disabled
+	asserts.NoError(c.Update(context.TODO(), vz))
+	_, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.Equal([]string{"istiocoredns"}, runner.uninstalls, "Only Istio CoreDNS should be uninstalled")
+	asserts.Empty(runner.upgrades, "The Istio ingress should not be re-applied")
+
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, vz))
+	vz.Spec.Components.Istio.IstioInstallArgs = nil
+	asserts.NoError(c.Update(context.TODO(), vz))
+	_, err = reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.Len(runner.upgrades, 1, "Only the Istio ingress should be re-applied")
+	asserts.Contains(runner.upgrades["istio-ingress"], "--reset-values")
+	asserts.NotContains(runner.upgrades["istio-ingress"], "gateways.istio-ingressgateway.replicaCount=2")
+}
+
+// TestGetToggledComponents tests the getToggledComponents function
+// GIVEN specs where components were enabled and disabled
+// WHEN getToggledComponents is called
+// THEN ensure that the enabled components are returned, and the disabled components in reverse order
+func TestGetToggledComponents(t *testing.T) {
+	asserts := assert.New(t)
+	disabled := false
+	oldSpec := vzapi.VerrazzanoSpec{}
+	oldSpec.Components.Rancher.Enabled = &disabled
+	newSpec := vzapi.VerrazzanoSpec{}
+	newSpec.Components.Keycloak.Enabled = &disabled
+	newSpec.Components.Prometheus.Enabled = &disabled
+
+	enabled, disabledComps := getToggledComponents(&oldSpec, &newSpec)
+	asserts.Equal([]string{"rancher"}, componentNames(enabled))
+	asserts.Equal([]string{"keycloak", "mysql", "prometheus"}, componentNames(disabledComps))
+}
+
+// componentNames returns the names of the components
+func componentNames(comps []component.Component) []string {
+	var names []string
+	for _, comp := range comps {
+		names = append(names, comp.Name())
+	}
+	return names
+}
+
+// TestGetSetArgs tests the getSetArgs function
+// GIVEN a list of install args
+// WHEN the args are converted to helm set arguments
+// THEN ensure that args without a value are skipped and value lists use the helm list syntax
+func TestGetSetArgs(t *testing.T) {
+	asserts := assert.New(t)
+	args := getSetArgs([]vzapi.InstallArgs{
+		{Name: "a", Value: "1"},
+		{Name: "b", Value: "true", SetString: true},
+		{Name: "c"},
+		{Name: "d", ValueList: []string{"x", "y"}},
+	})
+	asserts.Equal([]helm.SetArg{
+		{Name: "a", Value: "1"},
+		{Name: "b", Value: "true", SetString: true},
+		{Name: "d", Value: "{x,y}"},
+	}, args)
+}
+
+// newInstalledVerrazzano creates a Verrazzano resource that has been installed
+func newInstalledVerrazzano() *vzapi.Verrazzano {
+	return &vzapi.Verrazzano{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "install.verrazzano.io/v1alpha1",
+			Kind:       "Verrazzano"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "verrazzano",
+			Name:       "test",
+			Finalizers: []string{finalizerName}},
+		Status: vzapi.VerrazzanoStatus{
+			Conditions: []vzapi.Condition{
+				{
+					Type: vzapi.InstallComplete,
+				},
+			},
+		},
+	}
+}
+
+// saveEffectiveSpec saves the effective spec of the resource, the same as the reconciler saves it after an install
+func saveEffectiveSpec(t *testing.T, reconciler Reconciler, vz *vzapi.Verrazzano) {
+	saved := vz.DeepCopy()
+	assert.NoError(t, reconciler.applyProfile(context.TODO(), zap.S(), saved))
+	assert.NoError(t, reconciler.saveVerrazzanoSpec(context.TODO(), zap.S(), saved))
+}
+
+// newIngressService creates the NGINX ingress controller service
+func newIngressService() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nginxNamespace,
+			Name:      nginxIngressController},
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeNodePort,
+			Ports: []corev1.ServicePort{{Name: "https", Protocol: "TCP", Port: 443, NodePort: 31443}},
+		},
+	}
+}
+
+// newClusterIssuer creates a CA cluster issuer with the secret name
+func newClusterIssuer(secretName string) *unstructured.Unstructured {
+	issuer := &unstructured.Unstructured{}
+	issuer.SetGroupVersionKind(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1alpha2", Kind: "ClusterIssuer"})
+	issuer.SetName(clusterIssuerName)
+	if len(secretName) > 0 {
+		_ = unstructured.SetNestedField(issuer.Object, secretName, "spec", "ca", "secretName")
+	}
+	return issuer
+}
+
+// Run tracks the arguments of the helm upgrade commands and returns success for all other commands
+func (r *reconfigureRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	switch cmd.Args[1] {
+	case "get":
+		return []byte("{}"), []byte(""), nil
+	case "upgrade":
+		r.upgrades[cmd.Args[2]] = cmd.Args
+	case "uninstall":
+		r.uninstalls = append(r.uninstalls, cmd.Args[2])
+	}
+	return []byte("success"), []byte(""), nil
+}
@@ -4,14 +4,22 @@
 package verrazzano
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/metrics"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
+	clipkg "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // The max upgrade failures for a given upgrade attempt is 2
@@ -28,6 +36,23 @@ func (r *Reconciler) reconcileUpgrade(log *zap.SugaredLogger, req ctrl.Request,
 		return ctrl.Result{}, nil
 	}
 
+	// Run the preflight checks before any release is touched.  The checks are skipped when an upgrade that
+	// was already started is resumed, including the retry of an upgrade of this generation that failed, since
+	// the release of the component that failed is left in the failed status.  In preflight only mode, and for
+	// a dry run, only the checks are run.
+	preflightOnly := cr.Spec.UpgradePolicy.PreflightOnly || r.DryRun
+	if preflightOnly && isPreflightDone(cr) {
+		log.Info("Preflight checks already done, upgrade will not be attempted")
+		return ctrl.Result{}, nil
+	}
+	resuming := isLastCondition(cr.Status, installv1alpha1.UpgradeStarted) || upgradeFailureCount(cr.Status, cr.Generation) > 0
+	if preflightOnly || (config.Get().UpgradePreflightEnabled && !resuming) {
+		result, passed, err := r.reconcilePreflight(log, cr, preflightOnly)
+		if err != nil || !passed || preflightOnly {
+			return result, err
+		}
+	}
+
 	// Only write the upgrade started message once
 	if !isLastCondition(cr.Status, installv1alpha1.UpgradeStarted) {
 		err := r.updateStatus(log, cr, fmt.Sprintf("Verrazzano upgrade to version %s in progress", cr.Spec.Version),
@@ -37,26 +62,128 @@ func (r *Reconciler) reconcileUpgrade(log *zap.SugaredLogger, req ctrl.Request,
 		}
 	}
 
-	// Loop through all of the Verrazzano components and upgrade each one sequentially
-	for _, comp := range component.GetComponents() {
-		if r.DryRun {
-			// Eventually, pass this down through Component.Upgrade() and into the helm command
-			log.Info("Dry run enabled, skipping upgrade")
-			break
+	// Get the components that were already upgraded by a previous attempt of this upgrade
+	progress, err := r.getUpgradeProgress(context.TODO(), log, cr)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Loop through the groups of Verrazzano components and upgrade each group sequentially.  The components
+	// in a group don't depend on each other, so they are upgraded in parallel.
+	for _, group := range component.GetComponentGroups() {
+		var pending []component.Component
+		for _, comp := range group {
+			if !installv1alpha1.IsComponentEnabled(&cr.Spec.Components, comp.Name()) {
+				log.Infof("Skipping upgrade of component %s since it is disabled", comp.Name())
+				continue
+			}
+			if progress.isUpgraded(comp.Name()) {
+				log.Infof("Skipping upgrade of component %s since it was already upgraded", comp.Name())
+				continue
+			}
+			pending = append(pending, comp)
+			setComponentState(cr, comp.Name(), installv1alpha1.CompStateUpgrading, nil)
 		}
-		err := comp.Upgrade(log, r, cr.Namespace)
+		if len(pending) == 0 {
+			continue
+		}
+		overrides, err := getComponentsOverrides(context.TODO(), log, r, cr, pending)
 		if err != nil {
-			log.Errorf("Error upgrading component %s: %v", comp.Name(), err)
-			msg := fmt.Sprintf("Error upgrading component %s - %s\".  Error is %s", comp.Name(),
-				fmtGeneration(cr.Generation), err.Error())
-			err := r.updateStatus(log, cr, msg, installv1alpha1.UpgradeFailed)
 			return ctrl.Result{}, err
 		}
+		if err := r.updateComponentStatus(log, cr); err != nil {
+			return ctrl.Result{}, err
+		}
+		if cr.Spec.UpgradePolicy.RollbackOnFailure {
+			recordRevisions(log, cr.Namespace, progress, pending)
+		}
+		errs := upgradeComponents(log, r, cr.Namespace, pending, overrides)
+
+		// Record the result of each upgrade, then fail the upgrade using the first component that failed
+		var failedComps []component.Component
+		var failedErr error
+		for i, comp := range pending {
+			if errs[i] == nil {
+				progress.Upgraded = append(progress.Upgraded, comp.Name())
+				setComponentReleaseStatus(log, cr, comp)
+				r.Recorder.Eventf(cr, corev1.EventTypeNormal, componentUpgradedReason, "Upgraded component %s", comp.Name())
+				continue
+			}
+			setComponentState(cr, comp.Name(), installv1alpha1.CompStateFailed, errs[i])
+			r.Recorder.Eventf(cr, corev1.EventTypeWarning, componentUpgradeFailedReason, "Failed to upgrade component %s: %v", comp.Name(), errs[i])
+			if failedErr == nil {
+				failedErr = errs[i]
+			}
+			failedComps = append(failedComps, comp)
+		}
+		if err := r.saveUpgradeProgress(context.TODO(), log, cr, progress); err != nil {
+			return ctrl.Result{}, err
+		}
+		if failedErr != nil {
+			var rollbackComps []component.Component
+			var skipped []string
+			if cr.Spec.UpgradePolicy.RollbackOnFailure {
+				rollbackComps, skipped = getRollbackComponents(cr, progress, failedComps)
+			}
+			msg := fmt.Sprintf("Error upgrading component %s - %s\".  Error is %s", failedComps[0].Name(),
+				fmtGeneration(cr.Generation), failedErr.Error())
+			if len(skipped) > 0 {
+				msg += fmt.Sprintf(".  Rollback skipped for components %v since the revision before the upgrade is unknown", skipped)
+			}
+			if err := r.updateStatus(log, cr, msg, installv1alpha1.UpgradeFailed); err != nil {
+				return ctrl.Result{}, err
+			}
+			if cr.Spec.UpgradePolicy.RollbackOnFailure {
+				err := r.rollbackUpgrade(log, cr, progress, rollbackComps)
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		}
+	}
+	if err := r.deleteUpgradeProgress(context.TODO(), cr); err != nil {
+		return ctrl.Result{}, err
 	}
 	msg := fmt.Sprintf("Verrazzano upgraded to version %s successfully", cr.Spec.Version)
 	cr.Status.Version = targetVersion
-	err := r.updateStatus(log, cr, msg, installv1alpha1.UpgradeComplete)
-	return ctrl.Result{}, err
+	if err := r.updateStatus(log, cr, msg, installv1alpha1.UpgradeComplete); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The components that were already at the chart version were not upgraded, so the configuration changes
+	// that were made along with the version, such as changed overrides, are re-applied by the update
+	savedSpec, err := r.getSavedInstallSpec(context.TODO(), log, cr)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	return r.updateComponents(context.TODO(), log, cr, savedSpec)
+}
+
+// upgradeComponents upgrades the components in parallel and waits for all of the upgrades to finish.  The overrides
+// of each component are keyed by component name.  The returned errors are in the same order as the components,
+// with a nil error for each successful upgrade.
+func upgradeComponents(log *zap.SugaredLogger, client clipkg.Client, namespace string, comps []component.Component, overrides map[string][]string) []error {
+	errs := make([]error, len(comps))
+	var wg sync.WaitGroup
+	for i, comp := range comps {
+		wg.Add(1)
+		go func(i int, comp component.Component) {
+			defer wg.Done()
+			start := time.Now()
+			errs[i] = comp.Upgrade(log, client, namespace, overrides[comp.Name()])
+			metrics.ObserveComponentUpgrade(comp.Name(), time.Since(start), errs[i])
+		}(i, comp)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			log.Errorf("Error upgrading component %s: %v", comps[i].Name(), err)
+		}
+	}
+	return errs
 }
 
 // Return true if verrazzano is installed
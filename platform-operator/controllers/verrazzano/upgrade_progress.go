@@ -0,0 +1,100 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"encoding/json"
+
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// The key in the internal configmap that holds the upgrade progress
+const upgradeProgressKey = "upgrade-progress"
+
+// upgradeProgress records the components that have been upgraded for a target version and generation of
+// the Verrazzano resource, so that an upgrade that failed can continue from the component that failed.
+// The helm revisions of the components before they were upgraded are recorded when rollback is enabled.
+type upgradeProgress struct {
+	Version    string         `json:"version"`
+	Generation int64          `json:"generation"`
+	Upgraded   []string       `json:"upgraded,omitempty"`
+	Revisions  map[string]int `json:"revisions,omitempty"`
+}
+
+// isUpgraded returns true if the component has already been upgraded
+func (p *upgradeProgress) isUpgraded(name string) bool {
+	return containsString(p.Upgraded, name)
+}
+
+// getUpgradeProgress returns the upgrade progress saved in the internal configmap.  An empty progress is
+// returned if nothing was saved, or if the saved progress is for a different version or generation.
+func (r *Reconciler) getUpgradeProgress(ctx context.Context, log *zap.SugaredLogger, vz *installv1alpha1.Verrazzano) (*upgradeProgress, error) {
+	progress := &upgradeProgress{Version: vz.Spec.Version, Generation: vz.Generation}
+	configMap, err := r.getInternalConfigMap(ctx, vz)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return progress, nil
+		}
+		return nil, err
+	}
+	data, ok := configMap.Data[upgradeProgressKey]
+	if !ok {
+		return progress, nil
+	}
+	saved := upgradeProgress{}
+	if err := json.Unmarshal([]byte(data), &saved); err != nil {
+		// Invalid progress is ignored, the upgrade starts from the first component
+		log.Errorf("Error unmarshalling saved upgrade progress for %s: %v", vz.Name, err)
+		return progress, nil
+	}
+	if saved.Version != progress.Version || saved.Generation != progress.Generation {
+		return progress, nil
+	}
+	log.Infof("Resuming upgrade to version %s, components already upgraded: %v", saved.Version, saved.Upgraded)
+	return &saved, nil
+}
+
+// saveUpgradeProgress saves the upgrade progress in the internal configmap, creating the configmap if needed
+func (r *Reconciler) saveUpgradeProgress(ctx context.Context, log *zap.SugaredLogger, vz *installv1alpha1.Verrazzano, progress *upgradeProgress) error {
+	progressBytes, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	configMap, err := r.getInternalConfigMap(ctx, vz)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		configMap = newInternalConfigMap(vz, map[string]string{upgradeProgressKey: string(progressBytes)})
+		if err := r.Create(ctx, configMap); err != nil {
+			log.Errorf("Unable to create installer config map %s: %v", configMap.Name, err)
+			return err
+		}
+		return nil
+	}
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[upgradeProgressKey] = string(progressBytes)
+	return r.Update(ctx, configMap)
+}
+
+// deleteUpgradeProgress removes the upgrade progress from the internal configmap once the upgrade is complete
+func (r *Reconciler) deleteUpgradeProgress(ctx context.Context, vz *installv1alpha1.Verrazzano) error {
+	configMap, err := r.getInternalConfigMap(ctx, vz)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if _, ok := configMap.Data[upgradeProgressKey]; !ok {
+		return nil
+	}
+	delete(configMap.Data, upgradeProgressKey)
+	return r.Update(ctx, configMap)
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// upgradeRunner is used to test the component upgrade without running the helm command.  The runner keeps
+// track of the releases that are upgraded and rolled back, and can fail the upgrade or status of a release.  The upgrades run in parallel so access to the
+// map is synchronized.
+type upgradeRunner struct {
+	sync.Mutex
+	upgraded    map[string]bool
+	rolledBack  []string
+	revisions         []string
+	failRelease       string
+	failStatusRelease string
+}
+
+// TestUpgradeResumed tests the reconcileUpgrade method for the following use case
+// GIVEN a request to reconcile a verrazzano resource where a previous upgrade attempt failed
+// WHEN the upgrade progress for the same version and generation was saved
+// THEN ensure that only the components that were not upgraded are upgraded and the progress is removed
+func TestUpgradeResumed(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+	runner := &upgradeRunner{upgraded: map[string]bool{}}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+	component.UpgradePrehooksEnabled = false
+	defer func() { component.UpgradePrehooksEnabled = true }()
+
+	vz := newUpgradeVerrazzano()
+	progress := upgradeProgress{Version: vz.Spec.Version, Generation: vz.Generation, Upgraded: []string{"istio-base", "istiod", "verrazzano"}}
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz, newUpgradeProgressConfigMap(t, vz, progress))
+	reconciler := newVerrazzanoReconciler(c)
+	result, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+	asserts.False(result.Requeue)
+
+	asserts.False(runner.upgraded["istio-base"], "istio-base should not be upgraded again")
+	asserts.False(runner.upgraded["istiod"], "istiod should not be upgraded again")
+	asserts.False(runner.upgraded["verrazzano"], "verrazzano should not be upgraded again")
+	asserts.True(runner.upgraded["keycloak"], "keycloak was not upgraded")
+	asserts.True(runner.upgraded["istio-ingress"], "istio-ingress was not upgraded")
+	asserts.Len(runner.upgraded, len(component.GetComponents())-3, "Incorrect number of components upgraded")
+
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, vz))
+	asserts.True(isLastCondition(vz.Status, vzapi.UpgradeComplete), "Upgrade should be complete")
+	cm := corev1.ConfigMap{}
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: buildInternalConfigMapName(vz.Name)}, &cm))
+	asserts.NotContains(cm.Data, upgradeProgressKey, "Upgrade progress should be removed")
+}
+
+// TestUpgradeProgressSaved tests the reconcileUpgrade method for the following use case
+// GIVEN a request to reconcile a verrazzano resource that needs to be upgraded
+// WHEN the upgrade of a component fails
+// THEN ensure that the components that were upgraded are saved in the upgrade progress
+func TestUpgradeProgressSaved(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+	runner := &upgradeRunner{upgraded: map[string]bool{}, failRelease: "keycloak"}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+	component.UpgradePrehooksEnabled = false
+	defer func() { component.UpgradePrehooksEnabled = true }()
+
+	vz := newUpgradeVerrazzano()
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz)
+	reconciler := newVerrazzanoReconciler(c)
+	_, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, vz))
+	asserts.True(isLastCondition(vz.Status, vzapi.UpgradeFailed), "Upgrade should have failed")
+	asserts.False(runner.upgraded["grafana"], "Components after the failed group should not be upgraded")
+
+	progress, err := reconciler.getUpgradeProgress(context.TODO(), zap.S(), vz)
+	asserts.NoError(err)
+	asserts.True(progress.isUpgraded("istio-base"), "istio-base should be saved as upgraded")
+	asserts.True(progress.isUpgraded("verrazzano"), "verrazzano should be saved as upgraded")
+	asserts.False(progress.isUpgraded("keycloak"), "keycloak should not be saved as upgraded")
+
+	// The saved progress is ignored when the resource generation changes
+	vz.Generation++
+	progress, err = reconciler.getUpgradeProgress(context.TODO(), zap.S(), vz)
+	asserts.NoError(err)
+	asserts.Empty(progress.Upgraded, "Progress from a different generation should be ignored")
+}
+
+// newUpgradeVerrazzano creates an installed Verrazzano resource that needs to be upgraded
+func newUpgradeVerrazzano() *vzapi.Verrazzano {
+	return &vzapi.Verrazzano{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "install.verrazzano.io/v1alpha1",
+			Kind:       "Verrazzano"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "verrazzano",
+			Name:       "test",
+			Generation: 2,
+			Finalizers: []string{finalizerName}},
+		Spec: vzapi.VerrazzanoSpec{
+			Version: "0.2.0"},
+		Status: vzapi.VerrazzanoStatus{
+			Version: "0.1.0",
+			Conditions: []vzapi.Condition{
+				{
+					Type: vzapi.InstallComplete,
+				},
+			},
+		},
+	}
+}
+
+// newUpgradeProgressConfigMap creates the internal configmap with the upgrade progress
+func newUpgradeProgressConfigMap(t *testing.T, vz *vzapi.Verrazzano, progress upgradeProgress) runtime.Object {
+	progressBytes, err := json.Marshal(progress)
+	assert.NoError(t, err)
+	return newInternalConfigMap(vz, map[string]string{upgradeProgressKey: string(progressBytes)})
+}
+
+// Run tracks the upgraded releases and returns a deployed helm status for all releases
+func (r *upgradeRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	release := cmd.Args[2]
+	switch cmd.Args[1] {
+	case "upgrade":
+		if release == r.failRelease {
+			return []byte(""), []byte("failure"), errors.New("Helm Error")
+		}
+		r.Lock()
+		r.upgraded[release] = true
+		r.Unlock()
+	case "rollback":
+		r.rolledBack = append(r.rolledBack, release)
+		r.revisions = append(r.revisions, cmd.Args[3])
+	case "status":
+		if release == r.failStatusRelease {
+			return []byte(""), []byte("failure"), errors.New("Helm Error")
+		}
+		return []byte(`{"version":4,"info":{"status":"deployed"},"chart":{"metadata":{"version":"0.0.0"}}}`), []byte(""), nil
+	}
+	return []byte("success"), []byte(""), nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"fmt"
+
+	installv1alpha1 "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"go.uber.org/zap"
+)
+
+// recordRevisions records the current helm revision of each component before it is upgraded, so that the
+// component can be rolled back to that revision.  The revision is only recorded the first time the component
+// is upgraded for the target version.  If the revision can't be determined then the component is not rolled back.
+func recordRevisions(log *zap.SugaredLogger, namespace string, progress *upgradeProgress, comps []component.Component) {
+	if progress.Revisions == nil {
+		progress.Revisions = make(map[string]int)
+	}
+	for _, comp := range comps {
+		if _, ok := progress.Revisions[comp.Name()]; ok {
+			continue
+		}
+		info, err := comp.GetReleaseInfo(namespace)
+		if err != nil {
+			log.Errorf("Error getting the release info for component %s: %v", comp.Name(), err)
+			continue
+		}
+		if info != nil {
+			progress.Revisions[comp.Name()] = info.Revision
+		}
+	}
+}
+
+// getRollbackComponents returns the components to roll back after the upgrade of the failed components failed.
+// If the upgrade policy also rolls back the upgraded components, then the components that were upgraded for the
+// target version are rolled back after the failed components, in the reverse order they were upgraded.  The
+// names of the components whose revision before the upgrade was not recorded are returned as skipped, since
+// the revision to roll back to is unknown.
+func getRollbackComponents(cr *installv1alpha1.Verrazzano, progress *upgradeProgress, failedComps []component.Component) (comps []component.Component, skipped []string) {
+	candidates := failedComps
+	if cr.Spec.UpgradePolicy.RollbackUpgraded {
+		registered := make(map[string]component.Component)
+		for _, comp := range component.GetComponents() {
+			registered[comp.Name()] = comp
+		}
+		for i := len(progress.Upgraded) - 1; i >= 0; i-- {
+			if comp, ok := registered[progress.Upgraded[i]]; ok {
+				candidates = append(candidates, comp)
+			}
+		}
+	}
+	for _, comp := range candidates {
+		if progress.Revisions[comp.Name()] == 0 {
+			skipped = append(skipped, comp.Name())
+			continue
+		}
+		comps = append(comps, comp)
+	}
+	return comps, skipped
+}
+
+// rollbackUpgrade rolls back the components to the revisions that were recorded before the upgrade.  An
+// UpgradeRolledBack condition is added for each component that is rolled back.  A component that fails to
+// roll back is logged and the remaining components are still rolled back.
+func (r *Reconciler) rollbackUpgrade(log *zap.SugaredLogger, cr *installv1alpha1.Verrazzano, progress *upgradeProgress, comps []component.Component) error {
+	for _, comp := range comps {
+		revision := progress.Revisions[comp.Name()]
+		log.Infof("Rolling back component %s to revision %d", comp.Name(), revision)
+		if err := comp.Rollback(log, r, cr.Namespace, revision); err != nil {
+			log.Errorf("Error rolling back component %s: %v", comp.Name(), err)
+			setComponentState(cr, comp.Name(), installv1alpha1.CompStateFailed, fmt.Errorf("Rollback failed: %v", err))
+			continue
+		}
+		if containsString(progress.Upgraded, comp.Name()) {
+			// The component is upgraded again when the upgrade is retried
+			progress.Upgraded = removeString(progress.Upgraded, comp.Name())
+			setComponentReleaseStatus(log, cr, comp)
+		}
+		delete(progress.Revisions, comp.Name())
+		msg := fmt.Sprintf("Component %s rolled back to revision %d after the upgrade to version %s failed - %s", comp.Name(),
+			revision, cr.Spec.Version, fmtGeneration(cr.Generation))
+		if err := r.updateStatus(log, cr, msg, installv1alpha1.UpgradeRolledBack); err != nil {
+			return err
+		}
+	}
+	return r.saveUpgradeProgress(context.TODO(), log, cr, progress)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package verrazzano
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vzapi "github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
+	"github.com/verrazzano/verrazzano/platform-operator/controllers/verrazzano/component"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestUpgradeRollbackFailed tests the reconcileUpgrade method for the following use case
+// GIVEN a request to reconcile a verrazzano resource with the rollbackOnFailure upgrade policy
+// WHEN the upgrade of a component fails
+// THEN ensure that only the failed component is rolled back to the revision before the upgrade
+func TestUpgradeRollbackFailed(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+	runner := &upgradeRunner{upgraded: map[string]bool{}, failRelease: "keycloak"}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+	component.UpgradePrehooksEnabled = false
+	defer func() { component.UpgradePrehooksEnabled = true }()
+
+	vz := newUpgradeVerrazzano()
+	vz.Spec.UpgradePolicy.RollbackOnFailure = true
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz)
+	reconciler := newVerrazzanoReconciler(c)
+	_, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+
+	asserts.Equal([]string{"keycloak"}, runner.rolledBack, "Only the failed component should be rolled back")
+	asserts.Equal([]string{"4"}, runner.revisions, "Incorrect rollback revision")
+
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, vz))
+	asserts.True(isLastCondition(vz.Status, vzapi.UpgradeRolledBack), "Last condition should be UpgradeRolledBack")
+	asserts.Contains(vz.Status.Conditions[len(vz.Status.Conditions)-1].Message, "keycloak")
+	asserts.Equal(1, upgradeFailureCount(vz.Status, vz.Generation), "Incorrect upgrade failure count")
+	asserts.Equal(vzapi.Failed, vz.Status.State, "Incorrect state")
+
+	// The upgraded components are still saved in the progress
+	progress, err := reconciler.getUpgradeProgress(context.TODO(), zap.S(), vz)
+	asserts.NoError(err)
+	asserts.True(progress.isUpgraded("istiod"), "istiod should be saved as upgraded")
+}
+
+// TestUpgradeRollbackUnknownRevision tests the reconcileUpgrade method for the following use case
+// GIVEN a request to reconcile a verrazzano resource with the rollbackOnFailure upgrade policy
+// WHEN the upgrade of a component fails and its revision before the upgrade could not be recorded
+// THEN ensure that the component is not rolled back and the skipped rollback is reported in the UpgradeFailed condition
+func TestUpgradeRollbackUnknownRevision(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+	runner := &upgradeRunner{upgraded: map[string]bool{}, failRelease: "keycloak", failStatusRelease: "keycloak"}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+	component.UpgradePrehooksEnabled = false
+	defer func() { component.UpgradePrehooksEnabled = true }()
+
+	vz := newUpgradeVerrazzano()
+	vz.Spec.UpgradePolicy.RollbackOnFailure = true
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz)
+	reconciler := newVerrazzanoReconciler(c)
+	_, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+
+	asserts.Empty(runner.rolledBack, "A component with an unknown revision should not be rolled back")
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, vz))
+	asserts.True(isLastCondition(vz.Status, vzapi.UpgradeFailed), "Last condition should be UpgradeFailed")
+	asserts.Contains(vz.Status.Conditions[len(vz.Status.Conditions)-1].Message, "Rollback skipped for components [keycloak]")
+	asserts.Equal(1, upgradeFailureCount(vz.Status, vz.Generation), "Incorrect upgrade failure count")
+}
+
+// TestUpgradeRollbackUpgraded tests the reconcileUpgrade method for the following use case
+// GIVEN a request to reconcile a verrazzano resource with the rollbackOnFailure and rollbackUpgraded upgrade policy
+// WHEN the upgrade of a component fails
+// THEN ensure that the failed component is rolled back, followed by the upgraded components in reverse order
+func TestUpgradeRollbackUpgraded(t *testing.T) {
+	asserts := assert.New(t)
+
+	defer config.Set(config.Get())
+	config.Set(config.OperatorConfig{HelmConfigDir: "../../helm_config"})
+	runner := &upgradeRunner{upgraded: map[string]bool{}, failRelease: "keycloak"}
+	helm.SetCmdRunner(runner)
+	defer helm.SetDefaultRunner()
+	component.UpgradePrehooksEnabled = false
+	defer func() { component.UpgradePrehooksEnabled = true }()
+
+	vz := newUpgradeVerrazzano()
+	vz.Spec.UpgradePolicy.RollbackOnFailure = true
+	vz.Spec.UpgradePolicy.RollbackUpgraded = true
+	c := fake.NewFakeClientWithScheme(newInstallScheme(), vz)
+	reconciler := newVerrazzanoReconciler(c)
+	_, err := reconciler.Reconcile(newRequest(vz.Namespace, vz.Name))
+	asserts.NoError(err)
+
+	// The first two groups are upgraded, keycloak fails in the second group
+	groups := component.GetComponentGroups()
+	asserts.Len(runner.rolledBack, len(groups[0])+len(groups[1]), "Incorrect number of components rolled back")
+	asserts.Equal("keycloak", runner.rolledBack[0], "The failed component should be rolled back first")
+	asserts.Equal("verrazzano-application-operator", runner.rolledBack[1], "The last upgraded component should be rolled back next")
+	asserts.Equal("istio-base", runner.rolledBack[len(runner.rolledBack)-1], "The first upgraded component should be rolled back last")
+
+	asserts.NoError(c.Get(context.TODO(), types.NamespacedName{Namespace: vz.Namespace, Name: vz.Name}, vz))
+	rolledBack := 0
+	for _, cond := range vz.Status.Conditions {
+		if cond.Type == vzapi.UpgradeRolledBack {
+			rolledBack++
+		}
+	}
+	asserts.Equal(len(runner.rolledBack), rolledBack, "Incorrect number of UpgradeRolledBack conditions")
+
+	// The rolled back components are upgraded again on the next attempt
+	progress, err := reconciler.getUpgradeProgress(context.TODO(), zap.S(), vz)
+	asserts.NoError(err)
+	asserts.Empty(progress.Upgraded, "Rolled back components should be removed from the progress")
+}
@@ -5,8 +5,10 @@ package verrazzano
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"os/exec"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -17,9 +19,14 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
 	"github.com/verrazzano/verrazzano/platform-operator/internal/util/helm"
 	"github.com/verrazzano/verrazzano/platform-operator/mocks"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 )
 
 // goodRunner is used to test helm success without actually running an OS exec command
@@ -30,6 +37,17 @@ type goodRunner struct {
 type badRunner struct {
 }
 
+// fakeComponent is used to test upgrading components without running helm
+type fakeComponent struct {
+	name           string
+	upgradeErr     error
+	upgraded       *int32
+	releaseInfoErr error
+}
+
+// Verify that fakeComponent implements Component
+var _ component.Component = fakeComponent{}
+
 // Generate mocs for the Kerberos Client and StatusWriter interfaces for use in tests.
 //go:generate mockgen -destination=../mocks/controller_mock.go -package=mocks -copyright_file=../hack/boilerplate.go.txt sigs.k8s.io/controller-runtime/pkg/client Client,StatusWriter
 
@@ -68,6 +86,9 @@ func TestUpgradeNoVersion(t *testing.T) {
 			return nil
 		})
 
+	// Expect a call to get the saved spec, which is the same as the current spec
+	expectSavedSpec(t, mock, namespace, name, vzapi.VerrazzanoSpec{})
+
 	// Create and make the request
 	request := newRequest(namespace, name)
 	reconciler := newVerrazzanoReconciler(mock)
@@ -118,6 +139,9 @@ func TestUpgradeSameVersion(t *testing.T) {
 			return nil
 		})
 
+	// Expect a call to get the saved spec, which is the same as the current spec
+	expectSavedSpec(t, mock, namespace, name, vzapi.VerrazzanoSpec{Version: "0.2.0"})
+
 	// Create and make the request
 	request := newRequest(namespace, name)
 	reconciler := newVerrazzanoReconciler(mock)
@@ -145,7 +169,7 @@ func TestUpgradeStarted(t *testing.T) {
 	asserts.NotNil(mockStatus)
 
 	defer config.Set(config.Get())
-	config.Set(config.OperatorConfig{VersionCheckEnabled: false})
+	config.Set(config.OperatorConfig{VersionCheckEnabled: false, HelmConfigDir: "../../helm_config"})
 
 	// Expect a call to get the verrazzano resource.  Return resource with version
 	mock.EXPECT().
@@ -173,6 +197,12 @@ func TestUpgradeStarted(t *testing.T) {
 	// Expect a call to get the status writer and return a mock.
 	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
 
+	// Expect the component states to be updated while the components are upgraded
+	expectComponentUpgradeStatus(mockStatus)
+
+	// Expect the upgrade progress to be saved in the internal configmap
+	expectUpgradeProgress(t, mock, namespace, name)
+
 	// Expect a call to update the status of the Verrazzano resource
 	mockStatus.EXPECT().
 		Update(gomock.Any(), gomock.Any()).
@@ -217,7 +247,7 @@ func TestUpgradeTooManyFailures(t *testing.T) {
 	asserts.NotNil(mockStatus)
 
 	defer config.Set(config.Get())
-	config.Set(config.OperatorConfig{VersionCheckEnabled: false})
+	config.Set(config.OperatorConfig{VersionCheckEnabled: false, HelmConfigDir: "../../helm_config"})
 
 	// Expect a call to get the verrazzano resource.  Return resource with version
 	mock.EXPECT().
@@ -282,7 +312,7 @@ func TestUpgradeStartedWhenPrevFailures(t *testing.T) {
 	asserts.NotNil(mockStatus)
 
 	defer config.Set(config.Get())
-	config.Set(config.OperatorConfig{VersionCheckEnabled: false})
+	config.Set(config.OperatorConfig{VersionCheckEnabled: false, HelmConfigDir: "../../helm_config"})
 
 	// Expect a call to get the verrazzano resource.  Return resource with version
 	mock.EXPECT().
@@ -334,6 +364,12 @@ func TestUpgradeStartedWhenPrevFailures(t *testing.T) {
 	// Expect a call to get the status writer and return a mock.
 	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
 
+	// Expect the component states to be updated while the components are upgraded
+	expectComponentUpgradeStatus(mockStatus)
+
+	// Expect the upgrade progress to be saved in the internal configmap
+	expectUpgradeProgress(t, mock, namespace, name)
+
 	// Expect a call to update the status of the Verrazzano resource
 	mockStatus.EXPECT().
 		Update(gomock.Any(), gomock.Any()).
@@ -378,7 +414,7 @@ func TestUpgradeNotStartedWhenPrevFailures(t *testing.T) {
 	asserts.NotNil(mockStatus)
 
 	defer config.Set(config.Get())
-	config.Set(config.OperatorConfig{VersionCheckEnabled: false})
+	config.Set(config.OperatorConfig{VersionCheckEnabled: false, HelmConfigDir: "../../helm_config"})
 
 	// Expect a call to get the verrazzano resource.  Return resource with version
 	mock.EXPECT().
@@ -454,7 +490,7 @@ func TestUpgradeCompleted(t *testing.T) {
 	asserts.NotNil(mockStatus)
 
 	defer config.Set(config.Get())
-	config.Set(config.OperatorConfig{VersionCheckEnabled: false})
+	config.Set(config.OperatorConfig{VersionCheckEnabled: false, HelmConfigDir: "../../helm_config"})
 
 	// Expect a call to get the verrazzano resource.  Return resource with version
 	mock.EXPECT().
@@ -485,12 +521,20 @@ func TestUpgradeCompleted(t *testing.T) {
 	// Expect a call to get the status writer and return a mock.
 	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
 
+	// Expect the component states to be updated while the components are upgraded
+	expectComponentUpgradeStatus(mockStatus)
+
+	// Expect the upgrade progress to be saved in the internal configmap
+	expectUpgradeProgress(t, mock, namespace, name)
+
 	// Expect a call to update the status of the Verrazzano resource
 	mockStatus.EXPECT().
 		Update(gomock.Any(), gomock.Any()).
 		DoAndReturn(func(ctx context.Context, verrazzano *vzapi.Verrazzano, opts ...client.UpdateOption) error {
 			asserts.Len(verrazzano.Status.Conditions, 3, "Incorrect number of conditions")
 			asserts.Equal(verrazzano.Status.Conditions[2].Type, vzapi.UpgradeComplete, "Incorrect conditions")
+			asserts.Len(verrazzano.Status.Components, len(component.GetComponents()), "Incorrect number of component states")
+			asserts.Equal(vzapi.CompStateReady, verrazzano.Status.Components["verrazzano"].State, "Incorrect component state")
 			return nil
 		})
 
@@ -525,7 +569,7 @@ func TestUpgradeHelmError(t *testing.T) {
 	asserts.NotNil(mockStatus)
 
 	defer config.Set(config.Get())
-	config.Set(config.OperatorConfig{VersionCheckEnabled: false})
+	config.Set(config.OperatorConfig{VersionCheckEnabled: false, HelmConfigDir: "../../helm_config"})
 
 	// Expect a call to get the verrazzano resource.  Return resource with version
 	mock.EXPECT().
@@ -556,12 +600,21 @@ func TestUpgradeHelmError(t *testing.T) {
 	// Expect a call to get the status writer and return a mock.
 	mock.EXPECT().Status().Return(mockStatus).AnyTimes()
 
+	// Expect the component states to be updated while the components are upgraded
+	expectComponentUpgradeStatus(mockStatus)
+
+	// Expect the upgrade progress to be saved in the internal configmap
+	expectUpgradeProgress(t, mock, namespace, name)
+
 	// Expect a call to update the status of the Verrazzano resource
 	mockStatus.EXPECT().
 		Update(gomock.Any(), gomock.Any()).
 		DoAndReturn(func(ctx context.Context, verrazzano *vzapi.Verrazzano, opts ...client.UpdateOption) error {
 			asserts.Len(verrazzano.Status.Conditions, 3, "Incorrect number of conditions")
 			asserts.Equal(verrazzano.Status.Conditions[2].Type, vzapi.UpgradeFailed, "Incorrect condition")
+			asserts.Equal(vzapi.CompStateFailed, verrazzano.Status.Components["istio-base"].State, "Incorrect component state")
+			asserts.Equal("helm status for release istio-base in namespace istio-system failed: Helm Error: failure", verrazzano.Status.Components["istio-base"].LastError, "Incorrect component error")
+			asserts.NotContains(verrazzano.Status.Components, "istiod", "Component in a later group should not have a state")
 			return nil
 		})
 
@@ -580,6 +633,26 @@ func TestUpgradeHelmError(t *testing.T) {
 	asserts.Equal(time.Duration(0), result.RequeueAfter)
 }
 
+// TestUpgradeComponents tests the upgradeComponents method for the following use case
+// GIVEN a group of components where two of the component upgrades fail
+// WHEN upgradeComponents is called
+// THEN ensure that all of the components are upgraded and an error is returned for each failed component
+func TestUpgradeComponents(t *testing.T) {
+	asserts := assert.New(t)
+	var upgraded int32
+	comps := []component.Component{
+		fakeComponent{name: "a", upgraded: &upgraded},
+		fakeComponent{name: "b", upgraded: &upgraded, upgradeErr: errors.New("b failed")},
+		fakeComponent{name: "c", upgraded: &upgraded, upgradeErr: errors.New("c failed")},
+	}
+	errs := upgradeComponents(zap.S(), nil, "", comps, nil)
+	asserts.Len(errs, 3, "Incorrect number of errors")
+	asserts.NoError(errs[0])
+	asserts.EqualError(errs[1], "b failed")
+	asserts.EqualError(errs[2], "c failed")
+	asserts.Equal(int32(3), upgraded, "Incorrect number of components upgraded")
+}
+
 // TestIsLastConditionNone tests the isLastCondition method for the following use case
 // GIVEN an empty array of conditions
 // WHEN isLastCondition is called
@@ -627,10 +700,126 @@ func TestIsLastConditionTrue(t *testing.T) {
 	asserts.True(isLastCondition(st, vzapi.InstallFailed), "isLastCondition should have returned true")
 }
 
+// expectComponentUpgradeStatus expects one or more status updates that only change the component states
+func expectComponentUpgradeStatus(mockStatus *mocks.MockStatusWriter) {
+	mockStatus.EXPECT().
+		Update(gomock.Any(), componentUpgradingMatcher{}).
+		Return(nil).
+		MinTimes(1)
+}
+
+// expectSavedSpec expects one or more calls to get the internal configmap that contains the saved spec.  The effective
+// spec is saved, the same as the reconciler saves it.
+func expectSavedSpec(t *testing.T, mock *mocks.MockClient, namespace string, name string, spec vzapi.VerrazzanoSpec) {
+	profile, err := vzapi.GetProfile(context.TODO(), nil, spec.Profile)
+	assert.NoError(t, err)
+	effectiveSpec, err := vzapi.GetEffectiveSpec(profile, &spec)
+	assert.NoError(t, err)
+	specBytes, err := yaml.Marshal(effectiveSpec)
+	assert.NoError(t, err)
+	mock.EXPECT().
+		Get(gomock.Any(), client.ObjectKey{Namespace: namespace, Name: buildInternalConfigMapName(name)}, gomock.Not(gomock.Nil())).
+		DoAndReturn(func(ctx context.Context, key client.ObjectKey, configMap *corev1.ConfigMap) error {
+			configMap.Data = map[string]string{configDataKey: base64.StdEncoding.EncodeToString(specBytes)}
+			return nil
+		}).
+		MinTimes(1)
+}
+
+// expectUpgradeProgress expects the upgrade progress to be saved in a new internal configmap
+func expectUpgradeProgress(t *testing.T, mock *mocks.MockClient, namespace string, name string) {
+	mock.EXPECT().
+		Get(gomock.Any(), client.ObjectKey{Namespace: namespace, Name: buildInternalConfigMapName(name)}, gomock.Not(gomock.Nil())).
+		Return(k8serrors.NewNotFound(schema.GroupResource{Group: "", Resource: "configmap"}, buildInternalConfigMapName(name))).
+		AnyTimes()
+	mock.EXPECT().
+		Create(gomock.Any(), gomock.AssignableToTypeOf(&corev1.ConfigMap{})).
+		DoAndReturn(func(ctx context.Context, configMap *corev1.ConfigMap, opts ...client.CreateOption) error {
+			assert.Contains(t, configMap.Data, upgradeProgressKey, "Upgrade progress was not saved")
+			return nil
+		}).
+		MinTimes(1)
+}
+
+// componentUpgradingMatcher matches a Verrazzano resource that has a component in the Upgrading state
+type componentUpgradingMatcher struct{}
+
+func (m componentUpgradingMatcher) Matches(x interface{}) bool {
+	vz, ok := x.(*vzapi.Verrazzano)
+	if !ok {
+		return false
+	}
+	for _, compStatus := range vz.Status.Components {
+		if compStatus.State == vzapi.CompStateUpgrading {
+			return true
+		}
+	}
+	return false
+}
+
+func (m componentUpgradingMatcher) String() string {
+	return "has a component in the Upgrading state"
+}
+
 func (r goodRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	if cmd.Args[1] == "status" {
+		return []byte(`{"version":1,"info":{"status":"deployed"},"chart":{"metadata":{"version":"0.1.0"}}}`), []byte(""), nil
+	}
 	return []byte("success"), []byte(""), nil
 }
 
 func (r badRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
 	return []byte(""), []byte("failure"), errors.New("Helm Error")
 }
+
+func (f fakeComponent) Name() string {
+	return f.name
+}
+
+func (f fakeComponent) GetDependencies() []string {
+	return nil
+}
+
+func (f fakeComponent) PreInstall(_ *zap.SugaredLogger, _ client.Client, _ string) error {
+	return nil
+}
+
+func (f fakeComponent) Install(_ *zap.SugaredLogger, _ client.Client, _ string, _ []string) error {
+	return nil
+}
+
+func (f fakeComponent) PostInstall(_ *zap.SugaredLogger, _ client.Client, _ string) error {
+	return nil
+}
+
+func (f fakeComponent) IsReady(_ *zap.SugaredLogger, _ client.Client, _ string) bool {
+	return true
+}
+
+func (f fakeComponent) GetChartDir() string {
+	return ""
+}
+
+func (f fakeComponent) GetReleaseInfo(_ string) (*helm.ReleaseInfo, error) {
+	if f.releaseInfoErr != nil {
+		return nil, f.releaseInfoErr
+	}
+	return &helm.ReleaseInfo{Status: helm.ReleaseStatusDeployed, Revision: 1, ChartVersion: "0.1.0"}, nil
+}
+
+func (f fakeComponent) Uninstall(_ *zap.SugaredLogger, _ client.Client, _ string) error {
+	return nil
+}
+
+func (f fakeComponent) Reconfigure(_ *zap.SugaredLogger, _ client.Client, _ string, _ helm.ReconfigureValues) error {
+	return nil
+}
+
+func (f fakeComponent) Rollback(_ *zap.SugaredLogger, _ client.Client, _ string, _ int) error {
+	return nil
+}
+
+func (f fakeComponent) Upgrade(_ *zap.SugaredLogger, _ client.Client, _ string, _ []string) error {
+	atomic.AddInt32(f.upgraded, 1)
+	return f.upgradeErr
+}
@@ -21,12 +21,22 @@ type OperatorConfig struct {
 	// VersionCheckEnabled enables/disables version checking for upgrade.
 	VersionCheckEnabled bool
 
+	// UpgradePreflightEnabled enables/disables the checks that are run before an upgrade
+	UpgradePreflightEnabled bool
+
 	// WebhooksEnabled enables/disables Webhooks for the operator
 	WebhooksEnabled bool
 
 	// WebhookValidationEnabled enables/disables webhook validation without removing the webhook itself
 	WebhookValidationEnabled bool
 
+	// ComponentInstallEnabled enables/disables installing the Verrazzano components from the operator
+	// instead of running the install job
+	ComponentInstallEnabled bool
+
+	// HelmCLIEnabled runs the helm command instead of using the in-process helm client
+	HelmCLIEnabled bool
+
 	// VerrazzanoInstallDir is the directory in the image that contains the helm charts and installation scripts
 	VerrazzanoInstallDir string
 
@@ -45,8 +55,11 @@ var instance OperatorConfig = OperatorConfig{
 	MetricsAddr:              ":8080",
 	LeaderElectionEnabled:    false,
 	VersionCheckEnabled:      true,
+	UpgradePreflightEnabled:  true,
 	WebhooksEnabled:          true,
 	WebhookValidationEnabled: true,
+	ComponentInstallEnabled:  false,
+	HelmCLIEnabled:           false,
 	VerrazzanoInstallDir:     "/verrazzano/platform-operator/scripts/install",
 	ThirdpartyChartsDir:      "/verrazzano/platform-operator/thirdparty/charts",
 	HelmConfigDir:            "/verrazzano/platform-operator/helm_config",
@@ -22,8 +22,11 @@ func TestConfigDefaults(t *testing.T) {
 	asserts.False(conf.LeaderElectionEnabled, "LeaderElectionEnabled is incorrect")
 	asserts.Equal(":8080", conf.MetricsAddr, "MetricsAddr is incorrect")
 	asserts.True(conf.VersionCheckEnabled, "VersionCheckEnabled is incorrect")
+	asserts.True(conf.UpgradePreflightEnabled, "UpgradePreflightEnabled is incorrect")
 	asserts.True(conf.WebhooksEnabled, "WebhooksEnabled is incorrect")
 	asserts.True(conf.WebhookValidationEnabled, "WebhookValidationEnabled is incorrect")
+	asserts.False(conf.ComponentInstallEnabled, "ComponentInstallEnabled is incorrect")
+	asserts.False(conf.HelmCLIEnabled, "HelmCLIEnabled is incorrect")
 	asserts.Equal("/verrazzano/platform-operator/scripts/install", conf.VerrazzanoInstallDir, "VerrazzanoInstallDir is incorrect")
 	asserts.Equal("/verrazzano/platform-operator/thirdparty/charts", conf.ThirdpartyChartsDir, "ThirdpartyChartsDir is incorrect")
 	asserts.Equal("/verrazzano/platform-operator/helm_config", conf.HelmConfigDir, "HelmConfigdir is incorrect")
@@ -44,6 +47,7 @@ func TestSetConfig(t *testing.T) {
 		VersionCheckEnabled:      false,
 		WebhooksEnabled:          false,
 		WebhookValidationEnabled: false,
+		ComponentInstallEnabled:  true,
 		VerrazzanoInstallDir:     "/test/vz",
 		ThirdpartyChartsDir:      "/test/thirdparty",
 		HelmConfigDir:            "/test/helm_config",
@@ -58,6 +62,7 @@ func TestSetConfig(t *testing.T) {
 	asserts.False(conf.VersionCheckEnabled, "VersionCheckEnabled is incorrect")
 	asserts.False(conf.WebhooksEnabled, "WebhooksEnabled is incorrect")
 	asserts.False(conf.WebhookValidationEnabled, "WebhookValidationEnabled is incorrect")
+	asserts.True(conf.ComponentInstallEnabled, "ComponentInstallEnabled is incorrect")
 	asserts.Equal("/test/vz", conf.VerrazzanoInstallDir, "VerrazzanoInstallDir is incorrect")
 	asserts.Equal("/test/thirdparty", conf.ThirdpartyChartsDir, "ThirdpartyChartsDir is incorrect")
 	asserts.Equal("/test/helm_config", conf.HelmConfigDir, "HelmConfigDir is incorrect")
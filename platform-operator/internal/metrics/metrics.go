@@ -0,0 +1,117 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The namespace and subsystem of the platform operator metrics
+const (
+	metricsNamespace = "verrazzano"
+	metricsSubsystem = "platform_operator"
+)
+
+// The results of a lifecycle operation
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// operationDuration is the duration of the install, upgrade and uninstall of the Verrazzano resources
+var operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: metricsNamespace,
+	Subsystem: metricsSubsystem,
+	Name:      "operation_duration_seconds",
+	Help:      "The duration of the install, upgrade and uninstall operations of Verrazzano",
+	Buckets:   prometheus.ExponentialBuckets(30, 2, 8),
+}, []string{"operation", "result"})
+
+// componentUpgradeDuration is the duration of the upgrade of each component
+var componentUpgradeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: metricsNamespace,
+	Subsystem: metricsSubsystem,
+	Name:      "component_upgrade_duration_seconds",
+	Help:      "The duration of the upgrade of a Verrazzano component",
+	Buckets:   prometheus.ExponentialBuckets(5, 2, 9),
+}, []string{"component"})
+
+// componentUpgradeFailures is the number of failed upgrades of each component
+var componentUpgradeFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: metricsNamespace,
+	Subsystem: metricsSubsystem,
+	Name:      "component_upgrade_failures_total",
+	Help:      "The number of failed upgrades of a Verrazzano component",
+}, []string{"component"})
+
+// helmCommandDuration is the latency of the helm commands, labelled by the exit status of the command
+var helmCommandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: metricsNamespace,
+	Subsystem: metricsSubsystem,
+	Name:      "helm_command_duration_seconds",
+	Help:      "The latency of the helm commands run by the platform operator",
+	Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+}, []string{"command", "status"})
+
+// state is 1 for the current state of each Verrazzano resource, and 0 for the other states
+var state = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: metricsNamespace,
+	Subsystem: metricsSubsystem,
+	Name:      "state",
+	Help:      "The current state of a Verrazzano resource, 1 for the current state and 0 for the other states",
+}, []string{"namespace", "name", "state"})
+
+func init() {
+	metrics.Registry.MustRegister(operationDuration, componentUpgradeDuration, componentUpgradeFailures,
+		helmCommandDuration, state)
+}
+
+// InitComponents creates the component metrics of each component, so that the failure counters are reported
+// before the first upgrade of the component
+func InitComponents(components []string) {
+	for _, comp := range components {
+		componentUpgradeFailures.WithLabelValues(comp)
+	}
+}
+
+// ObserveOperation records the duration of an install, upgrade or uninstall operation with its result
+func ObserveOperation(operation string, result string, duration time.Duration) {
+	operationDuration.WithLabelValues(operation, result).Observe(duration.Seconds())
+}
+
+// ObserveComponentUpgrade records the duration of the upgrade of a component, and counts the upgrade as
+// failed if err is not nil
+func ObserveComponentUpgrade(component string, duration time.Duration, err error) {
+	componentUpgradeDuration.WithLabelValues(component).Observe(duration.Seconds())
+	if err != nil {
+		componentUpgradeFailures.WithLabelValues(component).Inc()
+	}
+}
+
+// ObserveHelmCommand records the latency of a helm command with its exit status
+func ObserveHelmCommand(command string, status string, duration time.Duration) {
+	helmCommandDuration.WithLabelValues(command, status).Observe(duration.Seconds())
+}
+
+// SetState sets the current state of a Verrazzano resource.  The states are all the possible states of the
+// resource, the gauge of the current state is set to 1 and the gauges of the other states are set to 0.
+func SetState(namespace string, name string, current string, states []string) {
+	for _, s := range states {
+		value := 0.0
+		if s == current {
+			value = 1
+		}
+		state.WithLabelValues(namespace, name, s).Set(value)
+	}
+}
+
+// DeleteState deletes the state gauges of a Verrazzano resource that was deleted
+func DeleteState(namespace string, name string, states []string) {
+	for _, s := range states {
+		state.DeleteLabelValues(namespace, name, s)
+	}
+}
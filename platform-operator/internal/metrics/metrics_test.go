@@ -0,0 +1,85 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestObserveComponentUpgrade tests the ObserveComponentUpgrade function
+// GIVEN the upgrades of a component
+// WHEN ObserveComponentUpgrade is called
+// THEN the duration of each upgrade is recorded and the failed upgrades are counted
+func TestObserveComponentUpgrade(t *testing.T) {
+	asserts := assert.New(t)
+
+	InitComponents([]string{"test-init"})
+	asserts.Equal(float64(0), testutil.ToFloat64(componentUpgradeFailures.WithLabelValues("test-init")))
+
+	ObserveComponentUpgrade("test-upgrade", time.Minute, nil)
+	ObserveComponentUpgrade("test-upgrade", time.Minute, errors.New("upgrade failed"))
+	asserts.Equal(uint64(2), histogramCount(t, componentUpgradeDuration.WithLabelValues("test-upgrade")))
+	asserts.Equal(float64(120), histogramSum(t, componentUpgradeDuration.WithLabelValues("test-upgrade")))
+	asserts.Equal(float64(1), testutil.ToFloat64(componentUpgradeFailures.WithLabelValues("test-upgrade")))
+}
+
+// TestObserveOperation tests the ObserveOperation and ObserveHelmCommand functions
+// GIVEN a completed operation or helm command
+// WHEN the function is called
+// THEN the duration is recorded with the result of the operation or the exit status of the command
+func TestObserveOperation(t *testing.T) {
+	asserts := assert.New(t)
+
+	ObserveOperation("test-install", ResultFailure, time.Hour)
+	asserts.Equal(uint64(1), histogramCount(t, operationDuration.WithLabelValues("test-install", ResultFailure)))
+	asserts.Equal(uint64(0), histogramCount(t, operationDuration.WithLabelValues("test-install", ResultSuccess)))
+
+	ObserveHelmCommand("test-upgrade", "1", time.Second)
+	asserts.Equal(uint64(1), histogramCount(t, helmCommandDuration.WithLabelValues("test-upgrade", "1")))
+}
+
+// TestSetState tests the SetState and DeleteState functions
+// GIVEN a Verrazzano resource
+// WHEN the state of the resource is set
+// THEN the gauge of the current state is 1 and the gauges of the other states are 0, and the gauges are
+//      removed when the state is deleted
+func TestSetState(t *testing.T) {
+	asserts := assert.New(t)
+	states := []string{"Installing", "Ready"}
+
+	SetState("test-ns", "test", "Installing", states)
+	asserts.Equal(float64(1), testutil.ToFloat64(state.WithLabelValues("test-ns", "test", "Installing")))
+	asserts.Equal(float64(0), testutil.ToFloat64(state.WithLabelValues("test-ns", "test", "Ready")))
+
+	SetState("test-ns", "test", "Ready", states)
+	asserts.Equal(float64(0), testutil.ToFloat64(state.WithLabelValues("test-ns", "test", "Installing")))
+	asserts.Equal(float64(1), testutil.ToFloat64(state.WithLabelValues("test-ns", "test", "Ready")))
+
+	DeleteState("test-ns", "test", states)
+	asserts.False(state.DeleteLabelValues("test-ns", "test", "Ready"), "The state gauge was not deleted")
+}
+
+// histogramCount returns the number of observations of a histogram
+func histogramCount(t *testing.T, o prometheus.Observer) uint64 {
+	return writeHistogram(t, o).GetSampleCount()
+}
+
+// histogramSum returns the sum of the observations of a histogram
+func histogramSum(t *testing.T, o prometheus.Observer) float64 {
+	return writeHistogram(t, o).GetSampleSum()
+}
+
+// writeHistogram writes the observations of a histogram
+func writeHistogram(t *testing.T, o prometheus.Observer) *dto.Histogram {
+	m := &dto.Metric{}
+	assert.NoError(t, o.(prometheus.Metric).Write(m))
+	return m.GetHistogram()
+}
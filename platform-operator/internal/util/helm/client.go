@@ -0,0 +1,318 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package helm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/verrazzano/verrazzano/platform-operator/internal/config"
+	"github.com/verrazzano/verrazzano/platform-operator/internal/metrics"
+	vz_os "github.com/verrazzano/verrazzano/platform-operator/internal/util/os"
+	"go.uber.org/zap"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/strvals"
+)
+
+// ReleaseStatusDeployed is the helm status of a release that has been successfully deployed
+const ReleaseStatusDeployed = "deployed"
+
+// ErrReleaseNotFound is returned when a helm release doesn't exist
+var ErrReleaseNotFound = errors.New("release not found")
+
+// ReleaseError is returned when a helm operation on a release fails
+type ReleaseError struct {
+	// Operation is the helm operation that failed, for example upgrade or rollback
+	Operation string
+	// Release is the name of the release
+	Release string
+	// Namespace is the namespace of the release
+	Namespace string
+	// Err is the error returned by helm
+	Err error
+}
+
+// Error returns the error message of the helm operation
+func (e *ReleaseError) Error() string {
+	return fmt.Sprintf("helm %s for release %s in namespace %s failed: %v", e.Operation, e.Release, e.Namespace, e.Err)
+}
+
+// Unwrap returns the error returned by helm, so that errors.Is can be used to check for ErrReleaseNotFound
+func (e *ReleaseError) Unwrap() error {
+	return e.Err
+}
+
+// ReleaseInfo contains the status, revision and chart version of a helm release
+type ReleaseInfo struct {
+	// Status is the helm status of the release, for example deployed or failed
+	Status string
+	// Revision is the helm revision of the release
+	Revision int
+	// ChartVersion is the version of the chart that was used for the release
+	ChartVersion string
+}
+
+// SetArg is a helm value that is passed on the command line using --set, or --set-string for literal strings
+type SetArg struct {
+	Name      string
+	Value     string
+	SetString bool
+}
+
+// ReconfigureValues are the values used to reconfigure a release.  The values of the release are reset to the
+// values file, followed by the current values of the release, the overrides and the set arguments.  The values of
+// the previous overrides and set arguments are removed from the current values, so that the values that were
+// removed from the spec revert to their defaults, while the values that were set by the install are kept.
+type ReconfigureValues struct {
+	// ValuesFile is the values file of the component, empty if the component doesn't have one
+	ValuesFile string
+	// PreviousOverrides are the YAML overrides that were applied by the previous install or reconfigure
+	PreviousOverrides []string
+	// Overrides are YAML values that are merged in order on top of the current values
+	Overrides []string
+	// PreviousSetArgs are the set arguments that were applied by the previous install or reconfigure
+	PreviousSetArgs []SetArg
+	// SetArgs are the set arguments that are applied on top of the other values
+	SetArgs []SetArg
+}
+
+// Client is the interface implemented by the helm clients.  The in-process client uses the helm SDK and is
+// used by default.  The CLI client runs the helm command, and is used when the helm CLI is enabled in the
+// operator config or when a command runner is set by unit tests.
+type Client interface {
+	// Install installs the release, or upgrades the release if it is already installed.  The overrides are YAML
+	// values that are merged in order on top of the values file.
+	Install(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overridesYaml string, overrides ...string) (stdout []byte, stderr []byte, err error)
+
+	// Upgrade upgrades the release with the chart, reusing the existing values.  The overrides are YAML values
+	// that are merged in order on top of the values file.
+	Upgrade(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overwriteYaml string, overrides ...string) (stdout []byte, stderr []byte, err error)
+
+	// Reconfigure upgrades the release with the values file, the current values without the values of the
+	// previous overrides and set arguments, the overrides and the set arguments
+	Reconfigure(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, values ReconfigureValues) (stdout []byte, stderr []byte, err error)
+
+	// Uninstall uninstalls the release
+	Uninstall(log *zap.SugaredLogger, releaseName string, namespace string) (stdout []byte, stderr []byte, err error)
+
+	// Rollback rolls back the release to the revision, or to the previous revision if the revision is 0
+	Rollback(log *zap.SugaredLogger, releaseName string, namespace string, revision int) (stdout []byte, stderr []byte, err error)
+
+	// IsReleaseInstalled returns true if the release is installed
+	IsReleaseInstalled(releaseName string, namespace string) (bool, error)
+
+	// GetReleaseInfo returns the release info, or an error wrapping ErrReleaseNotFound if the release doesn't exist
+	GetReleaseInfo(releaseName string, namespace string) (*ReleaseInfo, error)
+
+	// GetValues returns the values that were supplied for the current revision of the release
+	GetValues(releaseName string, namespace string) (map[string]interface{}, error)
+
+	// History returns the release info of each revision of the release, oldest first
+	History(releaseName string, namespace string) ([]ReleaseInfo, error)
+}
+
+// cmdRunner needed for unit tests
+var runner vz_os.CmdRunner = vz_os.DefaultRunner{}
+
+// client is the helm client set by unit tests, nil if the default client is used
+var client Client
+
+// getClient returns the helm client
+func getClient() Client {
+	if client != nil {
+		return client
+	}
+	if config.Get().HelmCLIEnabled {
+		return cliClient{}
+	}
+	return sdkClient{}
+}
+
+// Upgrade will upgrade a Helm release with the specified charts.  The overrides are YAML values that are
+// merged in order on top of the values in the overwrite file.
+func Upgrade(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overwriteYaml string, overrides ...string) (stdout []byte, stderr []byte, err error) {
+	defer observeCommand("upgrade", time.Now(), &err)
+	return getClient().Upgrade(log, releaseName, namespace, chartDir, overwriteYaml, overrides...)
+}
+
+// Reconfigure will upgrade a Helm release with the reconfigure values.  The values of the release are reset, so
+// that the values of the previous overrides and set arguments that are no longer specified are removed.
+func Reconfigure(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, values ReconfigureValues) (stdout []byte, stderr []byte, err error) {
+	defer observeCommand("reconfigure", time.Now(), &err)
+	return getClient().Reconfigure(log, releaseName, namespace, chartDir, values)
+}
+
+// Install will install a Helm release with the specified chart.  A release that is already installed is
+// upgraded, so that an install that was interrupted can be safely retried.  The overrides are YAML values
+// that are merged in order on top of the values in the overrides file.
+func Install(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overridesYaml string, overrides ...string) (stdout []byte, stderr []byte, err error) {
+	defer observeCommand("install", time.Now(), &err)
+	return getClient().Install(log, releaseName, namespace, chartDir, overridesYaml, overrides...)
+}
+
+// Rollback will roll back a Helm release to the specified revision.  If the revision is 0 then the
+// release is rolled back to the previous revision.
+func Rollback(log *zap.SugaredLogger, releaseName string, namespace string, revision int) (stdout []byte, stderr []byte, err error) {
+	defer observeCommand("rollback", time.Now(), &err)
+	return getClient().Rollback(log, releaseName, namespace, revision)
+}
+
+// Uninstall will uninstall a Helm release
+func Uninstall(log *zap.SugaredLogger, releaseName string, namespace string) (stdout []byte, stderr []byte, err error) {
+	defer observeCommand("uninstall", time.Now(), &err)
+	return getClient().Uninstall(log, releaseName, namespace)
+}
+
+// IsReleaseInstalled returns true if the release is installed
+func IsReleaseInstalled(releaseName string, namespace string) (found bool, err error) {
+	defer observeCommand("status", time.Now(), &err)
+	return getClient().IsReleaseInstalled(releaseName, namespace)
+}
+
+// IsReleaseDeployed returns true if the release is installed and has a deployed status
+func IsReleaseDeployed(releaseName string, namespace string) (found bool, err error) {
+	info, err := GetReleaseInfo(releaseName, namespace)
+	if err != nil || info == nil {
+		return false, err
+	}
+	return info.Status == ReleaseStatusDeployed, nil
+}
+
+// GetReleaseInfo returns the status, revision and chart version of the release.  Nil is returned
+// if the release is not installed.
+func GetReleaseInfo(releaseName string, namespace string) (*ReleaseInfo, error) {
+	start := time.Now()
+	info, err := getClient().GetReleaseInfo(releaseName, namespace)
+	observeCommand("status", start, &err)
+	if errors.Is(err, ErrReleaseNotFound) {
+		return nil, nil
+	}
+	return info, err
+}
+
+// GetValues returns the values that were supplied for the current revision of the release
+func GetValues(releaseName string, namespace string) (values map[string]interface{}, err error) {
+	defer observeCommand("get-values", time.Now(), &err)
+	return getClient().GetValues(releaseName, namespace)
+}
+
+// History returns the release info of each revision of the release, oldest first
+func History(releaseName string, namespace string) (history []ReleaseInfo, err error) {
+	defer observeCommand("history", time.Now(), &err)
+	return getClient().History(releaseName, namespace)
+}
+
+// observeCommand records the latency and the exit status of a helm command that was started at the start time.
+// The exit status of the in-process client is 0 when the command succeeds and 1 when it fails.
+func observeCommand(command string, start time.Time, err *error) {
+	metrics.ObserveHelmCommand(command, exitStatus(*err), time.Since(start))
+}
+
+// exitStatus returns the exit status of a helm command that returned the error
+func exitStatus(err error) string {
+	if err == nil {
+		return "0"
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return strconv.Itoa(exitErr.ExitCode())
+	}
+	return "1"
+}
+
+// SetClient sets the helm client as needed by unit tests
+func SetClient(c Client) {
+	client = c
+}
+
+// SetDefaultClient sets the helm client to default
+func SetDefaultClient() {
+	client = nil
+}
+
+// SetCmdRunner sets the command runner as needed by unit tests.  The CLI client is used with the runner.
+func SetCmdRunner(r vz_os.CmdRunner) {
+	runner = r
+	client = cliClient{}
+}
+
+// SetDefaultRunner sets the command runner and the helm client to default
+func SetDefaultRunner() {
+	runner = vz_os.DefaultRunner{}
+	client = nil
+}
+
+// SetArgsValues returns the YAML helm values of the set arguments, so that they can be passed as an override.  The
+// values are merged on top of the values that come before them, the same as the --set arguments of the helm command.
+func SetArgsValues(args []SetArg) (string, error) {
+	vals := map[string]interface{}{}
+	if err := parseSetArgs(args, vals); err != nil {
+		return "", err
+	}
+	// JSON is valid YAML
+	values, err := json.Marshal(vals)
+	if err != nil {
+		return "", err
+	}
+	return string(values), nil
+}
+
+// parseSetArgs parses the set arguments into the values
+func parseSetArgs(args []SetArg, vals map[string]interface{}) error {
+	for _, arg := range args {
+		parse := strvals.ParseInto
+		if arg.SetString {
+			parse = strvals.ParseIntoString
+		}
+		if err := parse(arg.Name+"="+arg.Value, vals); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getCurrentValues returns the current values of the release without the values of the previous overrides and
+// set arguments
+func getCurrentValues(c Client, releaseName string, namespace string, values ReconfigureValues) (map[string]interface{}, error) {
+	current, err := c.GetValues(releaseName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	previous := map[string]interface{}{}
+	for _, override := range values.PreviousOverrides {
+		overrideVals, err := chartutil.ReadValues([]byte(override))
+		if err != nil {
+			return nil, err
+		}
+		previous = mergeValues(previous, overrideVals)
+	}
+	// Only the names of the previous set arguments are needed to remove their values
+	for _, arg := range values.PreviousSetArgs {
+		if err := strvals.ParseIntoString(arg.Name+"=", previous); err != nil {
+			return nil, err
+		}
+	}
+	removeValues(current, previous)
+	return current, nil
+}
+
+// removeValues removes the values that are set in the removed values.  Nested maps are removed recursively and
+// are removed when they become empty, any other value is removed as a whole.
+func removeValues(vals map[string]interface{}, removed map[string]interface{}) {
+	for k, v := range removed {
+		if removedMap, ok := v.(map[string]interface{}); ok {
+			if valsMap, ok := vals[k].(map[string]interface{}); ok {
+				removeValues(valsMap, removedMap)
+				if len(valsMap) > 0 {
+					continue
+				}
+			}
+		}
+		delete(vals, k)
+	}
+}
@@ -4,18 +4,48 @@
 package helm
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
-	vz_os "github.com/verrazzano/verrazzano/platform-operator/internal/util/os"
 	"go.uber.org/zap"
+	"helm.sh/helm/v3/pkg/storage/driver"
 )
 
-// cmdRunner needed for unit tests
-var runner vz_os.CmdRunner = vz_os.DefaultRunner{}
+// cliClient is the helm client that runs the helm command using the command runner
+type cliClient struct{}
 
-// Upgrade will upgrade a Helm release with the specified charts.
-func Upgrade(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overwriteYaml string) (stdout []byte, stderr []byte, err error) {
+// Verify that cliClient implements Client
+var _ Client = cliClient{}
+
+// releaseStatus contains the subset of the helm status JSON output needed by the operator
+type releaseStatus struct {
+	Version int `json:"version"`
+	Info    struct {
+		Status string `json:"status"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Version string `json:"version"`
+		} `json:"metadata"`
+	} `json:"chart"`
+}
+
+// releaseHistory contains the subset of the helm history JSON output needed by the operator
+type releaseHistory struct {
+	Revision int    `json:"revision"`
+	Status   string `json:"status"`
+	Chart    string `json:"chart"`
+}
+
+// Upgrade will upgrade a Helm release with the specified charts.  The overrides are written to temporary
+// values files that are passed after the overwrite file, so that helm merges them in order.
+func (c cliClient) Upgrade(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overwriteYaml string, overrides ...string) (stdout []byte, stderr []byte, err error) {
 	// Helm upgrade command will apply the new chart, but use all the existing
 	// overrides that we used during the install.
 	args := []string{"upgrade", releaseName, chartDir}
@@ -24,17 +54,22 @@ func Upgrade(log *zap.SugaredLogger, releaseName string, namespace string, chart
 		args = append(args, namespace)
 	}
 
-	if overwriteYaml != "" {
+	if overwriteYaml != "" || len(overrides) > 0 {
 		args = append(args, "--reuse-values")
-		args = append(args, "-f")
-		args = append(args, overwriteYaml)
 	}
+	valuesArgs, cleanup, err := valuesFileArgs(overwriteYaml, overrides)
+	defer cleanup()
+	if err != nil {
+		log.Errorf("helm upgrade for release %s failed writing the overrides: %v", releaseName, err)
+		return nil, nil, newReleaseError("upgrade", releaseName, namespace, err)
+	}
+	args = append(args, valuesArgs...)
 
 	cmd := exec.Command("helm", args...)
 	stdout, stderr, err = runner.Run(cmd)
 	if err != nil {
 		log.Errorf("helm upgrade for release %s failed with stderr: %s\n", releaseName, string(stderr))
-		return stdout, stderr, err
+		return stdout, stderr, newCLIReleaseError("upgrade", releaseName, namespace, stderr, err)
 	}
 
 	//  Log upgrade output
@@ -42,8 +77,130 @@ func Upgrade(log *zap.SugaredLogger, releaseName string, namespace string, chart
 	return stdout, stderr, nil
 }
 
+// Reconfigure will upgrade a Helm release with the values file, the current values of the release without the
+// values of the previous overrides and set arguments, the overrides and the set arguments.  The values of the
+// release are reset, so that the values of the previous overrides and set arguments that are no longer specified
+// are removed.
+func (c cliClient) Reconfigure(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, values ReconfigureValues) (stdout []byte, stderr []byte, err error) {
+	current, err := getCurrentValues(c, releaseName, namespace, values)
+	if err != nil {
+		log.Errorf("helm upgrade for release %s failed getting the current values: %v", releaseName, err)
+		return nil, nil, newReleaseError("upgrade", releaseName, namespace, err)
+	}
+	// JSON is valid YAML, so the current values are passed as a values file
+	currentYaml, err := json.Marshal(current)
+	if err != nil {
+		return nil, nil, newReleaseError("upgrade", releaseName, namespace, err)
+	}
+
+	args := []string{"upgrade", releaseName, chartDir, "--reset-values"}
+	if namespace != "" {
+		args = append(args, "--namespace")
+		args = append(args, namespace)
+	}
+	valuesArgs, cleanup, err := valuesFileArgs(values.ValuesFile, append([]string{string(currentYaml)}, values.Overrides...))
+	defer cleanup()
+	if err != nil {
+		log.Errorf("helm upgrade for release %s failed writing the values: %v", releaseName, err)
+		return nil, nil, newReleaseError("upgrade", releaseName, namespace, err)
+	}
+	args = append(args, valuesArgs...)
+	for _, arg := range values.SetArgs {
+		if arg.SetString {
+			args = append(args, "--set-string")
+		} else {
+			args = append(args, "--set")
+		}
+		args = append(args, arg.Name+"="+arg.Value)
+	}
+
+	cmd := exec.Command("helm", args...)
+	stdout, stderr, err = runner.Run(cmd)
+	if err != nil {
+		log.Errorf("helm upgrade for release %s failed with stderr: %s\n", releaseName, string(stderr))
+		return stdout, stderr, newCLIReleaseError("upgrade", releaseName, namespace, stderr, err)
+	}
+
+	//  Log upgrade output
+	log.Infof("helm upgrade for release %s succeeded with stdout: %s\n", releaseName, string(stdout))
+	return stdout, stderr, nil
+}
+
+// Install will install a Helm release with the specified chart.  The upgrade command is used with the
+// install flag so that an install that was interrupted can be safely retried.
+func (c cliClient) Install(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overridesYaml string, overrides ...string) (stdout []byte, stderr []byte, err error) {
+	args := []string{"upgrade", releaseName, chartDir, "--install"}
+	if namespace != "" {
+		args = append(args, "--namespace")
+		args = append(args, namespace)
+	}
+
+	valuesArgs, cleanup, err := valuesFileArgs(overridesYaml, overrides)
+	defer cleanup()
+	if err != nil {
+		log.Errorf("helm install for release %s failed writing the overrides: %v", releaseName, err)
+		return nil, nil, newReleaseError("install", releaseName, namespace, err)
+	}
+	args = append(args, valuesArgs...)
+
+	cmd := exec.Command("helm", args...)
+	stdout, stderr, err = runner.Run(cmd)
+	if err != nil {
+		log.Errorf("helm install for release %s failed with stderr: %s\n", releaseName, string(stderr))
+		return stdout, stderr, newCLIReleaseError("install", releaseName, namespace, stderr, err)
+	}
+
+	//  Log install output
+	log.Infof("helm install for release %s succeeded with stdout: %s\n", releaseName, string(stdout))
+	return stdout, stderr, nil
+}
+
+// Rollback will roll back a Helm release to the specified revision.  If the revision is 0 then the
+// release is rolled back to the previous revision.
+func (c cliClient) Rollback(log *zap.SugaredLogger, releaseName string, namespace string, revision int) (stdout []byte, stderr []byte, err error) {
+	args := []string{"rollback", releaseName}
+	if revision > 0 {
+		args = append(args, strconv.Itoa(revision))
+	}
+	if namespace != "" {
+		args = append(args, "--namespace")
+		args = append(args, namespace)
+	}
+
+	cmd := exec.Command("helm", args...)
+	stdout, stderr, err = runner.Run(cmd)
+	if err != nil {
+		log.Errorf("helm rollback for release %s failed with stderr: %s\n", releaseName, string(stderr))
+		return stdout, stderr, newCLIReleaseError("rollback", releaseName, namespace, stderr, err)
+	}
+
+	//  Log rollback output
+	log.Infof("helm rollback for release %s succeeded with stdout: %s\n", releaseName, string(stdout))
+	return stdout, stderr, nil
+}
+
+// Uninstall will uninstall a Helm release
+func (c cliClient) Uninstall(log *zap.SugaredLogger, releaseName string, namespace string) (stdout []byte, stderr []byte, err error) {
+	args := []string{"uninstall", releaseName}
+	if namespace != "" {
+		args = append(args, "--namespace")
+		args = append(args, namespace)
+	}
+
+	cmd := exec.Command("helm", args...)
+	stdout, stderr, err = runner.Run(cmd)
+	if err != nil {
+		log.Errorf("helm uninstall for release %s failed with stderr: %s\n", releaseName, string(stderr))
+		return stdout, stderr, newCLIReleaseError("uninstall", releaseName, namespace, stderr, err)
+	}
+
+	//  Log uninstall output
+	log.Infof("helm uninstall for release %s succeeded with stdout: %s\n", releaseName, string(stdout))
+	return stdout, stderr, nil
+}
+
 // IsReleaseInstalled returns true if the release is installed
-func IsReleaseInstalled(releaseName string, namespace string) (found bool, err error) {
+func (c cliClient) IsReleaseInstalled(releaseName string, namespace string) (found bool, err error) {
 	log := zap.S()
 
 	args := []string{"status", releaseName}
@@ -56,19 +213,137 @@ func IsReleaseInstalled(releaseName string, namespace string) (found bool, err e
 	if err == nil {
 		return true, nil
 	}
-	if strings.Contains(string(stderr), "not found") {
+	err = newCLIReleaseError("status", releaseName, namespace, stderr, err)
+	if errors.Is(err, ErrReleaseNotFound) {
 		return false, nil
 	}
 	log.Errorf("helm status for release %s failed with stderr: %s\n", releaseName, string(stderr))
 	return false, err
 }
 
-// SetCmdRunner sets the command runner as needed by unit tests
-func SetCmdRunner(r vz_os.CmdRunner) {
-	runner = r
+// GetReleaseInfo returns the status, revision and chart version of the release.  A ReleaseError wrapping
+// ErrReleaseNotFound is returned if the release is not installed.
+func (c cliClient) GetReleaseInfo(releaseName string, namespace string) (*ReleaseInfo, error) {
+	log := zap.S()
+
+	args := []string{"status", releaseName, "--output", "json"}
+	if namespace != "" {
+		args = append(args, "--namespace")
+		args = append(args, namespace)
+	}
+	stdout, err := c.runJSON("status", releaseName, namespace, args)
+	if err != nil {
+		return nil, err
+	}
+	status := releaseStatus{}
+	if err := json.Unmarshal(stdout, &status); err != nil {
+		log.Errorf("helm status for release %s returned invalid output: %v", releaseName, err)
+		return nil, newReleaseError("status", releaseName, namespace, err)
+	}
+	return &ReleaseInfo{
+		Status:       status.Info.Status,
+		Revision:     status.Version,
+		ChartVersion: status.Chart.Metadata.Version,
+	}, nil
+}
+
+// GetValues returns the values that were supplied for the current revision of the release
+func (c cliClient) GetValues(releaseName string, namespace string) (map[string]interface{}, error) {
+	args := []string{"get", "values", releaseName, "--output", "json"}
+	if namespace != "" {
+		args = append(args, "--namespace")
+		args = append(args, namespace)
+	}
+	stdout, err := c.runJSON("get values", releaseName, namespace, args)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(stdout, &values); err != nil {
+		return nil, newReleaseError("get values", releaseName, namespace, err)
+	}
+	return values, nil
+}
+
+// History returns the release info of each revision of the release, oldest first
+func (c cliClient) History(releaseName string, namespace string) ([]ReleaseInfo, error) {
+	args := []string{"history", releaseName, "--output", "json"}
+	if namespace != "" {
+		args = append(args, "--namespace")
+		args = append(args, namespace)
+	}
+	stdout, err := c.runJSON("history", releaseName, namespace, args)
+	if err != nil {
+		return nil, err
+	}
+	var history []releaseHistory
+	if err := json.Unmarshal(stdout, &history); err != nil {
+		return nil, newReleaseError("history", releaseName, namespace, err)
+	}
+	infos := make([]ReleaseInfo, 0, len(history))
+	for _, h := range history {
+		// The chart is the chart name and version, for example keycloak-8.2.2
+		chartVersion := h.Chart[strings.LastIndex(h.Chart, "-")+1:]
+		infos = append(infos, ReleaseInfo{Status: h.Status, Revision: h.Revision, ChartVersion: chartVersion})
+	}
+	return infos, nil
+}
+
+// runJSON runs a helm command that returns JSON output.  A ReleaseError wrapping ErrReleaseNotFound is returned
+// if the release is not installed.
+func (c cliClient) runJSON(operation string, releaseName string, namespace string, args []string) ([]byte, error) {
+	cmd := exec.Command("helm", args...)
+	stdout, stderr, err := runner.Run(cmd)
+	if err != nil {
+		err = newCLIReleaseError(operation, releaseName, namespace, stderr, err)
+		if !errors.Is(err, ErrReleaseNotFound) {
+			zap.S().Errorf("helm %s for release %s failed with stderr: %s\n", operation, releaseName, string(stderr))
+		}
+		return nil, err
+	}
+	return stdout, nil
+}
+
+// newCLIReleaseError creates a ReleaseError for a helm command that failed.  The error wraps ErrReleaseNotFound
+// if helm reported that the release doesn't exist, the same as the in-process client.  Otherwise the error of
+// the command is wrapped with the stderr of the command.
+func newCLIReleaseError(operation string, releaseName string, namespace string, stderr []byte, err error) error {
+	msg := strings.TrimSpace(string(stderr))
+	switch {
+	case strings.Contains(msg, driver.ErrReleaseNotFound.Error()):
+		err = ErrReleaseNotFound
+	case msg != "":
+		err = fmt.Errorf("%w: %s", err, msg)
+	}
+	return &ReleaseError{Operation: operation, Release: releaseName, Namespace: namespace, Err: err}
 }
 
-// SetDefaultRunner sets the command runner to default
-func SetDefaultRunner() {
-	runner = vz_os.DefaultRunner{}
+// valuesFileArgs returns the -f arguments for the values file and the overrides, in order.  Each override is
+// written to a temporary file, which is removed by the returned cleanup function.
+func valuesFileArgs(valuesFile string, overrides []string) (args []string, cleanup func(), err error) {
+	var files []string
+	cleanup = func() {
+		for _, f := range files {
+			os.Remove(f)
+		}
+	}
+	if valuesFile != "" {
+		args = append(args, "-f", valuesFile)
+	}
+	for _, override := range overrides {
+		f, err := ioutil.TempFile("", "helm-overrides-*.yaml")
+		if err != nil {
+			return nil, cleanup, err
+		}
+		files = append(files, f.Name())
+		_, err = f.WriteString(override)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, cleanup, err
+		}
+		args = append(args, "-f", f.Name())
+	}
+	return args, cleanup, nil
 }
@@ -5,6 +5,8 @@ package helm
 
 import (
 	"errors"
+	"io/ioutil"
+	"os"
 	"os/exec"
 	"testing"
 
@@ -34,6 +36,17 @@ type foundRunner struct {
 	t *testing.T
 }
 
+// installRunner is used to test the helm install and uninstall commands
+type installRunner struct {
+	t *testing.T
+}
+
+// statusRunner is used to test the helm status command with JSON output
+type statusRunner struct {
+	t      *testing.T
+	status string
+}
+
 // TestUpgrade tests the Helm upgrade command
 // GIVEN a set of upgrade parameters
 //  WHEN I call Upgrade
@@ -49,6 +62,26 @@ func TestUpgrade(t *testing.T) {
 	assert.NotZero(stdout, "Upgrade stdout should not be empty")
 }
 
+// TestUpgradeOverrides tests the Helm upgrade command with overrides
+// GIVEN an overwrite file and YAML overrides
+//  WHEN I call Upgrade
+//  THEN the overrides are passed as values files after the overwrite file, and the files are removed afterwards
+func TestUpgradeOverrides(t *testing.T) {
+	assert := assert.New(t)
+	runner := &overridesRunner{t: t}
+	SetCmdRunner(runner)
+	defer SetDefaultRunner()
+
+	_, _, err := Upgrade(zap.S(), release, ns, chartdir, overrideYaml, "a: 1", "b: 2")
+	assert.NoError(err, "Upgrade returned an error")
+	assert.Contains(runner.args, "--reuse-values", "args should contain the reuse values flag")
+	assert.Equal([]string{overrideYaml, "a: 1", "b: 2"}, runner.values, "Incorrect values files")
+	for _, f := range runner.files[1:] {
+		_, err := os.Stat(f)
+		assert.True(os.IsNotExist(err), "The overrides file should be removed")
+	}
+}
+
 // TestUpgradeFail tests the Helm upgrade command failure condition
 // GIVEN a set of upgrade parameters and a fake runner that fails
 //  WHEN I call Upgrade
@@ -64,6 +97,149 @@ func TestUpgradeFail(t *testing.T) {
 	assert.NotZero(stderr, "Upgrade stderr should not be empty")
 }
 
+// TestInstall tests the Helm install command
+// GIVEN a set of install parameters
+//  WHEN I call Install
+//  THEN the Helm upgrade command is called with the install flag and returns success
+func TestInstall(t *testing.T) {
+	assert := assert.New(t)
+	SetCmdRunner(installRunner{t: t})
+	defer SetDefaultRunner()
+
+	stdout, stderr, err := Install(zap.S(), release, ns, chartdir, overrideYaml)
+	assert.NoError(err, "Install returned an error")
+	assert.Len(stderr, 0, "Install stderr should be empty")
+	assert.NotZero(stdout, "Install stdout should not be empty")
+}
+
+// TestInstallOverrides tests the Helm install command with overrides
+// GIVEN YAML overrides and no overrides file
+//  WHEN I call Install
+//  THEN the overrides are passed as values files in order
+func TestInstallOverrides(t *testing.T) {
+	assert := assert.New(t)
+	runner := &overridesRunner{t: t}
+	SetCmdRunner(runner)
+	defer SetDefaultRunner()
+
+	_, _, err := Install(zap.S(), release, ns, chartdir, "", "a: 1", "b: 2")
+	assert.NoError(err, "Install returned an error")
+	assert.Contains(runner.args, "--install", "args should contain the install flag")
+	assert.Equal([]string{"a: 1", "b: 2"}, runner.values, "Incorrect values files")
+}
+
+// TestInstallFail tests the Helm install command failure condition
+// GIVEN a set of install parameters and a fake runner that fails
+//  WHEN I call Install
+//  THEN the Helm install returns an error
+func TestInstallFail(t *testing.T) {
+	assert := assert.New(t)
+	SetCmdRunner(badRunner{t: t})
+	defer SetDefaultRunner()
+
+	stdout, stderr, err := Install(zap.S(), release, ns, chartdir, "")
+	assert.Error(err, "Install should have returned an error")
+	assert.Len(stdout, 0, "Install stdout should be empty")
+	assert.NotZero(stderr, "Install stderr should not be empty")
+}
+
+// TestReconfigure tests the Helm upgrade command with set arguments
+// GIVEN a release name, namespace, chart dir, the previous set arguments and the set arguments
+//  WHEN I call Reconfigure
+//  THEN the Helm upgrade command resets the values, passes the values file followed by the current values without
+//   the values of the previous set arguments, and passes the set arguments
+func TestReconfigure(t *testing.T) {
+	assert := assert.New(t)
+	runner := &reconfigureRunner{t: t, values: `{"a":{"b":2,"d":3},"e":"x","f":{"g":"y"}}`}
+	SetCmdRunner(runner)
+	defer SetDefaultRunner()
+
+	stdout, _, err := Reconfigure(zap.S(), release, ns, chartdir, ReconfigureValues{
+		ValuesFile:      overrideYaml,
+		PreviousSetArgs: []SetArg{{Name: "a.b", Value: "2"}, {Name: "f.g", Value: "y"}},
+		SetArgs:         []SetArg{{Name: "a.b", Value: "1"}, {Name: "c", Value: "true", SetString: true}},
+	})
+	assert.NoError(err, "Reconfigure returned an error")
+	assert.NotZero(stdout, "Reconfigure stdout should not be empty")
+	assert.Equal([]string{"helm", "upgrade", release, chartdir, "--reset-values", "--namespace", ns,
+		"-f", overrideYaml, "-f", runner.args[10], "--set", "a.b=1", "--set-string", "c=true"}, runner.args, "Incorrect helm arguments")
+	assert.JSONEq(`{"a":{"d":3},"e":"x"}`, runner.currentValues, "The values of the previous set arguments should be removed")
+}
+
+// TestUninstall tests the Helm uninstall command
+// GIVEN a release name and namespace
+//  WHEN I call Uninstall
+//  THEN the Helm uninstall command is called and returns success
+func TestUninstall(t *testing.T) {
+	assert := assert.New(t)
+	SetCmdRunner(installRunner{t: t})
+	defer SetDefaultRunner()
+
+	stdout, _, err := Uninstall(zap.S(), release, ns)
+	assert.NoError(err, "Uninstall returned an error")
+	assert.NotZero(stdout, "Uninstall stdout should not be empty")
+}
+
+// TestRollback tests the Helm rollback command
+// GIVEN a release name, namespace and revision
+//  WHEN I call Rollback
+//  THEN the Helm rollback command is called with the revision and returns success
+func TestRollback(t *testing.T) {
+	assert := assert.New(t)
+	SetCmdRunner(installRunner{t: t})
+	defer SetDefaultRunner()
+
+	stdout, _, err := Rollback(zap.S(), release, ns, 2)
+	assert.NoError(err, "Rollback returned an error")
+	assert.NotZero(stdout, "Rollback stdout should not be empty")
+}
+
+// TestIsReleaseDeployed tests checking if a Helm release is deployed
+// GIVEN a release name and namespace
+//  WHEN I call IsReleaseDeployed
+//  THEN the function returns true only if the release status is deployed
+func TestIsReleaseDeployed(t *testing.T) {
+	assert := assert.New(t)
+	defer SetDefaultRunner()
+
+	SetCmdRunner(statusRunner{t: t, status: ReleaseStatusDeployed})
+	deployed, err := IsReleaseDeployed(release, ns)
+	assert.NoError(err, "IsReleaseDeployed returned an error")
+	assert.True(deployed, "Release should be deployed")
+
+	SetCmdRunner(statusRunner{t: t, status: "pending-install"})
+	deployed, err = IsReleaseDeployed(release, ns)
+	assert.NoError(err, "IsReleaseDeployed returned an error")
+	assert.False(deployed, "Release should not be deployed")
+
+	SetCmdRunner(foundRunner{t: t})
+	deployed, err = IsReleaseDeployed(missingRelease, ns)
+	assert.NoError(err, "IsReleaseDeployed returned an error")
+	assert.False(deployed, "Release should not be found")
+}
+
+// TestGetReleaseInfo tests getting the status, revision and chart version of a Helm release
+// GIVEN a release name and namespace
+//  WHEN I call GetReleaseInfo
+//  THEN the function returns the release info, or nil if the release is not installed
+func TestGetReleaseInfo(t *testing.T) {
+	assert := assert.New(t)
+	defer SetDefaultRunner()
+
+	SetCmdRunner(statusRunner{t: t, status: ReleaseStatusDeployed})
+	info, err := GetReleaseInfo(release, ns)
+	assert.NoError(err, "GetReleaseInfo returned an error")
+	assert.NotNil(info, "Release info should be returned")
+	assert.Equal(ReleaseStatusDeployed, info.Status, "Incorrect release status")
+	assert.Equal(3, info.Revision, "Incorrect release revision")
+	assert.Equal("1.2.3", info.ChartVersion, "Incorrect chart version")
+
+	SetCmdRunner(foundRunner{t: t})
+	info, err = GetReleaseInfo(missingRelease, ns)
+	assert.NoError(err, "GetReleaseInfo returned an error")
+	assert.Nil(info, "Release should not be found")
+}
+
 // TestIsReleaseInstalled tests checking if a Helm release is installed
 // GIVEN a release name and namespace
 //  WHEN I call IsReleaseInstalled
@@ -106,6 +282,87 @@ func TestIsReleaseInstalledFailed(t *testing.T) {
 	assert.False(found, "Release should not be found")
 }
 
+// TestGetValues tests the helm get values command
+// GIVEN a release that is installed with values
+//  WHEN I call GetValues
+//  THEN the values of the release are returned
+func TestGetValues(t *testing.T) {
+	assert := assert.New(t)
+	SetCmdRunner(jsonRunner{t: t, output: `{"a":{"b":1},"c":"true"}`})
+	defer SetDefaultRunner()
+
+	values, err := GetValues(release, ns)
+	assert.NoError(err, "GetValues returned an error")
+	assert.Equal(map[string]interface{}{"a": map[string]interface{}{"b": float64(1)}, "c": "true"}, values)
+}
+
+// TestHistory tests the helm history command
+// GIVEN a release that has been upgraded
+//  WHEN I call History
+//  THEN the status, revision and chart version of each revision are returned
+func TestHistory(t *testing.T) {
+	assert := assert.New(t)
+	SetCmdRunner(jsonRunner{t: t, output: `[{"revision":1,"status":"superseded","chart":"my-chart-1.2.3"},` +
+		`{"revision":2,"status":"deployed","chart":"my-chart-1.2.4"}]`})
+	defer SetDefaultRunner()
+
+	history, err := History(release, ns)
+	assert.NoError(err, "History returned an error")
+	assert.Equal([]ReleaseInfo{{Status: "superseded", Revision: 1, ChartVersion: "1.2.3"},
+		{Status: ReleaseStatusDeployed, Revision: 2, ChartVersion: "1.2.4"}}, history)
+}
+
+// TestGetReleaseInfoNotFound tests the helm status command for a release that is not installed
+// GIVEN a release that is not installed
+//  WHEN I call GetReleaseInfo on the CLI client
+//  THEN a ReleaseError wrapping ErrReleaseNotFound is returned
+func TestGetReleaseInfoNotFound(t *testing.T) {
+	assert := assert.New(t)
+	SetCmdRunner(foundRunner{t: t})
+	defer SetDefaultRunner()
+
+	_, err := getClient().GetReleaseInfo(missingRelease, ns)
+	var releaseErr *ReleaseError
+	assert.True(errors.As(err, &releaseErr), "Error should be a ReleaseError")
+	assert.Equal("status", releaseErr.Operation)
+	assert.True(errors.Is(err, ErrReleaseNotFound), "Error should wrap ErrReleaseNotFound")
+}
+
+// TestCLIReleaseError tests the errors of the helm commands that fail
+// GIVEN a helm command that fails for a reason other than a missing release
+//  WHEN I call the CLI client
+//  THEN a ReleaseError is returned with the stderr of the command and the error of the command
+func TestCLIReleaseError(t *testing.T) {
+	assert := assert.New(t)
+	SetCmdRunner(badRunner{t: t})
+	defer SetDefaultRunner()
+
+	_, _, err := Upgrade(zap.S(), release, ns, chartdir, "")
+	var releaseErr *ReleaseError
+	assert.True(errors.As(err, &releaseErr), "Error should be a ReleaseError")
+	assert.Equal("upgrade", releaseErr.Operation)
+	assert.Equal(release, releaseErr.Release)
+	assert.Contains(err.Error(), "error: error", "Error should contain the error and stderr of the command")
+	assert.False(errors.Is(err, ErrReleaseNotFound), "Error should not wrap ErrReleaseNotFound")
+
+	_, err = History(release, ns)
+	assert.True(errors.As(err, &releaseErr), "Error should be a ReleaseError")
+	assert.Equal("history", releaseErr.Operation)
+}
+
+// TestExitStatus tests the exit status of the helm commands that is recorded in the metrics
+// GIVEN the error returned by a helm command
+//  WHEN I call exitStatus
+//  THEN the exit code of the command is returned, or 1 if the command failed without an exit code
+func TestExitStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("0", exitStatus(nil))
+	assert.Equal("1", exitStatus(&ReleaseError{Operation: "upgrade", Release: release, Namespace: ns, Err: errors.New("error")}))
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	assert.Equal("3", exitStatus(err))
+}
+
 // Run should assert the command parameters are correct then return a success with stdout contents
 func (r goodRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
 	assert := assert.New(r.t)
@@ -134,8 +391,105 @@ func (r foundRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error
 		return []byte(""), []byte(""), nil
 	}
 	if cmd.Args[2] == missingRelease {
-		return []byte(""), []byte("not found"), errors.New("not found error")
+		return []byte(""), []byte("Error: release: not found"), errors.New("not found error")
 	}
 	// simulate a Helm error
 	return []byte(""), []byte("error"), errors.New("helm error")
 }
+
+// Run should assert the command parameters are correct for install and uninstall, then return a success
+func (r installRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	assert := assert.New(r.t)
+	assert.Contains(cmd.Path, "helm", "command should contain helm")
+	assert.Contains(cmd.Args[2], release, "args should contain release name")
+	switch cmd.Args[1] {
+	case "upgrade":
+		assert.Equal(chartdir, cmd.Args[3], "args should contain chart dir")
+		assert.Contains(cmd.Args, "--install", "args should contain the install flag")
+	case "uninstall":
+		assert.Contains(cmd.Args, ns, "args should contain namespace")
+	case "rollback":
+		assert.Contains(cmd.Args, ns, "args should contain namespace")
+		assert.Equal("2", cmd.Args[3], "args should contain the revision")
+	default:
+		assert.Fail("unexpected helm command " + cmd.Args[1])
+	}
+	return []byte("success"), []byte(""), nil
+}
+
+// Run should return the helm status JSON output with the configured status
+func (r statusRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	assert := assert.New(r.t)
+	assert.Contains(cmd.Args[1], "status", "args should contain status")
+	assert.Contains(cmd.Args, "json", "args should contain the json output format")
+	return []byte(`{"name":"` + release + `","version":3,"info":{"status":"` + r.status + `"},` +
+		`"chart":{"metadata":{"name":"` + release + `","version":"1.2.3"}}}`), []byte(""), nil
+}
+
+// reconfigureRunner is used to test the helm upgrade command with set arguments.  The runner returns the values
+// of the release, and records the arguments of the upgrade command and the contents of the current values file.
+type reconfigureRunner struct {
+	t             *testing.T
+	values        string
+	args          []string
+	currentValues string
+}
+
+// Run returns the values of the release for the get values command, and records the upgrade command
+func (r *reconfigureRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	if cmd.Args[1] == "get" {
+		return []byte(r.values), []byte(""), nil
+	}
+	r.args = cmd.Args
+	for i, arg := range cmd.Args {
+		if arg == "-f" && cmd.Args[i+1] != overrideYaml {
+			data, err := ioutil.ReadFile(cmd.Args[i+1])
+			assert.NoError(r.t, err, "Error reading the values file")
+			r.currentValues = string(data)
+		}
+	}
+	return []byte("success"), []byte(""), nil
+}
+
+// jsonRunner is used to test the helm commands with JSON output
+type jsonRunner struct {
+	t      *testing.T
+	output string
+}
+
+// Run verifies that JSON output is requested and returns the output
+func (r jsonRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	assert := assert.New(r.t)
+	assert.Contains(cmd.Args, release, "args should contain release name")
+	assert.Contains(cmd.Args, "json", "args should contain the json output format")
+	return []byte(r.output), []byte(""), nil
+}
+
+// overridesRunner is used to test the values files passed to the helm command
+type overridesRunner struct {
+	t      *testing.T
+	args   []string
+	files  []string
+	values []string
+}
+
+// Run records the arguments, the values files and their contents.  The contents of the overrides file
+// are not read since it doesn't exist.
+func (r *overridesRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	r.args = cmd.Args
+	for i, arg := range cmd.Args {
+		if arg != "-f" {
+			continue
+		}
+		f := cmd.Args[i+1]
+		r.files = append(r.files, f)
+		if f == overrideYaml {
+			r.values = append(r.values, f)
+			continue
+		}
+		data, err := ioutil.ReadFile(f)
+		assert.NoError(r.t, err, "Error reading the values file")
+		r.values = append(r.values, string(data))
+	}
+	return []byte("success"), []byte(""), nil
+}
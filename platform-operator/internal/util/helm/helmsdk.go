@@ -0,0 +1,272 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package helm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"go.uber.org/zap"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// sdkClient is the helm client that uses the helm SDK in the operator process
+type sdkClient struct{}
+
+// Verify that sdkClient implements Client
+var _ Client = sdkClient{}
+
+// newActionConfigFuncSig is needed for unit test override
+type newActionConfigFuncSig func(log *zap.SugaredLogger, namespace string) (*action.Configuration, error)
+
+// newActionConfigFunc is the function that creates the helm action configuration
+var newActionConfigFunc newActionConfigFuncSig = newActionConfig
+
+// newActionConfig creates the helm action configuration for the namespace.  The in-cluster config of the
+// operator is used, or the kubeconfig when running outside of the cluster.  The releases are stored in
+// secrets, the same as the helm command, unless the HELM_DRIVER environment variable is set.
+func newActionConfig(log *zap.SugaredLogger, namespace string) (*action.Configuration, error) {
+	flags := genericclioptions.NewConfigFlags(true)
+	flags.Namespace = &namespace
+	cfg := &action.Configuration{}
+	if err := cfg.Init(flags, namespace, os.Getenv("HELM_DRIVER"), log.Debugf); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Install will install the release, or upgrade the release if it is already installed
+func (c sdkClient) Install(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overridesYaml string, overrides ...string) (stdout []byte, stderr []byte, err error) {
+	cfg, chrt, vals, err := c.prepare(log, namespace, chartDir, overridesYaml, overrides...)
+	if err != nil {
+		return nil, nil, newReleaseError("install", releaseName, namespace, err)
+	}
+	var rel *helmrelease.Release
+	if _, err = cfg.Releases.Last(releaseName); errors.Is(err, driver.ErrReleaseNotFound) {
+		install := action.NewInstall(cfg)
+		install.ReleaseName = releaseName
+		install.Namespace = namespace
+		rel, err = install.Run(chrt, vals)
+	} else {
+		upgrade := action.NewUpgrade(cfg)
+		upgrade.Namespace = namespace
+		rel, err = upgrade.Run(releaseName, chrt, vals)
+	}
+	return c.result(log, "install", releaseName, namespace, rel, err)
+}
+
+// Upgrade will upgrade the release with the chart.  The existing values are reused, and the values in the
+// overwrite file and the overrides override them.
+func (c sdkClient) Upgrade(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, overwriteYaml string, overrides ...string) (stdout []byte, stderr []byte, err error) {
+	cfg, chrt, vals, err := c.prepare(log, namespace, chartDir, overwriteYaml, overrides...)
+	if err != nil {
+		return nil, nil, newReleaseError("upgrade", releaseName, namespace, err)
+	}
+	upgrade := action.NewUpgrade(cfg)
+	upgrade.Namespace = namespace
+	upgrade.ReuseValues = overwriteYaml != "" || len(overrides) > 0
+	rel, err := upgrade.Run(releaseName, chrt, vals)
+	return c.result(log, "upgrade", releaseName, namespace, rel, err)
+}
+
+// Reconfigure will upgrade the release with the values file, the current values of the release without the
+// values of the previous overrides and set arguments, the overrides and the set arguments.  The values of the
+// release are reset.
+func (c sdkClient) Reconfigure(log *zap.SugaredLogger, releaseName string, namespace string, chartDir string, values ReconfigureValues) (stdout []byte, stderr []byte, err error) {
+	current, err := getCurrentValues(c, releaseName, namespace, values)
+	if err != nil {
+		return nil, nil, newReleaseError("upgrade", releaseName, namespace, err)
+	}
+	currentYaml, err := json.Marshal(current)
+	if err != nil {
+		return nil, nil, newReleaseError("upgrade", releaseName, namespace, err)
+	}
+	cfg, chrt, vals, err := c.prepare(log, namespace, chartDir, values.ValuesFile, append([]string{string(currentYaml)}, values.Overrides...)...)
+	if err != nil {
+		return nil, nil, newReleaseError("upgrade", releaseName, namespace, err)
+	}
+	if err := parseSetArgs(values.SetArgs, vals); err != nil {
+		return nil, nil, newReleaseError("upgrade", releaseName, namespace, err)
+	}
+	upgrade := action.NewUpgrade(cfg)
+	upgrade.Namespace = namespace
+	upgrade.ResetValues = true
+	rel, err := upgrade.Run(releaseName, chrt, vals)
+	return c.result(log, "upgrade", releaseName, namespace, rel, err)
+}
+
+// Uninstall will uninstall the release
+func (c sdkClient) Uninstall(log *zap.SugaredLogger, releaseName string, namespace string) (stdout []byte, stderr []byte, err error) {
+	cfg, err := newActionConfigFunc(log, namespace)
+	if err != nil {
+		return nil, nil, newReleaseError("uninstall", releaseName, namespace, err)
+	}
+	resp, err := action.NewUninstall(cfg).Run(releaseName)
+	if err != nil {
+		err = newReleaseError("uninstall", releaseName, namespace, err)
+		log.Error(err.Error())
+		return nil, []byte(err.Error()), err
+	}
+	log.Infof("helm uninstall for release %s succeeded: %s", releaseName, resp.Info)
+	return []byte(fmt.Sprintf("release \"%s\" uninstalled", releaseName)), nil, nil
+}
+
+// Rollback will roll back the release to the revision, or to the previous revision if the revision is 0
+func (c sdkClient) Rollback(log *zap.SugaredLogger, releaseName string, namespace string, revision int) (stdout []byte, stderr []byte, err error) {
+	cfg, err := newActionConfigFunc(log, namespace)
+	if err != nil {
+		return nil, nil, newReleaseError("rollback", releaseName, namespace, err)
+	}
+	rollback := action.NewRollback(cfg)
+	rollback.Version = revision
+	if err := rollback.Run(releaseName); err != nil {
+		err = newReleaseError("rollback", releaseName, namespace, err)
+		log.Error(err.Error())
+		return nil, []byte(err.Error()), err
+	}
+	log.Infof("helm rollback for release %s succeeded", releaseName)
+	return []byte("Rollback was a success! Happy Helming!"), nil, nil
+}
+
+// IsReleaseInstalled returns true if the release is installed
+func (c sdkClient) IsReleaseInstalled(releaseName string, namespace string) (bool, error) {
+	_, err := c.GetReleaseInfo(releaseName, namespace)
+	if errors.Is(err, ErrReleaseNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// GetReleaseInfo returns the status, revision and chart version of the release
+func (c sdkClient) GetReleaseInfo(releaseName string, namespace string) (*ReleaseInfo, error) {
+	cfg, err := newActionConfigFunc(zap.S(), namespace)
+	if err != nil {
+		return nil, newReleaseError("status", releaseName, namespace, err)
+	}
+	rel, err := action.NewStatus(cfg).Run(releaseName)
+	if err != nil {
+		return nil, newReleaseError("status", releaseName, namespace, err)
+	}
+	info := newReleaseInfo(rel)
+	return &info, nil
+}
+
+// GetValues returns the values that were supplied for the current revision of the release
+func (c sdkClient) GetValues(releaseName string, namespace string) (map[string]interface{}, error) {
+	cfg, err := newActionConfigFunc(zap.S(), namespace)
+	if err != nil {
+		return nil, newReleaseError("get values", releaseName, namespace, err)
+	}
+	values, err := action.NewGetValues(cfg).Run(releaseName)
+	if err != nil {
+		return nil, newReleaseError("get values", releaseName, namespace, err)
+	}
+	return values, nil
+}
+
+// History returns the release info of each revision of the release, oldest first
+func (c sdkClient) History(releaseName string, namespace string) ([]ReleaseInfo, error) {
+	cfg, err := newActionConfigFunc(zap.S(), namespace)
+	if err != nil {
+		return nil, newReleaseError("history", releaseName, namespace, err)
+	}
+	releases, err := action.NewHistory(cfg).Run(releaseName)
+	if err == nil && len(releases) == 0 {
+		err = driver.ErrReleaseNotFound
+	}
+	if err != nil {
+		return nil, newReleaseError("history", releaseName, namespace, err)
+	}
+	infos := make([]ReleaseInfo, len(releases))
+	for i, rel := range releases {
+		infos[i] = newReleaseInfo(rel)
+	}
+	// The releases are not sorted by the storage driver
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Revision < infos[j].Revision })
+	return infos, nil
+}
+
+// prepare creates the action configuration, loads the chart and reads the values file if one is specified.
+// The overrides are merged in order on top of the values from the file.
+func (c sdkClient) prepare(log *zap.SugaredLogger, namespace string, chartDir string, valuesFile string, overrides ...string) (*action.Configuration, *chart.Chart, map[string]interface{}, error) {
+	cfg, err := newActionConfigFunc(log, namespace)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	chrt, err := loader.Load(chartDir)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	vals := map[string]interface{}{}
+	if valuesFile != "" {
+		vals, err = chartutil.ReadValuesFile(valuesFile)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	for _, override := range overrides {
+		overrideVals, err := chartutil.ReadValues([]byte(override))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		vals = mergeValues(vals, overrideVals)
+	}
+	return cfg, chrt, vals, nil
+}
+
+// mergeValues merges the override values into the values, the same way that helm merges multiple values
+// files.  Nested maps are merged, any other override value replaces the value.
+func mergeValues(vals map[string]interface{}, override map[string]interface{}) map[string]interface{} {
+	for k, v := range override {
+		if overrideMap, ok := v.(map[string]interface{}); ok {
+			if valsMap, ok := vals[k].(map[string]interface{}); ok {
+				vals[k] = mergeValues(valsMap, overrideMap)
+				continue
+			}
+		}
+		vals[k] = v
+	}
+	return vals
+}
+
+// result logs the result of an install or upgrade and returns a summary of the release as stdout
+func (c sdkClient) result(log *zap.SugaredLogger, operation string, releaseName string, namespace string, rel *helmrelease.Release, err error) (stdout []byte, stderr []byte, _ error) {
+	if err != nil {
+		err = newReleaseError(operation, releaseName, namespace, err)
+		log.Error(err.Error())
+		return nil, []byte(err.Error()), err
+	}
+	stdout = []byte(fmt.Sprintf("release \"%s\" revision %d %s", rel.Name, rel.Version, rel.Info.Status))
+	log.Infof("helm %s for release %s succeeded: %s", operation, releaseName, string(stdout))
+	return stdout, nil, nil
+}
+
+// newReleaseError creates a ReleaseError, replacing the helm release not found error with ErrReleaseNotFound
+func newReleaseError(operation string, releaseName string, namespace string, err error) error {
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		err = ErrReleaseNotFound
+	}
+	return &ReleaseError{Operation: operation, Release: releaseName, Namespace: namespace, Err: err}
+}
+
+// newReleaseInfo creates the release info of a helm release
+func newReleaseInfo(rel *helmrelease.Release) ReleaseInfo {
+	info := ReleaseInfo{Revision: rel.Version}
+	if rel.Info != nil {
+		info.Status = rel.Info.Status.String()
+	}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		info.ChartVersion = rel.Chart.Metadata.Version
+	}
+	return info
+}
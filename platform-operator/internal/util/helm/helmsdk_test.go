@@ -0,0 +1,241 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package helm
+
+import (
+	"errors"
+	"io/ioutil"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+const testChartDir = "testdata"
+
+// TestSDKReleaseLifecycle tests the in-process helm client
+// GIVEN a helm action configuration that stores the releases in memory
+//  WHEN a release is installed, upgraded, reconfigured, rolled back and uninstalled
+//  THEN the release info, values and history reflect each operation
+func TestSDKReleaseLifecycle(t *testing.T) {
+	assert := assert.New(t)
+	setTestActionConfig()
+	defer setDefaultActionConfig()
+
+	found, err := IsReleaseInstalled(release, ns)
+	assert.NoError(err, "IsReleaseInstalled returned an error")
+	assert.False(found, "Release should not be installed")
+
+	stdout, _, err := Install(zap.S(), release, ns, testChartDir, "")
+	assert.NoError(err, "Install returned an error")
+	assert.Contains(string(stdout), "revision 1 deployed")
+	info, err := GetReleaseInfo(release, ns)
+	assert.NoError(err, "GetReleaseInfo returned an error")
+	assert.Equal(ReleaseInfo{Status: ReleaseStatusDeployed, Revision: 1, ChartVersion: "0.8.0"}, *info)
+
+	// Install of an installed release upgrades it
+	_, _, err = Install(zap.S(), release, ns, testChartDir, "")
+	assert.NoError(err, "Install returned an error")
+	_, _, err = Upgrade(zap.S(), release, ns, testChartDir, "")
+	assert.NoError(err, "Upgrade returned an error")
+
+	_, _, err = Reconfigure(zap.S(), release, ns, testChartDir, ReconfigureValues{
+		SetArgs: []SetArg{{Name: "a.b", Value: "1"}, {Name: "c", Value: "true", SetString: true}},
+	})
+	assert.NoError(err, "Reconfigure returned an error")
+	values, err := GetValues(release, ns)
+	assert.NoError(err, "GetValues returned an error")
+	assert.Equal(map[string]interface{}{"a": map[string]interface{}{"b": int64(1)}, "c": "true"}, values)
+
+	_, _, err = Rollback(zap.S(), release, ns, 2)
+	assert.NoError(err, "Rollback returned an error")
+	history, err := History(release, ns)
+	assert.NoError(err, "History returned an error")
+	assert.Len(history, 5, "Incorrect number of revisions")
+	for i, rev := range history {
+		assert.Equal(i+1, rev.Revision, "History should be sorted by revision")
+	}
+	assert.Equal(ReleaseStatusDeployed, history[4].Status, "Last revision should be deployed")
+	values, err = GetValues(release, ns)
+	assert.NoError(err, "GetValues returned an error")
+	assert.Empty(values, "The values of revision 2 should be restored")
+
+	_, _, err = Uninstall(zap.S(), release, ns)
+	assert.NoError(err, "Uninstall returned an error")
+	found, err = IsReleaseInstalled(release, ns)
+	assert.NoError(err, "IsReleaseInstalled returned an error")
+	assert.False(found, "Release should be uninstalled")
+}
+
+// TestSDKOverrides tests that the overrides are merged in order on top of the values file
+// GIVEN a values file and overrides that set nested and top level values
+//  WHEN the release is installed and upgraded with the overrides
+//  THEN the values of the release are the merged values, with the last override taking precedence
+func TestSDKOverrides(t *testing.T) {
+	assert := assert.New(t)
+	setTestActionConfig()
+	defer setDefaultActionConfig()
+
+	_, _, err := Install(zap.S(), release, ns, testChartDir, "testdata/overrides.yaml",
+		"a:\n  c: 2\nd: [1, 2]", "a:\n  c: 3\ne: x")
+	assert.NoError(err, "Install returned an error")
+	values, err := GetValues(release, ns)
+	assert.NoError(err, "GetValues returned an error")
+	assert.Equal(map[string]interface{}{
+		"a": map[string]interface{}{"b": float64(1), "c": float64(3)},
+		"d": []interface{}{float64(1), float64(2)},
+		"e": "x",
+	}, values)
+
+	_, _, err = Upgrade(zap.S(), release, ns, testChartDir, "", "e: z")
+	assert.NoError(err, "Upgrade returned an error")
+	values, err = GetValues(release, ns)
+	assert.NoError(err, "GetValues returned an error")
+	assert.Equal("z", values["e"], "The override should replace the existing value")
+	assert.Equal(map[string]interface{}{"b": float64(1), "c": float64(3)}, values["a"], "The existing values should be reused")
+
+	_, _, err = Upgrade(zap.S(), release, ns, testChartDir, "", "a: [")
+	assert.Error(err, "Upgrade should fail for invalid YAML")
+}
+
+// TestSDKReconfigure tests that a reconfigure reverts the values of the set arguments that were removed
+// GIVEN a release that was installed with a values file and overrides, and reconfigured with set arguments
+//  WHEN the release is reconfigured without one of the previous set arguments
+//  THEN the value of the removed set argument is reverted to the value in the values file, and the other values
+//   of the release are kept
+func TestSDKReconfigure(t *testing.T) {
+	assert := assert.New(t)
+	setTestActionConfig()
+	defer setDefaultActionConfig()
+
+	_, _, err := Install(zap.S(), release, ns, testChartDir, "testdata/overrides.yaml", "e: x")
+	assert.NoError(err, "Install returned an error")
+	previous := []SetArg{{Name: "a.b", Value: "5"}, {Name: "f", Value: "y"}}
+	_, _, err = Reconfigure(zap.S(), release, ns, testChartDir, ReconfigureValues{ValuesFile: "testdata/overrides.yaml", SetArgs: previous})
+	assert.NoError(err, "Reconfigure returned an error")
+	values, err := GetValues(release, ns)
+	assert.NoError(err, "GetValues returned an error")
+	assert.Equal(map[string]interface{}{"a": map[string]interface{}{"b": int64(5), "c": float64(1)}, "e": "x", "f": "y"}, values)
+
+	_, _, err = Reconfigure(zap.S(), release, ns, testChartDir, ReconfigureValues{
+		ValuesFile:      "testdata/overrides.yaml",
+		PreviousSetArgs: previous,
+		SetArgs:         []SetArg{{Name: "f", Value: "z"}},
+	})
+	assert.NoError(err, "Reconfigure returned an error")
+	values, err = GetValues(release, ns)
+	assert.NoError(err, "GetValues returned an error")
+	assert.Equal(map[string]interface{}{"a": map[string]interface{}{"b": float64(1), "c": float64(1)}, "e": "x", "f": "z"}, values)
+}
+
+// TestSDKReleaseNotFound tests the typed errors of the in-process helm client
+// GIVEN a release that is not installed
+//  WHEN the release is upgraded or its status is requested
+//  THEN a ReleaseError wrapping ErrReleaseNotFound is returned, and GetReleaseInfo returns nil
+func TestSDKReleaseNotFound(t *testing.T) {
+	assert := assert.New(t)
+	setTestActionConfig()
+	defer setDefaultActionConfig()
+
+	info, err := GetReleaseInfo(missingRelease, ns)
+	assert.NoError(err, "GetReleaseInfo should not return an error for a missing release")
+	assert.Nil(info, "Release info should be nil")
+
+	_, _, err = Upgrade(zap.S(), missingRelease, ns, testChartDir, "")
+	assert.Error(err, "Upgrade should have returned an error")
+	var releaseErr *ReleaseError
+	assert.True(errors.As(err, &releaseErr), "Error should be a ReleaseError")
+	assert.Equal("upgrade", releaseErr.Operation)
+	assert.Equal(missingRelease, releaseErr.Release)
+
+	_, err = History(missingRelease, ns)
+	assert.True(errors.Is(err, ErrReleaseNotFound), "Error should wrap ErrReleaseNotFound")
+	_, _, err = Install(zap.S(), release, ns, "no-chart-dir", "")
+	assert.True(errors.As(err, &releaseErr), "Error should be a ReleaseError")
+	assert.False(errors.Is(err, ErrReleaseNotFound), "Error should not wrap ErrReleaseNotFound")
+}
+
+// TestClientsReleaseNotFound tests that the in-process and CLI helm clients return the same errors
+// GIVEN a release that is not installed
+//  WHEN the same operations are run on the release with each client
+//  THEN each client returns a ReleaseError for the operation wrapping ErrReleaseNotFound
+func TestClientsReleaseNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	clients := map[string]func() func(){
+		"sdk": func() func() {
+			setTestActionConfig()
+			return setDefaultActionConfig
+		},
+		"cli": func() func() {
+			SetCmdRunner(notFoundRunner{})
+			return SetDefaultRunner
+		},
+	}
+	for name, setup := range clients {
+		cleanup := setup()
+		c := getClient()
+		_, err := c.GetReleaseInfo(missingRelease, ns)
+		assertReleaseNotFound(assert, name, "status", err)
+		_, err = c.GetValues(missingRelease, ns)
+		assertReleaseNotFound(assert, name, "get values", err)
+		_, err = c.History(missingRelease, ns)
+		assertReleaseNotFound(assert, name, "history", err)
+		_, _, err = c.Rollback(zap.S(), missingRelease, ns, 1)
+		assertReleaseNotFound(assert, name, "rollback", err)
+		_, _, err = c.Uninstall(zap.S(), missingRelease, ns)
+		assertReleaseNotFound(assert, name, "uninstall", err)
+		found, err := c.IsReleaseInstalled(missingRelease, ns)
+		assert.NoError(err, "IsReleaseInstalled returned an error for the %s client", name)
+		assert.False(found, "Release should not be found by the %s client", name)
+		cleanup()
+	}
+}
+
+// assertReleaseNotFound asserts that the error is a ReleaseError for the operation wrapping ErrReleaseNotFound
+func assertReleaseNotFound(assert *assert.Assertions, client string, operation string, err error) {
+	var releaseErr *ReleaseError
+	if assert.True(errors.As(err, &releaseErr), "Error of %s %s should be a ReleaseError", client, operation) {
+		assert.Equal(operation, releaseErr.Operation, "Incorrect operation of the %s client", client)
+	}
+	assert.True(errors.Is(err, ErrReleaseNotFound), "Error of %s %s should wrap ErrReleaseNotFound", client, operation)
+}
+
+// notFoundRunner returns the stderr of the helm commands for a release that is not installed
+type notFoundRunner struct{}
+
+// Run returns the error of the helm command for a release that doesn't exist
+func (r notFoundRunner) Run(cmd *exec.Cmd) (stdout []byte, stderr []byte, err error) {
+	if cmd.Args[1] == "uninstall" {
+		return []byte(""), []byte("Error: uninstall: Release not loaded: " + cmd.Args[2] + ": release: not found"), errors.New("exit status 1")
+	}
+	return []byte(""), []byte("Error: release: not found"), errors.New("exit status 1")
+}
+
+// setTestActionConfig sets the in-process helm client with an action configuration that stores the releases in
+// memory and doesn't access a cluster.  The same configuration is returned for every namespace.
+func setTestActionConfig() {
+	cfg := &action.Configuration{
+		Releases:     storage.Init(driver.NewMemory()),
+		KubeClient:   &kubefake.PrintingKubeClient{Out: ioutil.Discard},
+		Capabilities: chartutil.DefaultCapabilities,
+		Log:          func(string, ...interface{}) {},
+	}
+	SetClient(sdkClient{})
+	newActionConfigFunc = func(_ *zap.SugaredLogger, _ string) (*action.Configuration, error) {
+		return cfg, nil
+	}
+}
+
+// setDefaultActionConfig restores the default helm client and action configuration
+func setDefaultActionConfig() {
+	SetDefaultClient()
+	newActionConfigFunc = newActionConfig
+}
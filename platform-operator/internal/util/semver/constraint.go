@@ -0,0 +1,234 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A partial version, where the minor and patch versions are optional and may be a wildcard (x, X or *)
+const partialVersionRegex = "^[v|V]?(0|[1-9]\\d*|[xX*])(?:\\.(0|[1-9]\\d*|[xX*]))?(?:\\.(0|[1-9]\\d*|[xX*]))?(?:-((?:0|[1-9]\\d*|\\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\\.(?:0|[1-9]\\d*|\\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\\+([0-9a-zA-Z-]+(?:\\.[0-9a-zA-Z-]+)*))?$"
+
+var partialVersionRegExp = regexp.MustCompile(partialVersionRegex)
+
+// Whitespace between an operator and its version is removed before the comparators are split
+var operatorSpaceRegExp = regexp.MustCompile(`(>=|<=|!=|=|>|<|~|\^)\s+`)
+
+// Constraint is a set of version ranges, for example ">=0.9.0 <1.0.0", "~0.10", "^1.2" or "0.8.x || >=0.10.0".
+// Comparators separated by spaces or commas must all be satisfied, and a version must satisfy at least
+// one of the ranges separated by "||".  The supported operators are =, !=, >, >=, <, <=, ~ and ^.
+//
+// A version with a pre-release only satisfies a range if one of the comparators in the range has a
+// pre-release for the same major, minor and patch version.  For example 1.2.3-beta.2 satisfies
+// ">=1.2.3-alpha <1.3.0" but doesn't satisfy ">=1.2.0".
+type Constraint struct {
+	expression string
+	ranges     [][]comparator
+}
+
+// comparator compares a version to the comparator version using the operator
+type comparator struct {
+	operator string
+	version  SemVersion
+}
+
+// partialVersion is a version where only the first parts are specified
+type partialVersion struct {
+	version SemVersion
+	// parts is the number of version fields specified before the first missing or wildcard field
+	parts int
+}
+
+// NewConstraint Create a Constraint from a constraint expression
+func NewConstraint(expression string) (*Constraint, error) {
+	constraint := Constraint{expression: expression}
+	for _, rangeExpr := range strings.Split(expression, "||") {
+		rangeExpr = operatorSpaceRegExp.ReplaceAllString(rangeExpr, "$1")
+		fields := strings.FieldsFunc(rangeExpr, func(r rune) bool { return r == ' ' || r == ',' || r == '\t' })
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("Invalid constraint %s, empty range", expression)
+		}
+		var comparators []comparator
+		for _, field := range fields {
+			c, err := parseComparator(field)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid constraint %s, %v", expression, err)
+			}
+			comparators = append(comparators, c...)
+		}
+		constraint.ranges = append(constraint.ranges, comparators)
+	}
+	return &constraint, nil
+}
+
+// String Returns the constraint expression
+func (c *Constraint) String() string {
+	return c.expression
+}
+
+// Satisfies Returns true if the version satisfies the constraint
+func (v *SemVersion) Satisfies(c *Constraint) bool {
+	for _, comparators := range c.ranges {
+		if v.satisfiesRange(comparators) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the version satisfies all of the comparators of a range
+func (v *SemVersion) satisfiesRange(comparators []comparator) bool {
+	prereleaseAllowed := len(v.Prerelease) == 0
+	for _, c := range comparators {
+		if !c.matches(v) {
+			return false
+		}
+		if len(c.version.Prerelease) > 0 && c.version.Major == v.Major && c.version.Minor == v.Minor && c.version.Patch == v.Patch {
+			prereleaseAllowed = true
+		}
+	}
+	return prereleaseAllowed
+}
+
+// Returns true if the version matches the comparator
+func (c comparator) matches(v *SemVersion) bool {
+	result := v.CompareTo(&c.version)
+	switch c.operator {
+	case "=":
+		return result == 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	}
+	return false
+}
+
+// Parses a single comparator, for example ">=1.2", into one or two primitive comparators.  Partial
+// versions, the tilde and the caret operators are expanded into a lower and an upper bound.
+func parseComparator(expr string) ([]comparator, error) {
+	operator := ""
+	for _, op := range []string{">=", "<=", "!=", "=", ">", "<", "~", "^"} {
+		if strings.HasPrefix(expr, op) {
+			operator = op
+			break
+		}
+	}
+	partial, err := parsePartialVersion(strings.TrimPrefix(expr, operator))
+	if err != nil {
+		return nil, err
+	}
+	v := partial.version
+	if len(v.Prerelease) > 0 && partial.parts < 3 {
+		return nil, fmt.Errorf("pre-release %s is only allowed with a full version", v.Prerelease)
+	}
+	anyVersion := []comparator{{operator: ">=", version: SemVersion{}}}
+
+	switch operator {
+	case "", "=":
+		if partial.parts == 3 {
+			return []comparator{{operator: "=", version: v}}, nil
+		}
+		if partial.parts == 0 {
+			return anyVersion, nil
+		}
+		return []comparator{{operator: ">=", version: v}, {operator: "<", version: partial.next()}}, nil
+	case "!=":
+		if partial.parts < 3 {
+			return nil, fmt.Errorf("operator != needs a full version, got %s", expr)
+		}
+		return []comparator{{operator: "!=", version: v}}, nil
+	case ">":
+		if partial.parts == 0 {
+			return nil, fmt.Errorf("no version is greater than %s", expr)
+		}
+		if partial.parts == 3 {
+			return []comparator{{operator: ">", version: v}}, nil
+		}
+		return []comparator{{operator: ">=", version: partial.next()}}, nil
+	case ">=":
+		return []comparator{{operator: ">=", version: v}}, nil
+	case "<":
+		if partial.parts == 0 {
+			return nil, fmt.Errorf("no version is less than %s", expr)
+		}
+		return []comparator{{operator: "<", version: v}}, nil
+	case "<=":
+		if partial.parts == 0 {
+			return anyVersion, nil
+		}
+		if partial.parts == 3 {
+			return []comparator{{operator: "<=", version: v}}, nil
+		}
+		return []comparator{{operator: "<", version: partial.next()}}, nil
+	case "~":
+		// ~1.2.3 and ~1.2 allow patch updates, ~1 allows minor updates
+		if partial.parts == 0 {
+			return anyVersion, nil
+		}
+		upper := SemVersion{Major: v.Major + 1}
+		if partial.parts > 1 {
+			upper = SemVersion{Major: v.Major, Minor: v.Minor + 1}
+		}
+		return []comparator{{operator: ">=", version: v}, {operator: "<", version: upper}}, nil
+	case "^":
+		// ^ allows updates that don't change the left-most non-zero version field
+		if partial.parts == 0 {
+			return anyVersion, nil
+		}
+		var upper SemVersion
+		switch {
+		case v.Major > 0 || partial.parts == 1:
+			upper = SemVersion{Major: v.Major + 1}
+		case v.Minor > 0 || partial.parts == 2:
+			upper = SemVersion{Minor: v.Minor + 1}
+		default:
+			upper = SemVersion{Patch: v.Patch + 1}
+		}
+		return []comparator{{operator: ">=", version: v}, {operator: "<", version: upper}}, nil
+	}
+	return nil, fmt.Errorf("invalid comparator %s", expr)
+}
+
+// Parses a partial version, for example "1", "1.2", "1.x" or "v1.2.3-beta"
+func parsePartialVersion(version string) (partialVersion, error) {
+	matches := partialVersionRegExp.FindStringSubmatch(version)
+	if matches == nil {
+		return partialVersion{}, fmt.Errorf("invalid version %s", version)
+	}
+	partial := partialVersion{}
+	fields := []*int64{&partial.version.Major, &partial.version.Minor, &partial.version.Patch}
+	for i, field := range fields {
+		s := matches[i+1]
+		if len(s) == 0 || s == "x" || s == "X" || s == "*" {
+			break
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return partialVersion{}, err
+		}
+		*field = n
+		partial.parts++
+	}
+	partial.version.Prerelease = matches[4]
+	partial.version.Build = matches[5]
+	return partial, nil
+}
+
+// Returns the first version after all of the versions that match the partial version, for example 1.3.0 for 1.2
+func (p partialVersion) next() SemVersion {
+	if p.parts == 1 {
+		return SemVersion{Major: p.version.Major + 1}
+	}
+	return SemVersion{Major: p.version.Major, Minor: p.version.Minor + 1}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2021, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSatisfies Tests the Satisfies method for various constraints
+// GIVEN a set of constraints
+// WHEN versions are checked against each constraint
+// THEN the versions in the ranges of the constraint satisfy it and the others don't
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		constraint  string
+		satisfied   []string
+		unsatisfied []string
+	}{
+		{">=0.9.0 <1.0.0", []string{"v0.9.0", "v0.9.5", "v0.10.2"}, []string{"v0.8.9", "v1.0.0", "v1.0.0-rc.1"}},
+		{">= 0.9.0, < 1.0.0", []string{"v0.9.0", "v0.99.0"}, []string{"v1.0.0"}},
+		{"~0.10", []string{"v0.10.0", "v0.10.9"}, []string{"v0.9.9", "v0.11.0"}},
+		{"~1.2.3", []string{"v1.2.3", "v1.2.10"}, []string{"v1.2.2", "v1.3.0"}},
+		{"~1", []string{"v1.0.0", "v1.9.9"}, []string{"v0.9.0", "v2.0.0"}},
+		{"^1.2", []string{"v1.2.0", "v1.9.0"}, []string{"v1.1.9", "v2.0.0"}},
+		{"^0.10", []string{"v0.10.0", "v0.10.4"}, []string{"v0.9.0", "v0.11.0"}},
+		{"^0.0.3", []string{"v0.0.3"}, []string{"v0.0.4", "v0.1.0"}},
+		{"^0", []string{"v0.0.1", "v0.99.0"}, []string{"v1.0.0"}},
+		{"1.2.x", []string{"v1.2.0", "v1.2.7"}, []string{"v1.3.0", "v1.1.0"}},
+		{"1.2", []string{"v1.2.0", "v1.2.7"}, []string{"v1.3.0"}},
+		{"*", []string{"v0.0.0", "v3.4.5"}, []string{"v1.0.0-beta"}},
+		{"=1.2.3", []string{"v1.2.3", "v1.2.3+build"}, []string{"v1.2.4"}},
+		{"!=1.2.3", []string{"v1.2.4"}, []string{"v1.2.3"}},
+		{">1.2", []string{"v1.3.0"}, []string{"v1.2.9"}},
+		{"<=1.2", []string{"v1.2.9"}, []string{"v1.3.0"}},
+		{"<1.2", []string{"v1.1.9"}, []string{"v1.2.0"}},
+		{"0.8.x || >=0.10.0", []string{"v0.8.3", "v0.10.0", "v1.0.0"}, []string{"v0.9.0"}},
+		{">=1.2.3-alpha <1.3.0", []string{"v1.2.3-alpha", "v1.2.3-beta.2", "v1.2.3", "v1.2.9"}, []string{"v1.2.4-beta", "v1.3.0"}},
+		{"^1.2.3-beta.2", []string{"v1.2.3-beta.2", "v1.2.3-beta.11", "v1.9.0"}, []string{"v1.2.3-beta.1", "v1.2.4-rc.1"}},
+	}
+	for _, test := range tests {
+		c, err := NewConstraint(test.constraint)
+		assert.NoError(t, err, "Error parsing constraint %s", test.constraint)
+		assert.Equal(t, test.constraint, c.String())
+		for _, version := range test.satisfied {
+			v, err := NewSemVersion(version)
+			assert.NoError(t, err)
+			assert.True(t, v.Satisfies(c), "%s should satisfy %s", version, test.constraint)
+		}
+		for _, version := range test.unsatisfied {
+			v, err := NewSemVersion(version)
+			assert.NoError(t, err)
+			assert.False(t, v.Satisfies(c), "%s should not satisfy %s", version, test.constraint)
+		}
+	}
+}
+
+// TestInvalidConstraint Tests NewConstraint for invalid constraint expressions
+// GIVEN a set of invalid constraint expressions
+// WHEN we try to create a Constraint
+// THEN an error is returned and nil is returned for the Constraint object ref
+func TestInvalidConstraint(t *testing.T) {
+	invalidConstraints := []string{
+		"",
+		"foo",
+		">=1.2.3 ||",
+		"1.2.3.4",
+		"!=1.2",
+		">*",
+		"<x",
+		"~>1.2",
+		"1.2-beta",
+	}
+	for _, expr := range invalidConstraints {
+		c, err := NewConstraint(expr)
+		assert.Error(t, err, "Constraint %s should be invalid", expr)
+		assert.Nil(t, c)
+	}
+}
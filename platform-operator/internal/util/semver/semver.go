@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 const semverRegex = "^[v|V](0|[1-9]\\d*)\\.(0|[1-9]\\d*)\\.(0|[1-9]\\d*)(?:-((?:0|[1-9]\\d*|\\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\\.(?:0|[1-9]\\d*|\\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\\+([0-9a-zA-Z-]+(?:\\.[0-9a-zA-Z-]+)*))?$"
@@ -95,7 +96,8 @@ func NewSemVersion(version string) (*SemVersion, error) {
 	return &semVersion, nil
 }
 
-// CompareTo Compares the current version to another version
+// CompareTo Compares the current version to another version using the semver 2.0 precedence rules.
+// A pre-release version has a lower precedence than the associated normal version, and build metadata is ignored.
 // - if from > this, -1 is returned
 // - if from < this, 1 is returned
 // - if they are equal, 0 is returned
@@ -103,8 +105,9 @@ func (v *SemVersion) CompareTo(from *SemVersion) int {
 	var result int
 	if result = compareVersion(from.Major, v.Major); result == 0 {
 		if result = compareVersion(from.Minor, v.Minor); result == 0 {
-			result = compareVersion(from.Patch, v.Patch)
-			// Ignore pre-release/buildver fields for now
+			if result = compareVersion(from.Patch, v.Patch); result == 0 {
+				result = comparePrerelease(v.Prerelease, from.Prerelease)
+			}
 		}
 	}
 	return result
@@ -143,3 +146,46 @@ func compareVersion(v1 int64, v2 int64) int {
 	}
 	return 0
 }
+
+// Compares the pre-release fields of two versions, where an empty pre-release is a normal version.
+// The dot separated identifiers are compared from left to right.  Numeric identifiers are compared
+// numerically and have a lower precedence than alphanumeric identifiers, which are compared in ASCII
+// sort order.  A larger set of identifiers has a higher precedence if all of the preceding identifiers
+// are equal.  Returns
+// - 1 if p1 > p2
+// - -1 if p2 > p1
+// - 0 if p1 == p2
+func comparePrerelease(p1 string, p2 string) int {
+	if p1 == p2 {
+		return 0
+	}
+	if len(p1) == 0 {
+		return 1
+	}
+	if len(p2) == 0 {
+		return -1
+	}
+	ids1 := strings.Split(p1, ".")
+	ids2 := strings.Split(p2, ".")
+	for i := 0; i < len(ids1) && i < len(ids2); i++ {
+		if result := comparePrereleaseIdentifier(ids1[i], ids2[i]); result != 0 {
+			return result
+		}
+	}
+	return -compareVersion(int64(len(ids1)), int64(len(ids2)))
+}
+
+// Compares a single pre-release identifier, with the same result as comparePrerelease
+func comparePrereleaseIdentifier(id1 string, id2 string) int {
+	num1, err1 := strconv.ParseInt(id1, 10, 64)
+	num2, err2 := strconv.ParseInt(id2, 10, 64)
+	switch {
+	case err1 == nil && err2 == nil:
+		return -compareVersion(num1, num2)
+	case err1 == nil:
+		return -1
+	case err2 == nil:
+		return 1
+	}
+	return strings.Compare(id1, id2)
+}
@@ -251,3 +251,38 @@ func TestIsGreatherThan(t *testing.T) {
 	assert.False(t, v009.IsGreatherThan(v0010))
 	assert.True(t, v0010.IsGreatherThan(v009))
 }
+
+// TestPrereleasePrecedence Tests the precedence of versions with pre-release and build fields
+// GIVEN the ordered version examples from the semver 2.0 spec at https://semver.org/#spec-item-11
+// WHEN each version is compared to the versions after it
+// THEN each version is less than the versions after it, and build metadata is ignored
+func TestPrereleasePrecedence(t *testing.T) {
+	ordered := []string{
+		"v1.0.0-alpha",
+		"v1.0.0-alpha.1",
+		"v1.0.0-alpha.beta",
+		"v1.0.0-beta",
+		"v1.0.0-beta.2",
+		"v1.0.0-beta.11",
+		"v1.0.0-rc.1",
+		"v1.0.0",
+		"v2.0.0",
+		"v2.1.0",
+		"v2.1.1",
+	}
+	for i := range ordered {
+		v1, err := NewSemVersion(ordered[i])
+		assert.NoError(t, err)
+		assert.True(t, v1.IsEqualTo(v1), "%s should be equal to itself", ordered[i])
+		for j := i + 1; j < len(ordered); j++ {
+			v2, err := NewSemVersion(ordered[j])
+			assert.NoError(t, err)
+			assert.True(t, v1.IsLessThan(v2), "%s should be less than %s", ordered[i], ordered[j])
+			assert.True(t, v2.IsGreatherThan(v1), "%s should be greater than %s", ordered[j], ordered[i])
+		}
+	}
+
+	v1, _ := NewSemVersion("v1.0.0-alpha+001")
+	v2, _ := NewSemVersion("v1.0.0-alpha+exp.sha.5114f85")
+	assert.True(t, v1.IsEqualTo(v2), "Build metadata should be ignored")
+}
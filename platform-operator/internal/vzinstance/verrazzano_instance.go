@@ -9,17 +9,25 @@ import (
 	"strings"
 )
 
-// GetInstanceInfo returns the instance info for the local install.
-func GetInstanceInfo(vzURI string) *v1alpha1.InstanceInfo {
-	return &v1alpha1.InstanceInfo{
-		Console:       deriveURL(vzURI, "verrazzano"),
-		RancherURL:    deriveURL(vzURI, "rancher"),
+// GetInstanceInfo returns the instance info for the local install.  There is no URL for the console, Rancher
+// and Keycloak when they are disabled.
+func GetInstanceInfo(vzURI string, comps *v1alpha1.ComponentSpec) *v1alpha1.InstanceInfo {
+	info := &v1alpha1.InstanceInfo{
 		ElasticURL:    deriveURL(vzURI, "elasticsearch.vmi.system"),
 		KibanaURL:     deriveURL(vzURI, "kibana.vmi.system"),
 		GrafanaURL:    deriveURL(vzURI, "grafana.vmi.system"),
 		PrometheusURL: deriveURL(vzURI, "prometheus.vmi.system"),
-		KeyCloakURL:   deriveURL(vzURI, "keycloak"),
 	}
+	if v1alpha1.IsEnabled(comps.Console.Enabled) {
+		info.Console = deriveURL(vzURI, "verrazzano")
+	}
+	if v1alpha1.IsEnabled(comps.Rancher.Enabled) {
+		info.RancherURL = deriveURL(vzURI, "rancher")
+	}
+	if v1alpha1.IsEnabled(comps.Keycloak.Enabled) {
+		info.KeyCloakURL = deriveURL(vzURI, "keycloak")
+	}
+	return info
 }
 
 // Derive the URL from the verrazzano URI by prefixing with the given URL segment
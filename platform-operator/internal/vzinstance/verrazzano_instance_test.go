@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano/platform-operator/apis/verrazzano/v1alpha1"
 )
 
 // TestGetInstanceInfo tests buildDomain method
@@ -16,7 +17,7 @@ import (
 // THEN the an instance info struct is returned with the expected URLs
 func TestGetInstanceInfo(t *testing.T) {
 	const dnsDomain = "myenv.testverrazzano.com"
-	instanceInfo := GetInstanceInfo(dnsDomain)
+	instanceInfo := GetInstanceInfo(dnsDomain, &v1alpha1.ComponentSpec{})
 	assert.NotNil(t, instanceInfo)
 	assert.Equal(t, fmt.Sprintf("https://%s.%s", "verrazzano", dnsDomain), *instanceInfo.Console)
 	assert.Equal(t, fmt.Sprintf("https://%s.%s", "rancher", dnsDomain), *instanceInfo.RancherURL)
@@ -27,6 +28,25 @@ func TestGetInstanceInfo(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("https://%s.vmi.system.%s", "prometheus", dnsDomain), *instanceInfo.PrometheusURL)
 }
 
+// TestGetInstanceInfoDisabled tests GetInstanceInfo when components are disabled
+// GIVEN a spec where the console, Rancher and Keycloak are disabled
+// WHEN GetInstanceInfo is called
+// THEN there are no URLs for the disabled components
+func TestGetInstanceInfoDisabled(t *testing.T) {
+	const dnsDomain = "myenv.testverrazzano.com"
+	disabled := false
+	comps := &v1alpha1.ComponentSpec{
+		Console:  v1alpha1.ConsoleComponent{Enabled: &disabled},
+		Rancher:  v1alpha1.RancherComponent{Enabled: &disabled},
+		Keycloak: v1alpha1.KeycloakComponent{Enabled: &disabled},
+	}
+	instanceInfo := GetInstanceInfo(dnsDomain, comps)
+	assert.Nil(t, instanceInfo.Console)
+	assert.Nil(t, instanceInfo.RancherURL)
+	assert.Nil(t, instanceInfo.KeyCloakURL)
+	assert.Equal(t, fmt.Sprintf("https://%s.vmi.system.%s", "grafana", dnsDomain), *instanceInfo.GrafanaURL)
+}
+
 // TestDeriveNegative tests buildDomain method
 // GIVEN a request to deriveURL
 // WHEN with an empty domain
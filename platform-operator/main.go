@@ -16,6 +16,7 @@ import (
 	"github.com/verrazzano/verrazzano/platform-operator/internal/util/log"
 	"go.uber.org/zap"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -51,6 +52,12 @@ func main() {
 		"Enable webhooks for the operator")
 	flag.BoolVar(&config.WebhookValidationEnabled, "enable-webhook-validation", config.WebhookValidationEnabled,
 		"Enable webhooks validation for the operator")
+	flag.BoolVar(&config.UpgradePreflightEnabled, "enable-upgrade-preflight", config.UpgradePreflightEnabled,
+		"Enable the checks that are run before upgrading the Verrazzano components")
+	flag.BoolVar(&config.ComponentInstallEnabled, "enable-component-install", config.ComponentInstallEnabled,
+		"Install the Verrazzano components from the operator instead of running the install job")
+	flag.BoolVar(&config.HelmCLIEnabled, "enable-helm-cli", config.HelmCLIEnabled,
+		"Run the helm command instead of using the in-process helm client")
 	flag.BoolVar(&config.InitWebhooks, "init-webhooks", config.InitWebhooks,
 		"Initialize webhooks for the operator")
 	flag.StringVar(&config.VerrazzanoInstallDir, "vz-install-dir", config.VerrazzanoInstallDir,
@@ -120,9 +127,10 @@ func main() {
 	// Setup the reconciler
 	_, dryRun := os.LookupEnv("VZ_DRY_RUN") // If this var is set, the install jobs are no-ops
 	reconciler := vzcontroller.Reconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		DryRun: dryRun,
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("verrazzano-platform-operator"),
+		DryRun:   dryRun,
 	}
 	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Errorf("unable to create controller: %v", err)
@@ -136,6 +144,25 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Watch for the install progress that is reported by the install job
+	if err := reconciler.Controller.Watch(&source.Kind{Type: &corev1.ConfigMap{}},
+		&handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(vzcontroller.InstallProgressRequests)},
+		vzcontroller.InstallProgressPredicate); err != nil {
+		setupLog.Errorf("unable to set watch for ConfigMap resource: %v", err)
+		os.Exit(1)
+	}
+
+	// Watch for the ConfigMaps and Secrets that are referenced by the overrides of the components
+	overridesHandler := &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(reconciler.OverridesRequests)}
+	if err := reconciler.Controller.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, overridesHandler); err != nil {
+		setupLog.Errorf("unable to set watch for ConfigMap resource: %v", err)
+		os.Exit(1)
+	}
+	if err := reconciler.Controller.Watch(&source.Kind{Type: &corev1.Secret{}}, overridesHandler); err != nil {
+		setupLog.Errorf("unable to set watch for Secret resource: %v", err)
+		os.Exit(1)
+	}
+
 	// Setup the validation webhook
 	if config.WebhooksEnabled {
 		setupLog.Info("Setting up Verrazzano webhook with manager")
@@ -148,8 +175,9 @@ func main() {
 
 	// Setup the reconciler for VerrazzanoManagedCluster objects
 	if err = (&clusterscontroller.VerrazzanoManagedClusterReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("verrazzano-managed-cluster-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "VerrazzanoManagedCluster")
 		os.Exit(1)
@@ -102,37 +102,45 @@ function install_istio()
       ${IMAGE_PULL_SECRETS_ARGUMENT} \
       || return $?
 
-    log "Installing Istio egress"
-    helm upgrade istio-egress ${ISTIO_CHART_DIR}/gateways/istio-egress \
-      --install \
-      --namespace istio-system \
-      -f $VZ_OVERRIDES_DIR/istio-values.yaml \
-      ${IMAGE_PULL_SECRETS_ARGUMENT} \
-      || return $?
+    if is_component_enabled istio-egress; then
+      log "Installing Istio egress"
+      helm upgrade istio-egress ${ISTIO_CHART_DIR}/gateways/istio-egress \
+        --install \
+        --namespace istio-system \
+        -f $VZ_OVERRIDES_DIR/istio-values.yaml \
+        ${IMAGE_PULL_SECRETS_ARGUMENT} \
+        || return $?
+    fi
 
-    log "Installing istiocoredns"
-    helm upgrade istiocoredns ${ISTIO_CHART_DIR}/istiocoredns \
-      --install \
-      --namespace istio-system \
-      -f $VZ_OVERRIDES_DIR/istio-values.yaml \
-      ${IMAGE_PULL_SECRETS_ARGUMENT} \
-      || return $?
+    if is_component_enabled istiocoredns; then
+      log "Installing istiocoredns"
+      helm upgrade istiocoredns ${ISTIO_CHART_DIR}/istiocoredns \
+        --install \
+        --namespace istio-system \
+        -f $VZ_OVERRIDES_DIR/istio-values.yaml \
+        ${IMAGE_PULL_SECRETS_ARGUMENT} \
+        || return $?
+    fi
 
-    log "Installing Istio Grafana"
-    helm upgrade grafana ${ISTIO_CHART_DIR}/istio-telemetry/grafana \
-      --install \
-      --namespace istio-system \
-      -f $VZ_OVERRIDES_DIR/istio-values.yaml \
-      ${IMAGE_PULL_SECRETS_ARGUMENT} \
-      || return $?
+    if is_component_enabled grafana; then
+      log "Installing Istio Grafana"
+      helm upgrade grafana ${ISTIO_CHART_DIR}/istio-telemetry/grafana \
+        --install \
+        --namespace istio-system \
+        -f $VZ_OVERRIDES_DIR/istio-values.yaml \
+        ${IMAGE_PULL_SECRETS_ARGUMENT} \
+        || return $?
+    fi
 
-    log "Installing Istio Prometheus"
-    helm upgrade prometheus ${ISTIO_CHART_DIR}/istio-telemetry/prometheus \
-      --install \
-      --namespace istio-system \
-      -f $VZ_OVERRIDES_DIR/istio-values.yaml \
-      ${IMAGE_PULL_SECRETS_ARGUMENT} \
-      || return $?
+    if is_component_enabled prometheus; then
+      log "Installing Istio Prometheus"
+      helm upgrade prometheus ${ISTIO_CHART_DIR}/istio-telemetry/prometheus \
+        --install \
+        --namespace istio-system \
+        -f $VZ_OVERRIDES_DIR/istio-values.yaml \
+        ${IMAGE_PULL_SECRETS_ARGUMENT} \
+        || return $?
+    fi
 
     log "Setting Istio global mesh policy to STRICT mode"
     kubectl apply -f <(echo "
@@ -234,7 +242,9 @@ if ! kubectl get secret cacerts -n istio-system > /dev/null 2>&1 ; then
   action "Generating Istio CA bundle" create_secret || exit 1
 fi
 
-action "Installing Istio" install_istio || exit 1
-action "Updating CoreDNS configuration" update_coredns || exit 1
+install_action istio "Installing Istio" install_istio || exit 1
+if is_component_enabled istiocoredns; then
+  install_action coredns "Updating CoreDNS configuration" update_coredns || exit 1
+fi
 
 kubectl get pods -n istio-system
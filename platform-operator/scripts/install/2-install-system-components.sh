@@ -310,7 +310,7 @@ NAME=$(get_config_value ".environmentName")
 DNS_TYPE=$(get_config_value ".dns.type")
 CERT_ISSUER_TYPE=$(get_config_value ".certificates.issuerType")
 
-action "Installing NGINX Ingress Controller" install_nginx_ingress_controller || exit 1
+install_action ingress-controller "Installing NGINX Ingress Controller" install_nginx_ingress_controller || exit 1
 
 # We can only know the ingress IP after installing nginx ingress controller
 INGRESS_IP=$(get_verrazzano_ingress_ip)
@@ -320,8 +320,10 @@ DNS_SUFFIX=$(get_dns_suffix ${INGRESS_IP})
 
 RANCHER_HOSTNAME=rancher.${NAME}.${DNS_SUFFIX}
 
-action "Installing cert manager" install_cert_manager || exit 1
-action "Installing external DNS" install_external_dns || exit 1
-action "Installing Rancher" install_rancher || exit 1
-action "Setting Rancher Server URL" set_rancher_server_url || true
-action "Patching Rancher Agents" patch_rancher_agents || true
+install_action cert-manager "Installing cert manager" install_cert_manager || exit 1
+install_action external-dns "Installing external DNS" install_external_dns || exit 1
+install_action rancher "Installing Rancher" install_rancher || exit 1
+if is_component_enabled rancher; then
+  action "Setting Rancher Server URL" set_rancher_server_url || true
+  action "Patching Rancher Agents" patch_rancher_agents || true
+fi
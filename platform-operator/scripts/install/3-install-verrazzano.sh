@@ -127,31 +127,43 @@ function install_verrazzano()
 {
   local RANCHER_HOSTNAME=rancher.${ENV_NAME}.${DNS_SUFFIX}
 
-  local rancher_admin_password=`kubectl get secret --namespace cattle-system rancher-admin-secret -o jsonpath={.data.password} | base64 --decode`
+  # The cluster operator registers the managed clusters with Rancher, so it is only configured when Rancher is enabled
+  local RANCHER_ARGUMENTS=""
+  if is_component_enabled rancher; then
+    local rancher_admin_password=`kubectl get secret --namespace cattle-system rancher-admin-secret -o jsonpath={.data.password} | base64 --decode`
 
-  if [ -z "$rancher_admin_password" ] ; then
-    error "ERROR: Failed to retrieve rancher-admin-secret - did you run the scripts to install Istio and system components?"
-    return 1
-  fi
+    if [ -z "$rancher_admin_password" ] ; then
+      error "ERROR: Failed to retrieve rancher-admin-secret - did you run the scripts to install Istio and system components?"
+      return 1
+    fi
 
-  # Wait until rancher TLS cert is ready
-  log "Waiting for Rancher TLS cert to reach ready state"
-  kubectl wait --for=condition=ready cert tls-rancher-ingress -n cattle-system
+    # Wait until rancher TLS cert is ready
+    log "Waiting for Rancher TLS cert to reach ready state"
+    kubectl wait --for=condition=ready cert tls-rancher-ingress -n cattle-system
 
-  # Make sure rancher ingress has an IP
-  wait_for_ingress_ip rancher cattle-system || exit 1
+    # Make sure rancher ingress has an IP
+    wait_for_ingress_ip rancher cattle-system || exit 1
 
-  get_rancher_access_token "${RANCHER_HOSTNAME}" "${rancher_admin_password}"
-  if [ $? -ne 0 ] ; then
-    error "ERROR: Failed to get rancher access token"
-    exit 1
+    get_rancher_access_token "${RANCHER_HOSTNAME}" "${rancher_admin_password}"
+    if [ $? -ne 0 ] ; then
+      error "ERROR: Failed to get rancher access token"
+      exit 1
+    fi
+    local token_array=(${RANCHER_ACCESS_TOKEN//:/ })
+    RANCHER_ARGUMENTS="--set clusterOperator.rancherURL=https://${RANCHER_HOSTNAME}"
+    RANCHER_ARGUMENTS="${RANCHER_ARGUMENTS} --set clusterOperator.rancherUserName=${token_array[0]}"
+    RANCHER_ARGUMENTS="${RANCHER_ARGUMENTS} --set clusterOperator.rancherPassword=${token_array[1]}"
+    RANCHER_ARGUMENTS="${RANCHER_ARGUMENTS} --set clusterOperator.rancherHostname=$(get_nginx_hostip)"
+    RANCHER_ARGUMENTS="${RANCHER_ARGUMENTS} --set clusterOperator.rancherHostPort=$(get_nginx_nodeport)"
   fi
-  local token_array=(${RANCHER_ACCESS_TOKEN//:/ })
 
   EXTRA_V8O_ARGUMENTS=$(get_verrazzano_helm_args_from_config)
   if [ ${REGISTRY_SECRET_EXISTS} == "TRUE" ]; then
     EXTRA_V8O_ARGUMENTS="${EXTRA_V8O_ARGUMENTS} --set global.imagePullSecrets[0]=${GLOBAL_IMAGE_PULL_SECRET}"
   fi
+  if ! is_component_enabled console; then
+    EXTRA_V8O_ARGUMENTS="${EXTRA_V8O_ARGUMENTS} --set console.enabled=false"
+  fi
 
   local profile=$(get_config_value '.profile')
   if [ -z "$profile" ]; then
@@ -172,11 +184,7 @@ function install_verrazzano()
       --set config.envName=${ENV_NAME} \
       --set config.dnsSuffix=${DNS_SUFFIX} \
       --set config.enableMonitoringStorage=true \
-      --set clusterOperator.rancherURL=https://${RANCHER_HOSTNAME} \
-      --set clusterOperator.rancherUserName="${token_array[0]}" \
-      --set clusterOperator.rancherPassword="${token_array[1]}" \
-      --set clusterOperator.rancherHostname=$(get_nginx_hostip) \
-      --set clusterOperator.rancherHostPort=$(get_nginx_nodeport) \
+      ${RANCHER_ARGUMENTS} \
       --set verrazzanoAdmissionController.caBundle="$(kubectl -n ${VERRAZZANO_NS} get secret verrazzano-validation -o json | jq -r '.data."ca.crt"' | base64 --decode)" \
       ${PROFILE_VALUES_OVERRIDE} \
       ${EXTRA_V8O_ARGUMENTS} || return $?
@@ -290,8 +298,8 @@ if [ "${REGISTRY_SECRET_EXISTS}" == "TRUE" ]; then
 fi
 
 action "Creating admission controller cert" create_admission_controller_cert || exit 1
-action "Installing Verrazzano system components" install_verrazzano || exit 1
-action "Installing Coherence Kubernetes operator" install_coherence_operator || exit 1
-action "Installing WebLogic Kubernetes operator" install_weblogic_operator || exit 1
-action "Installing OAM Kubernetes operator" install_oam_operator || exit 1
-action "Installing Verrazzano Application Kubernetes operator" install_application_operator || exit 1
+install_action verrazzano "Installing Verrazzano system components" install_verrazzano || exit 1
+install_action coherence-operator "Installing Coherence Kubernetes operator" install_coherence_operator || exit 1
+install_action weblogic-operator "Installing WebLogic Kubernetes operator" install_weblogic_operator || exit 1
+install_action oam-kubernetes-runtime "Installing OAM Kubernetes operator" install_oam_operator || exit 1
+install_action verrazzano-application-operator "Installing Verrazzano Application Kubernetes operator" install_application_operator || exit 1
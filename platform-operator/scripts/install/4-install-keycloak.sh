@@ -135,7 +135,7 @@ data:
 DNS_TARGET_NAME=verrazzano-ingress.${ENV_NAME}.${DNS_SUFFIX}
 REGISTRY_SECRET_EXISTS=$(check_registry_secret_exists)
 
-action "Installing MySQL" install_mysql
+install_action mysql "Installing MySQL" install_mysql
   if [ "$?" -ne 0 ]; then
     "$SCRIPT_DIR"/k8s-dump-objects.sh -o "pods" -n "${KEYCLOAK_NS}" -m "install_mysql"
     "$SCRIPT_DIR"/k8s-dump-objects.sh -o "jobs" -n "${KEYCLOAK_NS}" -m "install_mysql"
@@ -144,7 +144,7 @@ action "Installing MySQL" install_mysql
     fail "Installation of MySQL failed"
   fi
 
-action "Installing Keycloak" install_keycloak || exit 1
+install_action keycloak "Installing Keycloak" install_keycloak || exit 1
 
 rm -rf $TMP_DIR
 
@@ -188,6 +188,72 @@ function call_curl {
   return 1
 }
 
+# The components that are installed by the install scripts, in install order.  The position of a component in
+# the list is reported as the progress of the install.
+INSTALL_PROGRESS_COMPONENTS=(istio coredns ingress-controller cert-manager external-dns rancher verrazzano
+  coherence-operator weblogic-operator oam-kubernetes-runtime verrazzano-application-operator mysql keycloak)
+
+# Report the progress of the install by writing it to the install progress ConfigMap, which is read by the
+# Verrazzano platform operator.  Nothing is reported if INSTALL_PROGRESS_CONFIGMAP is not set.
+# A failure to report the progress is logged and does not fail the install.
+# $1 the component that is being installed
+# $2 the install step
+# $3 the error of the install step, if the step failed
+function report_progress() {
+  if [ -z "${INSTALL_PROGRESS_CONFIGMAP:-}" ]; then
+    return 0
+  fi
+  local component=$1
+  local step=$2
+  local step_error=${3:-}
+  local index=0
+  local i
+  for i in "${!INSTALL_PROGRESS_COMPONENTS[@]}"; do
+    if [ "${INSTALL_PROGRESS_COMPONENTS[$i]}" == "${component}" ]; then
+      index=$((i+1))
+      break
+    fi
+  done
+
+  kubectl create configmap ${INSTALL_PROGRESS_CONFIGMAP} -n ${INSTALL_PROGRESS_NAMESPACE} \
+      --from-literal=component="${component}" \
+      --from-literal=step="${step}" \
+      --from-literal=index="${index}" \
+      --from-literal=total="${#INSTALL_PROGRESS_COMPONENTS[@]}" \
+      --from-literal=error="${step_error}" \
+      --from-literal=time="$(date -u '+%Y-%m-%dT%H:%M:%SZ')" \
+      --dry-run=client -o yaml \
+    | kubectl label --local -f - install.verrazzano.io/install-progress=${INSTALL_PROGRESS_OWNER} -o yaml \
+    | kubectl apply -f - \
+    || log "Failed to report the install progress for ${component}"
+  return 0
+}
+
+# Execute an install action for a component and report the progress of the install before the action, and
+# the error if the action fails.  The last lines of the log file are reported as the error.  The action is
+# skipped if the component is disabled in the install config.
+# $1 the component that is being installed
+# $2 the message to be written to the console's stdout and the log file, which is reported as the install step
+# $@ the command or function to execute
+# Returns the result of the action
+function install_action() {
+  local component=$1
+  local msg=$2
+  shift 2
+  local rc
+
+  if ! is_component_enabled "${component}"; then
+    log "Skipping ${msg}, ${component} is disabled"
+    return 0
+  fi
+  report_progress "${component}" "${msg}"
+  action "${msg}" "$@" && rc=0 || rc=$?
+  if [ $rc -ne 0 ]; then
+    report_progress "${component}" "${msg}" "$(tail -n 10 ${LOG_FILE} | cut -c1-200)"
+  fi
+  return $rc
+}
+
 
 VERRAZZANO_DIR=${SCRIPT_DIR}/.verrazzano
 
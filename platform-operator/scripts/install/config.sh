@@ -57,6 +57,14 @@ function get_config_array() {
   return 0
 }
 
+# is_component_enabled returns success unless the component is one of the disabled components of the config.
+# Components are disabled by the install profile or the Verrazzano resource.
+# $1 the name of the component
+function is_component_enabled() {
+  local component=$1
+  echo "$CONFIG_JSON" | jq -e --arg component "${component}" '(.disabledComponents // []) | index($component) == null' > /dev/null
+}
+
 function validate_certificates_section() {
   set -o pipefail
   local jsonToValidate=$1